@@ -1,11 +1,23 @@
 package config
 
 import (
+	"time"
+
 	"github.com/kelseyhightower/envconfig"
 )
 
 type Config struct {
-	HttpPort string `envconfig:"HTTP_PORT" default:"8080"`
+	HttpPort        string `envconfig:"HTTP_PORT" default:"8080"`
+	DefaultCurrency string `envconfig:"DEFAULT_CURRENCY" default:"USD"`
+	TaxMode         string `envconfig:"TAX_MODE" default:"exclusive"`
+	// how long reserved stock is held
+	ReservationTTL time.Duration `envconfig:"RESERVATION_TTL" default:"15m"`
+	// how often expired reservations are released
+	ReservationSweepInterval time.Duration `envconfig:"RESERVATION_SWEEP_INTERVAL" default:"1m"`
+	// sequential or best-price
+	PromotionMode string `envconfig:"PROMOTION_MODE" default:"sequential"`
+	// maximum number of unit allocations evaluated for each product in best-price mode
+	PromotionSearchBound int `envconfig:"PROMOTION_SEARCH_BOUND" default:"1000"`
 }
 
 func Get() Config {
@@ -0,0 +1,43 @@
+package entity
+
+import "time"
+
+// persistent shopping cart
+type Cart struct {
+	ID int64
+	// ISO 4217 currency code of checkout
+	Currency string
+	// order created from this cart, 0 for open cart
+	OrderID   int64
+	CreatedAt time.Time
+	UpdatedAt time.Time
+	Items     []*CartItem `gorm:"foreignKey:CartID"`
+}
+
+// is cart already checked out
+func (e *Cart) CheckedOut() bool {
+	return e.OrderID != 0
+}
+
+// create checkout request from cart items
+func (e *Cart) ToCheckoutRequest() *CheckoutRequest {
+	result := CheckoutRequest{
+		Items:    make(MapProductSerialQuantity),
+		Currency: e.Currency,
+		CartID:   e.ID,
+	}
+	for _, item := range e.Items {
+		result.Items[item.Product.Serial] += item.Quantity
+	}
+	return &result
+}
+
+// product quantity in cart, unique by cart and product
+type CartItem struct {
+	ID        int64
+	CartID    int64
+	ProductID int64
+	Quantity  int
+	UpdatedAt time.Time
+	Product   *Product `gorm:"foreignKey:ProductID"`
+}
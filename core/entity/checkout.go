@@ -1,5 +1,7 @@
 package entity
 
+import "time"
+
 type MapProductSerialQuantity map[string]int
 
 func (e MapProductSerialQuantity) PluckSerial() []string {
@@ -10,14 +12,66 @@ func (e MapProductSerialQuantity) PluckSerial() []string {
 	return result
 }
 
+// checkout request from user
+type CheckoutRequest struct {
+	Items MapProductSerialQuantity
+	// ISO 4217 currency code, empty for default currency
+	Currency string
+	// reservation to be converted into sale, 0 for checkout without reservation
+	ReservationID int64
+	// cart to be closed by the order, 0 for checkout without cart
+	CartID int64
+	// skip unknown serials instead of rejecting the request
+	Lenient bool
+	// voucher code, empty for checkout without coupon
+	CouponCode string
+	// customer who redeems the coupon, required by coupon with per customer limit
+	CustomerID string
+}
+
 type CheckoutItem struct {
 	Product       *Product
 	Quantity      int
-	SubTotalPrice float64
+	SubTotalPrice Money
+	// promotions applied to this item
+	AppliedPromotions []*AppliedPromotion
+	// share of coupon discount, already deducted from sub total price
+	CouponDiscount Money
+	// tax of sub total price, calculated after promotions
+	TaxAmount Money
+	// current stock of product, only filled when quoting checkout
+	AvailableQuantity int
+}
+
+// is current stock enough for this item
+func (e *CheckoutItem) InStock() bool {
+	return e.AvailableQuantity >= e.Quantity
 }
 
 type Checkout struct {
-	Items      []*CheckoutItem
-	TotalItem  int
-	TotalPrice float64
+	Items         []*CheckoutItem
+	TotalItem     int
+	TotalPrice    Money
+	TotalDiscount Money
+	// total tax of all items
+	TaxTotal Money
+	// total to be paid, TotalPrice plus TaxTotal when tax is exclusive
+	GrandTotal Money
+	Currency   string
+	// reservation to be converted into sale, only for submit
+	ReservationID int64
+	// time of submit, reservation must be active at this time, only for submit with reservation
+	SubmittedAt time.Time
+	// cart to be closed by the order, only for submit
+	CartID int64
+	// serials skipped by lenient request
+	UnknownSerials []string
+	// cart level promotions, share of each discount is also recorded in applied promotions of items
+	Adjustments []*AppliedPromotion
+	// coupon applied after promotions, nil for checkout without coupon
+	Coupon *Coupon
+	// discount of coupon, included in TotalDiscount
+	CouponDiscount Money
+	// customer who redeems the coupon
+	CustomerID string
 }
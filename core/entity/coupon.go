@@ -0,0 +1,88 @@
+package entity
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type CouponType int
+
+const (
+	UndefinedCouponType CouponType = iota
+	// fixed amount off the order total
+	CouponFixedAmount
+	// percent off the order total
+	CouponPercent
+)
+
+// voucher code entered by customer at checkout
+type Coupon struct {
+	ID int64
+	// stored in upper case
+	Code string
+	Type CouponType
+	// amount off for fixed amount coupon
+	Amount Money
+	// percent off for percent coupon
+	Percent int
+	// minimum total price after promotions, 0 for no minimum
+	MinTotal Money
+	// currency of Amount and MinTotal, coupon is only valid for checkout in this currency
+	Currency string
+	// maximum redemptions of all customers, 0 for unlimited
+	UsageLimit int
+	// maximum redemptions of each customer, 0 for unlimited
+	PerCustomerLimit int
+	// number of redemptions, only increased by submit checkout
+	UsedCount int
+	// coupon is active from StartsAt (inclusive) until EndsAt (exclusive)
+	// nil means no limit
+	StartsAt  *time.Time
+	EndsAt    *time.Time
+	UpdatedAt time.Time
+	DeletedAt gorm.DeletedAt
+}
+
+// is coupon active at the time
+func (e *Coupon) ActiveAt(at time.Time) bool {
+	if e.StartsAt != nil && at.Before(*e.StartsAt) {
+		return false
+	}
+	if e.EndsAt != nil && !at.Before(*e.EndsAt) {
+		return false
+	}
+	return true
+}
+
+// is usage limit of all customers reached
+func (e *Coupon) UsageLimitReached() bool {
+	return e.UsageLimit > 0 && e.UsedCount >= e.UsageLimit
+}
+
+// get discount of total price, discount never exceeds total price
+func (e *Coupon) Discount(total Money) Money {
+	var result Money
+	switch e.Type {
+	case CouponFixedAmount:
+		result = e.Amount
+	case CouponPercent:
+		result = total.Percent(e.Percent)
+	}
+	if result > total {
+		return total
+	}
+	if result < 0 {
+		return 0
+	}
+	return result
+}
+
+// coupon used by an order
+type CouponRedemption struct {
+	ID         int64
+	CouponID   int64
+	OrderID    int64
+	CustomerID string
+	CreatedAt  time.Time
+}
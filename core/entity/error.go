@@ -1,13 +1,82 @@
 package entity
 
+import (
+	"net/http"
+	"strings"
+)
+
+const (
+	ProductNotFound   string = "product not found"
+	EmptyQuantity     string = "empty quantity"
+	OrderNotFound     string = "order not found"
+	InvalidCurrency   string = "invalid currency"
+	PromotionNotFound string = "promotion not found"
+	SerialAlreadyUsed string = "serial already used"
+	// reservation is not found, already consumed or expired
+	ReservationNotActive string = "reservation is not active"
+	CartNotFound         string = "cart not found"
+	CartCheckedOut       string = "cart is already checked out"
+	EmptyCart            string = "cart is empty"
+	CouponNotFound       string = "coupon not found"
+	// coupon is outside its validity window
+	CouponNotActive string = "coupon is not active"
+	// checkout does not meet coupon currency or minimum total
+	CouponNotApplicable        string = "coupon is not applicable"
+	CouponUsageLimitReached    string = "coupon usage limit is reached"
+	CouponCustomerLimitReached string = "coupon usage limit of customer is reached"
+)
+
+// stable machine readable error code, client must not parse error message
+type ErrorCode string
+
 const (
-	ProductNotFound string = "product not found"
-	EmptyQuantity   string = "empty quantity"
+	CodeValidationFailed           ErrorCode = "VALIDATION_FAILED"
+	CodeNotFound                   ErrorCode = "NOT_FOUND"
+	CodeInternalError              ErrorCode = "INTERNAL_ERROR"
+	CodeOutOfStock                 ErrorCode = "OUT_OF_STOCK"
+	CodeProductNotFound            ErrorCode = "PRODUCT_NOT_FOUND"
+	CodeEmptyQuantity              ErrorCode = "EMPTY_QUANTITY"
+	CodeOrderNotFound              ErrorCode = "ORDER_NOT_FOUND"
+	CodeInvalidCurrency            ErrorCode = "INVALID_CURRENCY"
+	CodePromotionNotFound          ErrorCode = "PROMOTION_NOT_FOUND"
+	CodeSerialAlreadyUsed          ErrorCode = "SERIAL_ALREADY_USED"
+	CodeReservationNotActive       ErrorCode = "RESERVATION_NOT_ACTIVE"
+	CodeCartNotFound               ErrorCode = "CART_NOT_FOUND"
+	CodeCartCheckedOut             ErrorCode = "CART_CHECKED_OUT"
+	CodeEmptyCart                  ErrorCode = "EMPTY_CART"
+	CodeCouponNotFound             ErrorCode = "COUPON_NOT_FOUND"
+	CodeCouponNotActive            ErrorCode = "COUPON_NOT_ACTIVE"
+	CodeCouponNotApplicable        ErrorCode = "COUPON_NOT_APPLICABLE"
+	CodeCouponUsageLimitReached    ErrorCode = "COUPON_USAGE_LIMIT_REACHED"
+	CodeCouponCustomerLimitReached ErrorCode = "COUPON_CUSTOMER_LIMIT_REACHED"
 )
 
+// error code of error message constants
+var messageCodes = map[string]ErrorCode{
+	ProductNotFound:            CodeProductNotFound,
+	EmptyQuantity:              CodeEmptyQuantity,
+	OrderNotFound:              CodeOrderNotFound,
+	InvalidCurrency:            CodeInvalidCurrency,
+	PromotionNotFound:          CodePromotionNotFound,
+	SerialAlreadyUsed:          CodeSerialAlreadyUsed,
+	ReservationNotActive:       CodeReservationNotActive,
+	CartNotFound:               CodeCartNotFound,
+	CartCheckedOut:             CodeCartCheckedOut,
+	EmptyCart:                  CodeEmptyCart,
+	CouponNotFound:             CodeCouponNotFound,
+	CouponNotActive:            CodeCouponNotActive,
+	CouponNotApplicable:        CodeCouponNotApplicable,
+	CouponUsageLimitReached:    CodeCouponUsageLimitReached,
+	CouponCustomerLimitReached: CodeCouponCustomerLimitReached,
+}
+
 type Err struct {
 	message string
 	code    int
+	// machine readable code of the error
+	errorCode ErrorCode
+	// additional information for client, eg: list of unknown serials
+	details map[string]interface{}
 }
 
 func (e Err) Error() string {
@@ -22,6 +91,62 @@ func (e Err) GetMessage() string {
 	return e.message
 }
 
+func (e Err) GetErrorCode() ErrorCode {
+	return e.errorCode
+}
+
+func (e Err) GetDetails() map[string]interface{} {
+	return e.details
+}
+
+// error code is taken from message constant, or from http code for other messages
 func NewError(msg string, code int) Err {
-	return Err{message: msg, code: code}
+	return Err{message: msg, code: code, errorCode: messageErrorCode(msg, code)}
+}
+
+func NewErrorWithDetails(msg string, code int, details map[string]interface{}) Err {
+	return Err{message: msg, code: code, errorCode: messageErrorCode(msg, code), details: details}
+}
+
+// item quantity that exceeds available stock
+type OutOfStockItem struct {
+	Serial    string `json:"serial"`
+	Requested int    `json:"requested"`
+	Available int    `json:"available"`
+}
+
+// error of all items that exceed available stock
+func NewOutOfStockError(msg string, items []*OutOfStockItem) Err {
+	return Err{
+		message:   msg,
+		code:      http.StatusBadRequest,
+		errorCode: CodeOutOfStock,
+		details: map[string]interface{}{
+			"items": items,
+		},
+	}
+}
+
+// default error code of http code, eg: 405 is METHOD_NOT_ALLOWED
+func ErrorCodeFromStatus(code int) ErrorCode {
+	switch code {
+	case http.StatusBadRequest:
+		return CodeValidationFailed
+	case http.StatusNotFound:
+		return CodeNotFound
+	case http.StatusInternalServerError:
+		return CodeInternalError
+	}
+	text := http.StatusText(code)
+	if text == "" {
+		return CodeInternalError
+	}
+	return ErrorCode(strings.ToUpper(strings.ReplaceAll(text, " ", "_")))
+}
+
+func messageErrorCode(msg string, code int) ErrorCode {
+	if result, ok := messageCodes[msg]; ok {
+		return result
+	}
+	return ErrorCodeFromStatus(code)
 }
@@ -0,0 +1,38 @@
+package entity_test
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/gendutski/be-candidate-home-test/core/entity"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_ErrorCode(t *testing.T) {
+	t.Run("error code of message constant", func(t *testing.T) {
+		err := entity.NewError(entity.ProductNotFound, http.StatusNotFound)
+		assert.Equal(t, entity.CodeProductNotFound, err.GetErrorCode())
+		assert.Equal(t, http.StatusNotFound, err.GetCode())
+	})
+
+	t.Run("error code of http code", func(t *testing.T) {
+		for code, expected := range map[int]entity.ErrorCode{
+			http.StatusBadRequest:          entity.CodeValidationFailed,
+			http.StatusNotFound:            entity.CodeNotFound,
+			http.StatusInternalServerError: entity.CodeInternalError,
+			http.StatusMethodNotAllowed:    "METHOD_NOT_ALLOWED",
+			999:                            entity.CodeInternalError,
+		} {
+			err := entity.NewError("connection refused", code)
+			assert.Equal(t, expected, err.GetErrorCode(), code)
+		}
+	})
+
+	t.Run("out of stock with details", func(t *testing.T) {
+		items := []*entity.OutOfStockItem{{Serial: "43N23P", Requested: 6, Available: 5}}
+		err := entity.NewOutOfStockError("out of stock", items)
+		assert.Equal(t, entity.CodeOutOfStock, err.GetErrorCode())
+		assert.Equal(t, http.StatusBadRequest, err.GetCode())
+		assert.Equal(t, map[string]interface{}{"items": items}, err.GetDetails())
+	})
+}
@@ -0,0 +1,242 @@
+package entity
+
+import (
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"math"
+	"math/bits"
+	"strconv"
+	"strings"
+)
+
+// number of minor units in one major unit, money is stored with 2 decimals
+const moneyScale int64 = 100
+
+// Money is an amount in minor units, eg: 5399.99 is stored as 539999
+// all price arithmetic must use Money to avoid floating point drift
+type Money int64
+
+var errInvalidMoney = errors.New("invalid money value")
+
+// create money from float, rounded to the nearest minor unit
+// only use this for values that are already rounded to 2 decimals, eg: from database driver
+func NewMoneyFromFloat(f float64) Money {
+	return Money(math.Round(f * float64(moneyScale)))
+}
+
+// parse decimal string exactly, eg: "5399.99", "-0.5", "30"
+// more than 2 decimals is rejected
+func ParseMoney(s string) (Money, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return 0, errInvalidMoney
+	}
+
+	negative := false
+	if s[0] == '-' || s[0] == '+' {
+		negative = s[0] == '-'
+		s = s[1:]
+	}
+
+	whole, fraction, _ := strings.Cut(s, ".")
+	if whole == "" && fraction == "" {
+		return 0, errInvalidMoney
+	}
+	if len(fraction) > 2 || !isDigits(whole) || !isDigits(fraction) {
+		return 0, errInvalidMoney
+	}
+	// pad fraction to 2 digits
+	fraction += strings.Repeat("0", 2-len(fraction))
+
+	var major, minor int64
+	var err error
+	if whole != "" {
+		if major, err = strconv.ParseInt(whole, 10, 64); err != nil {
+			return 0, errInvalidMoney
+		}
+	}
+	if minor, err = strconv.ParseInt(fraction, 10, 64); err != nil {
+		return 0, errInvalidMoney
+	}
+	// major in minor unit must not overflow
+	if major > (math.MaxInt64-minor)/moneyScale {
+		return 0, errInvalidMoney
+	}
+
+	result := Money(major*moneyScale + minor)
+	if negative {
+		result = -result
+	}
+	return result, nil
+}
+
+// check string has only digits 0-9, sign is not allowed
+func isDigits(s string) bool {
+	for _, c := range s {
+		if c < '0' || c > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+// multiply money with quantity
+func (m Money) Multiply(quantity int) Money {
+	return m * Money(quantity)
+}
+
+// get percent value of money
+// rounding rule: half away from zero to the nearest minor unit, eg: 10% of 0.05 is 0.01
+func (m Money) Percent(percent int) Money {
+	value := int64(m) * int64(percent)
+	if value < 0 {
+		return -Money((-value + 50) / 100)
+	}
+	return Money((value + 50) / 100)
+}
+
+// get numerator / denominator of money
+// rounding rule is the same as Percent, eg: 2/3 of 100.00 is 66.67
+func (m Money) Ratio(numerator, denominator int) Money {
+	return Money(divRound(int64(m)*int64(numerator), int64(denominator)))
+}
+
+// subtract amount from money, result never goes below zero
+// eg: 10.00 off 7.50 is 0
+func (m Money) Deduct(amount Money) Money {
+	if amount >= m {
+		return 0
+	}
+	return m - amount
+}
+
+// get rate value of money, rate is in basis points (1/100 of percent)
+// rounding rule is the same as Percent, eg: 11% of 49.99 is 5.50
+func (m Money) Rate(basisPoints int) Money {
+	return Money(divRound(int64(m)*int64(basisPoints), 10000))
+}
+
+// get rate value that is already included in money, rate is in basis points
+// eg: 11% tax included in 111.00 is 11.00
+func (m Money) IncludedRate(basisPoints int) Money {
+	return Money(divRound(int64(m)*int64(basisPoints), 10000+int64(basisPoints)))
+}
+
+// split money by weights, eg: split order discount to items by sub total price
+// each share is rounded down, then remaining minor units are given one by one from the first weight
+// money is capped at total of weights, negative money or weight gets nothing
+func (m Money) Allocate(weights []Money) []Money {
+	result := make([]Money, len(weights))
+	var total Money
+	for _, weight := range weights {
+		if weight > 0 {
+			total += weight
+		}
+	}
+	if m <= 0 || total <= 0 {
+		return result
+	}
+	if m > total {
+		m = total
+	}
+
+	remaining := m
+	for i, weight := range weights {
+		if weight <= 0 {
+			continue
+		}
+		// money * weight may overflow int64, share never exceeds weight
+		hi, lo := bits.Mul64(uint64(m), uint64(weight))
+		share, _ := bits.Div64(hi, lo, uint64(total))
+		result[i] = Money(share)
+		remaining -= result[i]
+	}
+	for i := 0; remaining > 0 && i < len(weights); i++ {
+		if weights[i] > result[i] {
+			result[i]++
+			remaining--
+		}
+	}
+	return result
+}
+
+// integer division rounded half away from zero
+func divRound(value, divisor int64) int64 {
+	if value < 0 {
+		return -((-value + divisor/2) / divisor)
+	}
+	return (value + divisor/2) / divisor
+}
+
+// convert money to float, only for display purpose
+func (m Money) Float64() float64 {
+	return float64(m) / float64(moneyScale)
+}
+
+// format money with 2 decimals, eg: "5399.99"
+func (m Money) String() string {
+	sign := ""
+	value := int64(m)
+	if value < 0 {
+		sign = "-"
+		value = -value
+	}
+	return fmt.Sprintf("%s%d.%02d", sign, value/moneyScale, value%moneyScale)
+}
+
+// MarshalJSON satisfies json.Marshaler interface, money is encoded as json number
+func (m Money) MarshalJSON() ([]byte, error) {
+	return []byte(m.String()), nil
+}
+
+// UnmarshalJSON satisfies json.Unmarshaler interface, accept json number or string
+func (m *Money) UnmarshalJSON(data []byte) error {
+	value, err := ParseMoney(strings.Trim(string(data), `"`))
+	if err != nil {
+		return err
+	}
+	*m = value
+	return nil
+}
+
+// Scan satisfies sql.Scanner interface
+func (m *Money) Scan(value interface{}) error {
+	switch v := value.(type) {
+	case nil:
+		*m = 0
+	case []byte:
+		return m.scanString(string(v))
+	case string:
+		return m.scanString(v)
+	case float64:
+		*m = NewMoneyFromFloat(v)
+	case float32:
+		*m = NewMoneyFromFloat(float64(v))
+	case int64:
+		// integer value is whole major unit
+		*m = Money(v * moneyScale)
+	default:
+		return errInvalidMoney
+	}
+	return nil
+}
+
+func (m *Money) scanString(s string) error {
+	value, err := ParseMoney(s)
+	if err != nil {
+		// decimal column may have trailing zeros, eg: "30.0000"
+		f, ferr := strconv.ParseFloat(s, 64)
+		if ferr != nil {
+			return err
+		}
+		value = NewMoneyFromFloat(f)
+	}
+	*m = value
+	return nil
+}
+
+// Value satisfies driver.Valuer interface, money is stored as exact decimal string
+func (m Money) Value() (driver.Value, error) {
+	return m.String(), nil
+}
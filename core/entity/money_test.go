@@ -0,0 +1,113 @@
+package entity_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/gendutski/be-candidate-home-test/core/entity"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_ParseMoney(t *testing.T) {
+	t.Run("positive", func(t *testing.T) {
+		for input, expected := range map[string]entity.Money{
+			"5399.99":              539999,
+			"109.5":                10950,
+			"30":                   3000,
+			".5":                   50,
+			"-0.05":                -5,
+			" 49.99 ":              4999,
+			"+1.50":                150,
+			"92233720368547758.07": 9223372036854775807,
+		} {
+			resp, err := entity.ParseMoney(input)
+			assert.Nil(t, err, input)
+			assert.Equal(t, expected, resp, input)
+		}
+	})
+
+	t.Run("negative, invalid value", func(t *testing.T) {
+		for _, input := range []string{"", "-", ".", "1.999", "abc", "1.-5", "1.+5", "-+5", "+-5", "1e3", "92233720368547758.08", "99999999999999999999"} {
+			_, err := entity.ParseMoney(input)
+			assert.NotNil(t, err, input)
+		}
+	})
+}
+
+func Test_MoneyPercent(t *testing.T) {
+	// rounding half away from zero
+	assert.Equal(t, entity.Money(3285), entity.Money(32850).Percent(10))
+	assert.Equal(t, entity.Money(1), entity.Money(5).Percent(10))
+	assert.Equal(t, entity.Money(0), entity.Money(4).Percent(10))
+	assert.Equal(t, entity.Money(-1), entity.Money(-5).Percent(10))
+	assert.Equal(t, entity.Money(539999), entity.Money(539999).Percent(100))
+}
+
+func Test_MoneyRatio(t *testing.T) {
+	assert.Equal(t, entity.Money(6667), entity.Money(10000).Ratio(2, 3))
+	assert.Equal(t, entity.Money(9998), entity.Money(14997).Ratio(2, 3))
+	assert.Equal(t, entity.Money(0), entity.Money(4999).Ratio(0, 3))
+}
+
+func Test_MoneyDeduct(t *testing.T) {
+	assert.Equal(t, entity.Money(3999), entity.Money(4999).Deduct(1000))
+	// result never goes below zero
+	assert.Equal(t, entity.Money(0), entity.Money(750).Deduct(1000))
+	assert.Equal(t, entity.Money(0), entity.Money(0).Deduct(1000))
+}
+
+func Test_MoneyRate(t *testing.T) {
+	// rate in basis points
+	assert.Equal(t, entity.Money(550), entity.Money(4999).Rate(1100))
+	assert.Equal(t, entity.Money(36), entity.Money(500).Rate(725))
+	assert.Equal(t, entity.Money(0), entity.Money(4999).Rate(0))
+	// rate included in money
+	assert.Equal(t, entity.Money(1100), entity.Money(11100).IncludedRate(1100))
+	assert.Equal(t, entity.Money(495), entity.Money(4999).IncludedRate(1100))
+	assert.Equal(t, entity.Money(0), entity.Money(4999).IncludedRate(0))
+}
+
+func Test_MoneyAllocate(t *testing.T) {
+	// remaining minor units are given from the first weight
+	assert.Equal(t, []entity.Money{34, 33, 33}, entity.Money(100).Allocate([]entity.Money{100, 100, 100}))
+	assert.Equal(t, []entity.Money{750, 0, 250}, entity.Money(1000).Allocate([]entity.Money{3000, 0, 1000}))
+	// money is capped at total of weights
+	assert.Equal(t, []entity.Money{4999, 3000}, entity.Money(10000).Allocate([]entity.Money{4999, 3000}))
+	// large values do not overflow
+	assert.Equal(t, []entity.Money{4000000000000, 1000000000000}, entity.Money(5000000000000).Allocate([]entity.Money{8000000000000, 2000000000000}))
+	assert.Equal(t, []entity.Money{0, 0}, entity.Money(0).Allocate([]entity.Money{4999, 3000}))
+}
+
+func Test_MoneyJSON(t *testing.T) {
+	data, err := json.Marshal(struct {
+		Price entity.Money `json:"price"`
+	}{Price: 29565})
+	assert.Nil(t, err)
+	assert.Equal(t, `{"price":295.65}`, string(data))
+
+	var resp struct {
+		Price entity.Money `json:"price"`
+		Total entity.Money `json:"total"`
+	}
+	err = json.Unmarshal([]byte(`{"price":295.65,"total":"0.1"}`), &resp)
+	assert.Nil(t, err)
+	assert.Equal(t, entity.Money(29565), resp.Price)
+	assert.Equal(t, entity.Money(10), resp.Total)
+}
+
+func Test_MoneyScan(t *testing.T) {
+	var m entity.Money
+	assert.Nil(t, m.Scan([]byte("5399.99")))
+	assert.Equal(t, entity.Money(539999), m)
+	assert.Nil(t, m.Scan(49.99))
+	assert.Equal(t, entity.Money(4999), m)
+	assert.Nil(t, m.Scan(int64(30)))
+	assert.Equal(t, entity.Money(3000), m)
+	assert.Nil(t, m.Scan("109.5000"))
+	assert.Equal(t, entity.Money(10950), m)
+	assert.NotNil(t, m.Scan(true))
+
+	value, err := entity.Money(-5).Value()
+	assert.Nil(t, err)
+	assert.Equal(t, "-0.05", value)
+}
@@ -0,0 +1,96 @@
+package entity
+
+import "time"
+
+type OrderItem struct {
+	ID                int64
+	OrderID           int64
+	ProductID         int64
+	Quantity          int
+	Price             Money
+	SubTotalPrice     Money
+	TaxAmount         Money
+	AppliedPromotions AppliedPromotionList
+	// share of coupon discount, already deducted from sub total price
+	CouponDiscount Money
+	Product        *Product `gorm:"foreignKey:ProductID"`
+}
+
+type Order struct {
+	ID            int64
+	TotalItem     int
+	TotalPrice    Money
+	TotalDiscount Money
+	TaxTotal      Money
+	GrandTotal    Money
+	Currency      string
+	// cart level promotions applied to the order
+	Adjustments AppliedPromotionList
+	// customer who redeems the coupon, empty for anonymous checkout
+	CustomerID string
+	// coupon used by the order, 0 for order without coupon
+	CouponID       int64
+	CouponCode     string
+	CouponDiscount Money
+	CreatedAt      time.Time
+	Items          []*OrderItem `gorm:"foreignKey:OrderID"`
+	// serials skipped by lenient request, not stored
+	UnknownSerials []string `gorm:"-"`
+}
+
+// create new order from checkout
+// price of each item is taken from current product price
+func NewOrder(checkout *Checkout) *Order {
+	result := Order{
+		TotalItem:      checkout.TotalItem,
+		TotalPrice:     checkout.TotalPrice,
+		TotalDiscount:  checkout.TotalDiscount,
+		TaxTotal:       checkout.TaxTotal,
+		GrandTotal:     checkout.GrandTotal,
+		Currency:       checkout.Currency,
+		Adjustments:    checkout.Adjustments,
+		UnknownSerials: checkout.UnknownSerials,
+		CustomerID:     checkout.CustomerID,
+		CouponDiscount: checkout.CouponDiscount,
+	}
+	if checkout.Coupon != nil {
+		result.CouponID = checkout.Coupon.ID
+		result.CouponCode = checkout.Coupon.Code
+	}
+	for _, item := range checkout.Items {
+		result.Items = append(result.Items, &OrderItem{
+			ProductID:         item.Product.ID,
+			Quantity:          item.Quantity,
+			Price:             item.Product.Price,
+			SubTotalPrice:     item.SubTotalPrice,
+			TaxAmount:         item.TaxAmount,
+			AppliedPromotions: item.AppliedPromotions,
+			CouponDiscount:    item.CouponDiscount,
+			Product:           item.Product,
+		})
+	}
+	return &result
+}
+
+// filter for order listing
+// zero value field is ignored
+type OrderFilter struct {
+	// created_at >= CreatedFrom
+	CreatedFrom time.Time
+	// created_at < CreatedTo
+	CreatedTo time.Time
+	// order contains product with this serial
+	ProductSerial string
+	MinTotalPrice *Money
+	MaxTotalPrice *Money
+	Page          int
+	Limit         int
+}
+
+// offset of current page, page start from 1
+func (e OrderFilter) Offset() int {
+	if e.Page <= 1 {
+		return 0
+	}
+	return (e.Page - 1) * e.Limit
+}
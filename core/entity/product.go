@@ -1,18 +1,60 @@
 package entity
 
-import "time"
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// max price of decimal(10,2) column, in minor units
+const MaxPrice Money = 9999999999
 
 type Product struct {
-	ID        int64
-	Serial    string
-	Name      string
-	Price     float64
+	ID     int64
+	Serial string
+	Name   string
+	Price  Money
+	// tax category for tax rate, empty is DefaultTaxCategory
+	TaxCategory string
+	// category for category bundle promotion, empty for uncategorized
+	Category  string
 	UpdatedAt time.Time
+	DeletedAt gorm.DeletedAt
 }
 
 type ProductQuantity struct {
 	ID        int64
 	ProductID int64
 	Quantity  int
+	// quantity held by active reservations
+	Reserved  int
+	UpdatedAt time.Time
+}
+
+// quantity that can be sold or reserved
+func (e *ProductQuantity) Available() int {
+	return e.Quantity - e.Reserved
+}
+
+// price of product in other currency
+type ProductPrice struct {
+	ID        int64
+	ProductID int64
+	Currency  string
+	Price     Money
 	UpdatedAt time.Time
 }
+
+// filter for product listing
+type ProductFilter struct {
+	Page  int
+	Limit int
+}
+
+// offset of current page, page start from 1
+func (e ProductFilter) Offset() int {
+	if e.Page <= 1 {
+		return 0
+	}
+	return (e.Page - 1) * e.Limit
+}
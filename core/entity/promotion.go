@@ -1,6 +1,9 @@
 package entity
 
 import (
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
 	"time"
 
 	"gorm.io/gorm"
@@ -14,6 +17,33 @@ const (
 	BuyItemsForReducePrice
 	DiscountInPercent
 	FreeItem
+	// cart level promotions, evaluated after promotions of each product
+	// any MatchQuantity items of Category for PromoPrice
+	CategoryBundle
+	// PromoValue items of PromoProductID for free when total price reaches MinTotal
+	CartBonusItem
+	// PromoValue percent discount of total price when total price reaches MinTotal
+	CartDiscountInPercent
+	// Items of different products together for PromoPrice, or PromoValue percent discount of them when PromoPrice is empty
+	ProductBundle
+	// price of product by tier of purchased quantity, eg: 10 to 49 items get 5% discount, 50 items or more get 12% discount
+	TieredPrice
+	// PromoPrice off each item when purchased quantity reaches MatchQuantity
+	DiscountInAmount
+	// PromoPrice off sub total of the product, once when purchased quantity reaches MatchQuantity
+	LineDiscountInAmount
+	// PromoPrice as price of each item when purchased quantity reaches MatchQuantity
+	FixedUnitPrice
+)
+
+// how promotions of a product are applied at checkout
+type PromotionMode string
+
+const (
+	// promotions are applied to the whole quantity in order of priority
+	PromotionSequential PromotionMode = "sequential"
+	// units are allocated to promotions to get the lowest total price
+	PromotionBestPrice PromotionMode = "best-price"
 )
 
 type Promotion struct {
@@ -23,6 +53,195 @@ type Promotion struct {
 	MatchQuantity  int
 	PromoValue     int
 	PromoProductID int64
-	UpdatedAt      time.Time
-	DeletedAt      gorm.DeletedAt
+	// minimum total price after product promotions, only for cart level promotion
+	MinTotal Money
+	// price of bundle for category bundle and product bundle
+	// amount of discount in amount, line discount in amount and fixed unit price
+	PromoPrice Money
+	// product category, only for category bundle
+	Category string
+	// products of bundle with required quantity, only for product bundle
+	Items []*PromotionItem `gorm:"foreignKey:PromotionID"`
+	// tiers of tiered price, sorted by min quantity
+	Tiers []*PromotionTier `gorm:"foreignKey:PromotionID"`
+	// currency of MinTotal and PromoPrice, cart level promotion is only applied to checkout in this currency
+	// tiered price with unit price and fixed amount promotions are also only applied to checkout in this currency
+	Currency string
+	// promotions of a product are evaluated from the lowest priority, only for product promotion
+	Priority int
+	// exclusive promotion is never stacked, it competes alone with other promotions of the product
+	Exclusive bool
+	// id of promotions that can be stacked with this promotion, empty for any non exclusive promotion
+	StackableWith PromotionIDList
+	// promotion is active from StartsAt (inclusive) until EndsAt (exclusive)
+	// nil means no limit
+	StartsAt  *time.Time
+	EndsAt    *time.Time
+	UpdatedAt time.Time
+	DeletedAt gorm.DeletedAt
+}
+
+// product of product bundle with its required quantity
+type PromotionItem struct {
+	ID          int64
+	PromotionID int64
+	ProductID   int64
+	Quantity    int
+}
+
+// tier of tiered price, applied when purchased quantity reaches MinQuantity until next tier
+// either Percent discount or UnitPrice is set
+type PromotionTier struct {
+	ID          int64
+	PromotionID int64
+	MinQuantity int
+	Percent     int
+	// unit price in promotion currency
+	UnitPrice Money
+}
+
+// get tier of purchased quantity, tiers must be sorted by min quantity
+// return nil if quantity is below the first tier
+func (e *Promotion) TierOf(quantity int) *PromotionTier {
+	var result *PromotionTier
+	for _, tier := range e.Tiers {
+		if quantity < tier.MinQuantity {
+			break
+		}
+		result = tier
+	}
+	return result
+}
+
+// is promotion active at the time
+func (e *Promotion) ActiveAt(at time.Time) bool {
+	if e.StartsAt != nil && at.Before(*e.StartsAt) {
+		return false
+	}
+	if e.EndsAt != nil && !at.Before(*e.EndsAt) {
+		return false
+	}
+	return true
+}
+
+// is promotion applied to the whole cart instead of a product
+func (e *Promotion) IsCartLevel() bool {
+	switch e.Type {
+	case CategoryBundle, CartBonusItem, CartDiscountInPercent, ProductBundle:
+		return true
+	}
+	return false
+}
+
+// is PromoPrice an amount of product promotion in promotion currency
+func (e *Promotion) IsFixedAmount() bool {
+	switch e.Type {
+	case DiscountInAmount, LineDiscountInAmount, FixedUnitPrice:
+		return true
+	}
+	return false
+}
+
+// can promotion be applied together with other promotion
+// both promotions must allow each other
+func (e *Promotion) CanStackWith(other *Promotion) bool {
+	if e.Exclusive || other.Exclusive {
+		return false
+	}
+	return e.StackableWith.Allows(other.ID) && other.StackableWith.Allows(e.ID)
+}
+
+// list of promotion id stored as json array in database
+type PromotionIDList []int64
+
+// empty list allows any promotion
+func (e PromotionIDList) Allows(id int64) bool {
+	if len(e) == 0 {
+		return true
+	}
+	for _, allowed := range e {
+		if allowed == id {
+			return true
+		}
+	}
+	return false
+}
+
+// Scan satisfies sql.Scanner interface
+func (e *PromotionIDList) Scan(value interface{}) error {
+	var data []byte
+	switch v := value.(type) {
+	case nil:
+		*e = nil
+		return nil
+	case []byte:
+		data = v
+	case string:
+		data = []byte(v)
+	default:
+		return errors.New("invalid promotion id list value")
+	}
+	if len(data) == 0 || string(data) == "[]" {
+		*e = nil
+		return nil
+	}
+	return json.Unmarshal(data, e)
+}
+
+// Value satisfies driver.Valuer interface
+func (e PromotionIDList) Value() (driver.Value, error) {
+	if e == nil {
+		return "[]", nil
+	}
+	data, err := json.Marshal(e)
+	if err != nil {
+		return nil, err
+	}
+	return string(data), nil
+}
+
+// promotion applied to checkout item
+type AppliedPromotion struct {
+	PromotionID int64         `json:"promotionId"`
+	Type        PromotionType `json:"type"`
+	// reduced price of the item by this promotion
+	DiscountAmount Money `json:"discountAmount"`
+	// number of free items given by this promotion
+	FreeQuantity int `json:"freeQuantity"`
+}
+
+// list of applied promotion stored as json array in database
+type AppliedPromotionList []*AppliedPromotion
+
+// Scan satisfies sql.Scanner interface
+func (e *AppliedPromotionList) Scan(value interface{}) error {
+	var data []byte
+	switch v := value.(type) {
+	case nil:
+		*e = nil
+		return nil
+	case []byte:
+		data = v
+	case string:
+		data = []byte(v)
+	default:
+		return errors.New("invalid applied promotion list value")
+	}
+	if len(data) == 0 {
+		*e = nil
+		return nil
+	}
+	return json.Unmarshal(data, e)
+}
+
+// Value satisfies driver.Valuer interface
+func (e AppliedPromotionList) Value() (driver.Value, error) {
+	if e == nil {
+		return "[]", nil
+	}
+	data, err := json.Marshal(e)
+	if err != nil {
+		return nil, err
+	}
+	return string(data), nil
 }
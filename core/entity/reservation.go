@@ -0,0 +1,33 @@
+package entity
+
+import "time"
+
+type ReservationStatus string
+
+const (
+	// stock is held until expires at
+	ReservationActive ReservationStatus = "active"
+	// reserved stock is converted into sale by checkout
+	ReservationConsumed ReservationStatus = "consumed"
+	// reserved stock is released after expired
+	ReservationReleased ReservationStatus = "released"
+)
+
+// stock held for in progress cart
+type Reservation struct {
+	ID        int64
+	Status    ReservationStatus
+	ExpiresAt time.Time
+	CreatedAt time.Time
+	Items     []*ReservationItem `gorm:"foreignKey:ReservationID"`
+	// serials skipped by lenient request, not stored
+	UnknownSerials []string `gorm:"-"`
+}
+
+type ReservationItem struct {
+	ID            int64
+	ReservationID int64
+	ProductID     int64
+	Quantity      int
+	Product       *Product `gorm:"foreignKey:ProductID"`
+}
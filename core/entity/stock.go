@@ -0,0 +1,47 @@
+package entity
+
+import "time"
+
+type StockMovementReason string
+
+const (
+	// initial quantity when product is created
+	StockInitial StockMovementReason = "initial"
+	// items sold by checkout
+	StockSale StockMovementReason = "sale"
+	// items received from supplier
+	StockRestock StockMovementReason = "restock"
+	// items returned by customer
+	StockReturn StockMovementReason = "return"
+	// manual correction, eg: after stock opname or damaged items
+	StockCorrection StockMovementReason = "correction"
+)
+
+// every change of product quantity
+type StockMovement struct {
+	ID        int64
+	ProductID int64
+	// positive for incoming items, negative for outgoing items
+	Delta int
+	// product quantity after this movement
+	Balance int
+	Reason  StockMovementReason
+	// order id for sale, admin user for adjustment
+	Reference string
+	CreatedAt time.Time
+}
+
+// filter for stock movement history
+type StockMovementFilter struct {
+	ProductID int64
+	Page      int
+	Limit     int
+}
+
+// offset of current page, page start from 1
+func (e StockMovementFilter) Offset() int {
+	if e.Page <= 1 {
+		return 0
+	}
+	return (e.Page - 1) * e.Limit
+}
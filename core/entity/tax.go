@@ -0,0 +1,24 @@
+package entity
+
+import "time"
+
+// default tax category of product
+const DefaultTaxCategory string = "standard"
+
+type TaxMode string
+
+const (
+	// tax is added on top of price
+	TaxExclusive TaxMode = "exclusive"
+	// price already includes tax
+	TaxInclusive TaxMode = "inclusive"
+)
+
+// tax rate of product tax category
+type TaxRate struct {
+	ID       int64
+	Category string
+	// rate in basis points, eg: 1100 is 11%
+	Rate      int
+	UpdatedAt time.Time
+}
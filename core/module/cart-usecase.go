@@ -0,0 +1,170 @@
+package module
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/gendutski/be-candidate-home-test/core/entity"
+	"github.com/gendutski/be-candidate-home-test/core/repository"
+)
+
+// persistent shopping cart, priced by checkout usecase
+type CartUsecase interface {
+	// create empty cart, empty currency is default currency
+	Create(cart *entity.Cart) error
+	// return cart with live quote of its items
+	Get(id int64) (*entity.Cart, *entity.Checkout, error)
+	// set quantity of product in cart
+	SetItem(cartID int64, serial string, quantity int) error
+	RemoveItem(cartID int64, serial string) error
+	// submit cart items as checkout, reservationID is optional
+	Checkout(cartID, reservationID int64) (*entity.Order, error)
+}
+
+type cartUsecase struct {
+	cartRepo    repository.CartRepo
+	productRepo repository.ProductRepo
+	checkoutUC  CheckoutUsecase
+	// currency of cart created without currency
+	defaultCurrency string
+}
+
+func NewCartUsecase(cartRepo repository.CartRepo, productRepo repository.ProductRepo, checkoutUC CheckoutUsecase, defaultCurrency string) CartUsecase {
+	return &cartUsecase{cartRepo, productRepo, checkoutUC, strings.ToUpper(defaultCurrency)}
+}
+
+func (uc *cartUsecase) Create(cart *entity.Cart) error {
+	// id is generated by database
+	cart.ID = 0
+	cart.OrderID = 0
+	cart.Items = nil
+
+	cart.Currency = strings.ToUpper(strings.TrimSpace(cart.Currency))
+	if cart.Currency == "" {
+		cart.Currency = uc.defaultCurrency
+	}
+	if !currencyPattern.MatchString(cart.Currency) {
+		return entity.NewError(entity.InvalidCurrency, http.StatusBadRequest)
+	}
+
+	if err := uc.cartRepo.CreateCart(cart); err != nil {
+		return entity.NewError(err.Error(), http.StatusInternalServerError)
+	}
+	return nil
+}
+
+func (uc *cartUsecase) Get(id int64) (*entity.Cart, *entity.Checkout, error) {
+	cart, err := uc.getCart(id)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// empty cart has nothing to quote
+	if len(cart.Items) == 0 {
+		return cart, &entity.Checkout{Currency: cart.Currency}, nil
+	}
+
+	// deleted products are shown as warnings
+	request := cart.ToCheckoutRequest()
+	request.Lenient = true
+	checkout, err := uc.checkoutUC.Quote(request)
+	if err != nil {
+		return nil, nil, err
+	}
+	return cart, checkout, nil
+}
+
+func (uc *cartUsecase) SetItem(cartID int64, serial string, quantity int) error {
+	if err := validateItemQuantity(serial, quantity); err != nil {
+		return err
+	}
+
+	cart, err := uc.getOpenCart(cartID)
+	if err != nil {
+		return err
+	}
+	product, err := getProductBySerial(uc.productRepo, serial)
+	if err != nil {
+		return err
+	}
+
+	// product.price is in default currency, other currencies must be in product price list
+	if cart.Currency != uc.defaultCurrency {
+		prices, err := uc.productRepo.GetProductPrices([]int64{product.ID}, cart.Currency)
+		if err != nil {
+			return entity.NewError(err.Error(), http.StatusInternalServerError)
+		}
+		if len(prices) == 0 {
+			return entity.NewError(
+				fmt.Sprintf("product %s(%s) has no price in %s", product.Name, product.Serial, cart.Currency),
+				http.StatusBadRequest)
+		}
+	}
+
+	err = uc.cartRepo.SaveCartItem(&entity.CartItem{
+		CartID:    cart.ID,
+		ProductID: product.ID,
+		Quantity:  quantity,
+	})
+	if err != nil {
+		return entity.NewError(err.Error(), http.StatusInternalServerError)
+	}
+	return nil
+}
+
+func (uc *cartUsecase) RemoveItem(cartID int64, serial string) error {
+	cart, err := uc.getOpenCart(cartID)
+	if err != nil {
+		return err
+	}
+
+	// product may be deleted after it is added to cart, so find it from cart items
+	for _, item := range cart.Items {
+		if item.Product.Serial == serial {
+			if err := uc.cartRepo.DeleteCartItem(cart.ID, item.ProductID); err != nil {
+				return entity.NewError(err.Error(), http.StatusInternalServerError)
+			}
+			return nil
+		}
+	}
+	return entity.NewError(entity.ProductNotFound, http.StatusNotFound)
+}
+
+func (uc *cartUsecase) Checkout(cartID, reservationID int64) (*entity.Order, error) {
+	cart, err := uc.getOpenCart(cartID)
+	if err != nil {
+		return nil, err
+	}
+	if len(cart.Items) == 0 {
+		return nil, entity.NewError(entity.EmptyCart, http.StatusBadRequest)
+	}
+
+	// cart is closed in the same transaction with the order
+	request := cart.ToCheckoutRequest()
+	request.ReservationID = reservationID
+	return uc.checkoutUC.Submit(request)
+}
+
+func (uc *cartUsecase) getCart(id int64) (*entity.Cart, error) {
+	cart, err := uc.cartRepo.GetCartByID(id)
+	if err != nil {
+		return nil, entity.NewError(err.Error(), http.StatusInternalServerError)
+	}
+	if cart == nil {
+		return nil, entity.NewError(entity.CartNotFound, http.StatusNotFound)
+	}
+	return cart, nil
+}
+
+// get cart that can be changed or checked out
+func (uc *cartUsecase) getOpenCart(id int64) (*entity.Cart, error) {
+	cart, err := uc.getCart(id)
+	if err != nil {
+		return nil, err
+	}
+	if cart.CheckedOut() {
+		return nil, entity.NewError(entity.CartCheckedOut, http.StatusBadRequest)
+	}
+	return cart, nil
+}
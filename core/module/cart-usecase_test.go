@@ -0,0 +1,225 @@
+package module_test
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/gendutski/be-candidate-home-test/core/entity"
+	"github.com/gendutski/be-candidate-home-test/core/module"
+	repomocks "github.com/gendutski/be-candidate-home-test/core/repository/mocks"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/golang/mock/gomock"
+)
+
+func initCartUC(ctrl *gomock.Controller) (module.CartUsecase, *repomocks.MockCartRepo, *repomocks.MockProductRepo, *repomocks.MockPromotionRepo) {
+	checkoutUC, productRepo, promoRepo, taxRepo := initCheckoutUC(ctrl)
+	cartRepo := repomocks.NewMockCartRepo(ctrl)
+	// products in these cases have no tax rate
+	taxRepo.EXPECT().GetTaxRateByCategories(gomock.Any()).Return(nil, nil).AnyTimes()
+	// no cart level promotion in these cases
+	promoRepo.EXPECT().GetCartPromotions(checkoutTime).Return(nil, nil).AnyTimes()
+
+	return module.NewCartUsecase(cartRepo, productRepo, checkoutUC, "USD"), cartRepo, productRepo, promoRepo
+}
+
+func Test_CreateCart(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	svc, cartRepo, _, _ := initCartUC(ctrl)
+
+	t.Run("positive, set default currency", func(t *testing.T) {
+		cartRepo.EXPECT().CreateCart(&entity.Cart{Currency: "USD"}).Return(nil).Times(1)
+
+		err := svc.Create(&entity.Cart{})
+		assert.Nil(t, err)
+	})
+
+	t.Run("negative, invalid currency", func(t *testing.T) {
+		err := svc.Create(&entity.Cart{Currency: "EURO"})
+		assert.Equal(t, entity.NewError(entity.InvalidCurrency, http.StatusBadRequest), err)
+	})
+}
+
+func Test_CartItem(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	svc, cartRepo, productRepo, promoRepo := initCartUC(ctrl)
+
+	dayCreated, _ := time.Parse("2006-01-02", "2023-05-16")
+	product := &entity.Product{ID: 1, Serial: "120P90", Name: "Google Home", Price: 4999, UpdatedAt: dayCreated}
+	cart := &entity.Cart{
+		ID:       3,
+		Currency: "USD",
+		Items:    []*entity.CartItem{{ID: 1, CartID: 3, ProductID: 1, Quantity: 2, Product: product}},
+	}
+
+	t.Run("set item quantity", func(t *testing.T) {
+		cartRepo.EXPECT().GetCartByID(int64(3)).Return(cart, nil).Times(1)
+		productRepo.EXPECT().GetProductBySerials([]string{"120P90"}).Return([]*entity.Product{product}, nil).Times(1)
+		cartRepo.EXPECT().SaveCartItem(&entity.CartItem{CartID: 3, ProductID: 1, Quantity: 5}).Return(nil).Times(1)
+
+		err := svc.SetItem(3, "120P90", 5)
+		assert.Nil(t, err)
+	})
+
+	t.Run("set item, product not found", func(t *testing.T) {
+		cartRepo.EXPECT().GetCartByID(int64(3)).Return(cart, nil).Times(1)
+		productRepo.EXPECT().GetProductBySerials([]string{"XXXXXX"}).Return(nil, nil).Times(1)
+
+		err := svc.SetItem(3, "XXXXXX", 1)
+		assert.Equal(t, entity.NewError(entity.ProductNotFound, http.StatusNotFound), err)
+	})
+
+	t.Run("set item, serial in different letter case", func(t *testing.T) {
+		cartRepo.EXPECT().GetCartByID(int64(3)).Return(cart, nil).Times(1)
+		productRepo.EXPECT().GetProductBySerials([]string{"120p90"}).Return([]*entity.Product{product}, nil).Times(1)
+
+		err := svc.SetItem(3, "120p90", 1)
+		assert.Equal(t, entity.NewError(entity.ProductNotFound, http.StatusNotFound), err)
+	})
+
+	t.Run("set item, product has price in cart currency", func(t *testing.T) {
+		cartRepo.EXPECT().GetCartByID(int64(4)).Return(&entity.Cart{ID: 4, Currency: "EUR"}, nil).Times(1)
+		productRepo.EXPECT().GetProductBySerials([]string{"120P90"}).Return([]*entity.Product{product}, nil).Times(1)
+		productRepo.EXPECT().GetProductPrices([]int64{1}, "EUR").Return([]*entity.ProductPrice{
+			{ProductID: 1, Currency: "EUR", Price: 4599},
+		}, nil).Times(1)
+		cartRepo.EXPECT().SaveCartItem(&entity.CartItem{CartID: 4, ProductID: 1, Quantity: 1}).Return(nil).Times(1)
+
+		err := svc.SetItem(4, "120P90", 1)
+		assert.Nil(t, err)
+	})
+
+	t.Run("set item, product has no price in cart currency", func(t *testing.T) {
+		cartRepo.EXPECT().GetCartByID(int64(4)).Return(&entity.Cart{ID: 4, Currency: "EUR"}, nil).Times(1)
+		productRepo.EXPECT().GetProductBySerials([]string{"120P90"}).Return([]*entity.Product{product}, nil).Times(1)
+		productRepo.EXPECT().GetProductPrices([]int64{1}, "EUR").Return(nil, nil).Times(1)
+		cartRepo.EXPECT().SaveCartItem(gomock.Any()).Times(0)
+
+		err := svc.SetItem(4, "120P90", 1)
+		assert.Equal(t, entity.NewError("product Google Home(120P90) has no price in EUR", http.StatusBadRequest), err)
+	})
+
+	t.Run("set item, quantity out of range", func(t *testing.T) {
+		// repository must not be called
+		err := svc.SetItem(3, "120P90", 0)
+		assert.Equal(t, entity.NewError(entity.EmptyQuantity, http.StatusBadRequest), err)
+
+		err = svc.SetItem(3, "120P90", 1001)
+		assert.Equal(t, entity.NewError("quantity of 120P90 exceeds maximum of 1000 items", http.StatusBadRequest), err)
+	})
+
+	t.Run("remove item", func(t *testing.T) {
+		cartRepo.EXPECT().GetCartByID(int64(3)).Return(cart, nil).Times(1)
+		cartRepo.EXPECT().DeleteCartItem(int64(3), int64(1)).Return(nil).Times(1)
+
+		err := svc.RemoveItem(3, "120P90")
+		assert.Nil(t, err)
+	})
+
+	t.Run("get cart with live quote", func(t *testing.T) {
+		cartRepo.EXPECT().GetCartByID(int64(3)).Return(cart, nil).Times(1)
+		productRepo.EXPECT().GetProductBySerials([]string{"120P90"}).Return([]*entity.Product{product}, nil).Times(1)
+		promoRepo.EXPECT().GetPromotionByProducts([]*entity.Product{product}, checkoutTime).Return(nil, nil).Times(1)
+		productRepo.EXPECT().GetProductQuantities([]int64{1}).Return([]*entity.ProductQuantity{
+			{ID: 1, ProductID: 1, Quantity: 10, UpdatedAt: dayCreated},
+		}, nil).Times(1)
+
+		resp, checkout, err := svc.Get(3)
+		assert.Nil(t, err)
+		assert.Equal(t, cart, resp)
+		assert.Equal(t, &entity.Checkout{
+			Items: []*entity.CheckoutItem{
+				{Product: product, Quantity: 2, SubTotalPrice: 4999 * 2, AvailableQuantity: 10},
+			},
+			TotalItem:  2,
+			TotalPrice: 4999 * 2,
+			GrandTotal: 4999 * 2,
+			Currency:   "USD",
+		}, checkout)
+	})
+
+	t.Run("get cart with only deleted products", func(t *testing.T) {
+		deleted := &entity.Cart{
+			ID:       5,
+			Currency: "USD",
+			Items:    []*entity.CartItem{{ID: 2, CartID: 5, ProductID: 1, Quantity: 2, Product: product}},
+		}
+		cartRepo.EXPECT().GetCartByID(int64(5)).Return(deleted, nil).Times(1)
+		// deleted product is not found by serial
+		productRepo.EXPECT().GetProductBySerials([]string{"120P90"}).Return(nil, nil).Times(1)
+
+		resp, checkout, err := svc.Get(5)
+		assert.Nil(t, err)
+		assert.Equal(t, deleted, resp)
+		assert.Equal(t, &entity.Checkout{Currency: "USD", UnknownSerials: []string{"120P90"}}, checkout)
+	})
+
+	t.Run("cart not found", func(t *testing.T) {
+		cartRepo.EXPECT().GetCartByID(int64(9)).Return(nil, nil).Times(1)
+
+		resp, checkout, err := svc.Get(9)
+		assert.Nil(t, resp)
+		assert.Nil(t, checkout)
+		assert.Equal(t, entity.NewError(entity.CartNotFound, http.StatusNotFound), err)
+	})
+}
+
+func Test_CartCheckout(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	svc, cartRepo, productRepo, promoRepo := initCartUC(ctrl)
+
+	dayCreated, _ := time.Parse("2006-01-02", "2023-05-16")
+	product := &entity.Product{ID: 1, Serial: "120P90", Name: "Google Home", Price: 4999, UpdatedAt: dayCreated}
+
+	t.Run("positive, submit cart items", func(t *testing.T) {
+		cartRepo.EXPECT().GetCartByID(int64(3)).Return(&entity.Cart{
+			ID:       3,
+			Currency: "USD",
+			Items:    []*entity.CartItem{{ID: 1, CartID: 3, ProductID: 1, Quantity: 2, Product: product}},
+		}, nil).Times(1)
+		productRepo.EXPECT().GetProductBySerials([]string{"120P90"}).Return([]*entity.Product{product}, nil).Times(1)
+		promoRepo.EXPECT().GetPromotionByProducts([]*entity.Product{product}, checkoutTime).Return(nil, nil).Times(1)
+
+		checkout := &entity.Checkout{
+			Items: []*entity.CheckoutItem{
+				{Product: product, Quantity: 2, SubTotalPrice: 4999 * 2},
+			},
+			TotalItem:     2,
+			TotalPrice:    4999 * 2,
+			GrandTotal:    4999 * 2,
+			Currency:      "USD",
+			ReservationID: 5,
+			SubmittedAt:   checkoutTime,
+			CartID:        3,
+		}
+		order := entity.NewOrder(checkout)
+		productRepo.EXPECT().SubmitCheckout(checkout).Return(order, nil).Times(1)
+
+		resp, err := svc.Checkout(3, 5)
+		assert.Nil(t, err)
+		assert.Equal(t, order, resp)
+	})
+
+	t.Run("negative, cart is already checked out", func(t *testing.T) {
+		cartRepo.EXPECT().GetCartByID(int64(4)).Return(&entity.Cart{ID: 4, Currency: "USD", OrderID: 7}, nil).Times(1)
+
+		resp, err := svc.Checkout(4, 0)
+		assert.Nil(t, resp)
+		assert.Equal(t, entity.NewError(entity.CartCheckedOut, http.StatusBadRequest), err)
+	})
+
+	t.Run("negative, empty cart", func(t *testing.T) {
+		cartRepo.EXPECT().GetCartByID(int64(5)).Return(&entity.Cart{ID: 5, Currency: "USD"}, nil).Times(1)
+
+		resp, err := svc.Checkout(5, 0)
+		assert.Nil(t, resp)
+		assert.Equal(t, entity.NewError(entity.EmptyCart, http.StatusBadRequest), err)
+	})
+}
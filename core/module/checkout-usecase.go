@@ -1,97 +1,341 @@
 package module
 
 import (
+	"fmt"
 	"net/http"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
 
 	"github.com/gendutski/be-candidate-home-test/core/entity"
 	"github.com/gendutski/be-candidate-home-test/core/repository"
 )
 
+// ISO 4217 currency code
+var currencyPattern = regexp.MustCompile(`^[A-Z]{3}$`)
+
+// maximum quantity of each product in checkout
+const maxItemQuantity int = 1000
+
+// search bound of best-price mode when configured bound is not positive
+const defaultSearchBound int = 1000
+
 type CheckoutUsecase interface {
-	Submit(payload entity.MapProductSerialQuantity) (*entity.Checkout, error)
+	Submit(payload *entity.CheckoutRequest) (*entity.Order, error)
+	// price checkout with promotions and current stock, without locking or writing anything
+	Quote(payload *entity.CheckoutRequest) (*entity.Checkout, error)
+	// hold stock of checkout items until reservation ttl
+	Reserve(payload *entity.CheckoutRequest) (*entity.Reservation, error)
 }
 
 type checkoutUsecase struct {
 	productRepo repository.ProductRepo
 	promoRepo   repository.PromotionRepo
+	taxCalc     TaxCalculator
+	clock       Clock
+	// currency of product.price, other currencies use product price list
+	defaultCurrency string
+	reservationTTL  time.Duration
+	promotionMode   entity.PromotionMode
+	// maximum number of unit allocations evaluated for each product in best-price mode
+	searchBound int
+}
+
+func NewCheckoutUsecase(productRepo repository.ProductRepo, promoRepo repository.PromotionRepo, taxCalc TaxCalculator, clock Clock, defaultCurrency string, reservationTTL time.Duration, promotionMode entity.PromotionMode, searchBound int) CheckoutUsecase {
+	// unknown mode is sequential
+	if promotionMode != entity.PromotionBestPrice {
+		promotionMode = entity.PromotionSequential
+	}
+	if searchBound <= 0 {
+		searchBound = defaultSearchBound
+	}
+	return &checkoutUsecase{productRepo, promoRepo, taxCalc, clock, strings.ToUpper(defaultCurrency), reservationTTL, promotionMode, searchBound}
+}
+
+func (uc *checkoutUsecase) Submit(payload *entity.CheckoutRequest) (*entity.Order, error) {
+	checkout, err := uc.prepareCheckout(payload)
+	if err != nil {
+		return nil, err
+	}
+	if len(checkout.Items) == 0 {
+		return nil, newUnknownSerialsError(checkout.UnknownSerials)
+	}
+	if payload.ReservationID != 0 {
+		checkout.ReservationID = payload.ReservationID
+		checkout.SubmittedAt = uc.clock.Now()
+	}
+	checkout.CartID = payload.CartID
+
+	// submit checkout to database
+	order, err := uc.productRepo.SubmitCheckout(checkout)
+	if err != nil {
+		// repository must handle error with entity.Err
+		return nil, err
+	}
+
+	return order, nil
+}
+
+func (uc *checkoutUsecase) Quote(payload *entity.CheckoutRequest) (*entity.Checkout, error) {
+	checkout, err := uc.prepareCheckout(payload)
+	if err != nil {
+		return nil, err
+	}
+	// unknown serials are shown as warnings of empty quote
+	if len(checkout.Items) == 0 {
+		return checkout, nil
+	}
+
+	// get current stock
+	var productIDs []int64
+	for _, item := range checkout.Items {
+		productIDs = append(productIDs, item.Product.ID)
+	}
+	quantities, err := uc.productRepo.GetProductQuantities(productIDs)
+	if err != nil {
+		return nil, entity.NewError(err.Error(), http.StatusInternalServerError)
+	}
+
+	// map product id with available quantity
+	mapQuantity := make(map[int64]int)
+	for _, q := range quantities {
+		mapQuantity[q.ProductID] = q.Available()
+	}
+	for _, item := range checkout.Items {
+		item.AvailableQuantity = mapQuantity[item.Product.ID]
+	}
+
+	return checkout, nil
 }
 
-func NewCheckoutUsecase(productRepo repository.ProductRepo, promoRepo repository.PromotionRepo) CheckoutUsecase {
-	return &checkoutUsecase{productRepo, promoRepo}
+func (uc *checkoutUsecase) Reserve(payload *entity.CheckoutRequest) (*entity.Reservation, error) {
+	checkout, err := uc.prepareCheckout(payload)
+	if err != nil {
+		return nil, err
+	}
+	if len(checkout.Items) == 0 {
+		return nil, newUnknownSerialsError(checkout.UnknownSerials)
+	}
+
+	// reserve all items including free items
+	reservation := entity.Reservation{
+		ExpiresAt:      uc.clock.Now().Add(uc.reservationTTL),
+		UnknownSerials: checkout.UnknownSerials,
+	}
+	for _, item := range checkout.Items {
+		reservation.Items = append(reservation.Items, &entity.ReservationItem{
+			ProductID: item.Product.ID,
+			Quantity:  item.Quantity,
+			Product:   item.Product,
+		})
+	}
+
+	if err := uc.productRepo.ReserveStock(&reservation); err != nil {
+		// repository must handle error with entity.Err
+		return nil, err
+	}
+	return &reservation, nil
 }
 
-func (uc *checkoutUsecase) Submit(payload entity.MapProductSerialQuantity) (*entity.Checkout, error) {
+// get products and promotions, then render checkout
+func (uc *checkoutUsecase) prepareCheckout(payload *entity.CheckoutRequest) (*entity.Checkout, error) {
+	// validate currency, empty currency is default currency
+	currency := strings.ToUpper(strings.TrimSpace(payload.Currency))
+	if currency == "" {
+		currency = uc.defaultCurrency
+	}
+	if !currencyPattern.MatchString(currency) {
+		return nil, entity.NewError(entity.InvalidCurrency, http.StatusBadRequest)
+	}
+	if err := validateQuantity(payload.Items); err != nil {
+		return nil, err
+	}
+
 	// get products
-	products, err := uc.productRepo.GetProductBySerials(payload.PluckSerial())
+	products, err := uc.productRepo.GetProductBySerials(payload.Items.PluckSerial())
 	if err != nil {
 		return nil, entity.NewError(err.Error(), http.StatusInternalServerError)
 	}
+
+	// unknown serials are rejected, unless request is lenient
+	products, unknownSerials := matchProductSerials(payload.Items, products)
+	if !payload.Lenient && (len(products) == 0 || len(unknownSerials) > 0) {
+		return nil, newUnknownSerialsError(unknownSerials)
+	}
+	// lenient request may skip every serial, empty checkout is rejected by submit and reserve
 	if len(products) == 0 {
-		return nil, entity.NewError(entity.ProductNotFound, http.StatusBadRequest)
+		return &entity.Checkout{Currency: currency, UnknownSerials: unknownSerials}, nil
+	}
+
+	// set product price in requested currency
+	products, err = uc.applyCurrencyPrice(products, currency)
+	if err != nil {
+		return nil, err
 	}
 
 	// get promotions
-	promotionMaps, err := uc.promoRepo.GetPromotionByProducts(products)
+	promotionMaps, err := uc.promoRepo.GetPromotionByProducts(products, uc.clock.Now())
 	if err != nil {
 		return nil, entity.NewError(err.Error(), http.StatusInternalServerError)
 	}
 
 	// render checkout
-	checkout, err := uc.generateCheckout(payload, products, promotionMaps)
+	checkout, err := uc.generateCheckout(payload.Items, products, promotionMaps, currency)
 	if err != nil {
 		return nil, err
 	}
 
-	// submit checkout to database
-	err = uc.productRepo.SubmitCheckout(checkout)
-	if err != nil {
-		// repository must handle error with entity.Err
+	// apply cart level promotions after promotions of each product
+	if err := uc.applyCartPromotions(checkout); err != nil {
+		return nil, err
+	}
+
+	// apply coupon after promotions, so tax is calculated from discounted sub total
+	if err := uc.applyCoupon(checkout, payload); err != nil {
 		return nil, err
 	}
 
+	// calculate tax after promotions
+	if err := uc.taxCalc.Calculate(checkout); err != nil {
+		return nil, err
+	}
+	checkout.UnknownSerials = unknownSerials
 	return checkout, nil
 }
 
-func (uc *checkoutUsecase) generateCheckout(mapQuantity entity.MapProductSerialQuantity, products []*entity.Product, promotionMaps map[int64][]*entity.Promotion) (*entity.Checkout, error) {
+// This function returns product not found error with list of unknown serials
+func newUnknownSerialsError(unknownSerials []string) error {
+	return entity.NewErrorWithDetails(entity.ProductNotFound, http.StatusBadRequest, map[string]interface{}{
+		"serials": unknownSerials,
+	})
+}
+
+// This function returns product with exactly the serial, for usecases of a single product
+// database collation may match serial with different letter case
+func getProductBySerial(productRepo repository.ProductRepo, serial string) (*entity.Product, error) {
+	products, err := productRepo.GetProductBySerials([]string{serial})
+	if err != nil {
+		return nil, entity.NewError(err.Error(), http.StatusInternalServerError)
+	}
+	for _, product := range products {
+		if product.Serial == serial {
+			return product, nil
+		}
+	}
+	return nil, entity.NewError(entity.ProductNotFound, http.StatusNotFound)
+}
+
+// This function returns products with exactly requested serial and sorted requested serials without product
+// database collation may match serial with different letter case
+func matchProductSerials(items entity.MapProductSerialQuantity, products []*entity.Product) ([]*entity.Product, []string) {
+	found := make(map[string]bool)
+	var matched []*entity.Product
+	for _, product := range products {
+		if _, ok := items[product.Serial]; ok {
+			found[product.Serial] = true
+			matched = append(matched, product)
+		}
+	}
+
+	var unknown []string
+	for serial := range items {
+		if !found[serial] {
+			unknown = append(unknown, serial)
+		}
+	}
+	sort.Strings(unknown)
+	return matched, unknown
+}
+
+// This function returns copy of products with price in the currency
+// products without price in the currency will be rejected
+func (uc *checkoutUsecase) applyCurrencyPrice(products []*entity.Product, currency string) ([]*entity.Product, error) {
+	// product.price is in default currency
+	if currency == uc.defaultCurrency {
+		return products, nil
+	}
+
+	var productIDs []int64
+	for _, product := range products {
+		productIDs = append(productIDs, product.ID)
+	}
+	prices, err := uc.productRepo.GetProductPrices(productIDs, currency)
+	if err != nil {
+		return nil, entity.NewError(err.Error(), http.StatusInternalServerError)
+	}
+
+	// map product id with price
+	mapPrice := make(map[int64]entity.Money)
+	for _, price := range prices {
+		mapPrice[price.ProductID] = price.Price
+	}
+
+	var result []*entity.Product
+	for _, product := range products {
+		price, ok := mapPrice[product.ID]
+		if !ok {
+			return nil, entity.NewError(
+				fmt.Sprintf("product %s(%s) has no price in %s", product.Name, product.Serial, currency),
+				http.StatusBadRequest)
+		}
+		// copy product, so the price change does not leak to the caller
+		priced := *product
+		priced.Price = price
+		result = append(result, &priced)
+	}
+	return result, nil
+}
+
+func (uc *checkoutUsecase) generateCheckout(mapQuantity entity.MapProductSerialQuantity, products []*entity.Product, promotionMaps map[int64][]*entity.Promotion, currency string) (*entity.Checkout, error) {
 	// if product item is free by promo
-	// map[int64] = product id, int = number available free items
-	var freeProductItem map[int64]int
+	// map[int64] = product id, []*entity.AppliedPromotion = bonus promotions with number of free items
+	var freeProductItem map[int64][]*entity.AppliedPromotion
 
-	var result entity.Checkout
+	result := entity.Checkout{Currency: currency}
 
-	// loop products
+	// money of promotion is only valid in its currency
+	promotionMaps = filterPromotionCurrency(promotionMaps, currency)
+
+	// promotions applied to each product
+	// map[int64] = product id
+	lines := make(map[int64]*productPromotions)
 	for _, product := range products {
-		var checkoutItem entity.CheckoutItem
+		promotions, err := uc.resolvePromotions(mapQuantity[product.Serial], product, promotionMaps[product.ID], products, currency)
+		if err != nil {
+			return nil, err
+		}
+		lines[product.ID] = promotions
+	}
+	if uc.promotionMode == entity.PromotionBestPrice {
+		uc.optimisePromotions(mapQuantity, products, promotionMaps, lines)
+	}
 
+	// loop products
+	for _, product := range products {
 		// set quantity
 		qty := mapQuantity[product.Serial]
+		promotions := lines[product.ID]
 
-		// init checkout
-		checkoutItem.Product = product
-		checkoutItem.Quantity = qty
-		checkoutItem.SubTotalPrice = float64(qty) * product.Price
-
-		for productID, promos := range promotionMaps {
-			if productID == product.ID {
-				for _, promo := range promos {
-					// the repository should sort promotion types in ascending order
-					switch promo.Type {
-					case entity.BonusItem:
-						freeProductItem = uc.handleBonusItemPromotion(qty, promo, freeProductItem)
-					case entity.BuyItemsForReducePrice:
-						checkoutItem.SubTotalPrice = uc.handleReducePricePromotion(qty, product, promo)
-					case entity.DiscountInPercent:
-						checkoutItem.SubTotalPrice = uc.handleDiscountPromotion(qty, checkoutItem.SubTotalPrice, promo)
-					default:
-						continue
-					}
-				}
+		checkoutItem := entity.CheckoutItem{
+			Product: product,
+			// free items are added on top of purchased quantity, without changing sub total price
+			Quantity:          qty + promotions.freeQuantity,
+			SubTotalPrice:     promotions.subTotal,
+			AppliedPromotions: promotions.applied,
+		}
+
+		// bonus items are recorded in free product item
+		for productID, bonuses := range promotions.bonuses {
+			if freeProductItem == nil {
+				freeProductItem = make(map[int64][]*entity.AppliedPromotion)
 			}
+			freeProductItem[productID] = append(freeProductItem[productID], bonuses...)
 		}
 
 		// set result
 		result.Items = append(result.Items, &checkoutItem)
-		result.TotalItem += qty
+		result.TotalItem += checkoutItem.Quantity
 		result.TotalPrice += checkoutItem.SubTotalPrice
 	}
 
@@ -99,106 +343,890 @@ func (uc *checkoutUsecase) generateCheckout(mapQuantity entity.MapProductSerialQ
 	if err != nil {
 		return nil, err
 	}
+
+	// sum discount of all items
+	for _, item := range result.Items {
+		for _, applied := range item.AppliedPromotions {
+			result.TotalDiscount += applied.DiscountAmount
+		}
+	}
 	return &result, nil
 }
 
-// This function calculates the free items that will be obtained
-func (uc *checkoutUsecase) handleBonusItemPromotion(quantity int, promo *entity.Promotion, freeProductItem map[int64]int) map[int64]int {
-	// if promo product id empty or no match quantity, no free item for this promo
-	if promo.PromoProductID == 0 || promo.MatchQuantity == 0 || quantity < promo.MatchQuantity {
-		return freeProductItem
+// promotions applied to a product
+type productPromotions struct {
+	subTotal     entity.Money
+	freeQuantity int
+	applied      []*entity.AppliedPromotion
+	// bonus items of other products
+	// map[int64] = promo product id, []*entity.AppliedPromotion = bonus promotions with number of free items
+	bonuses map[int64][]*entity.AppliedPromotion
+}
+
+// This function resolves which promotions of a product are applied
+// promotions are evaluated in order of priority, type and id
+// non exclusive promotions are stacked, a promotion that cannot be stacked with the applied promotions is skipped
+// each exclusive promotion competes alone with the stack, the one with the most discount wins
+// on a tie the stack wins, then the exclusive promotion evaluated first
+func (uc *checkoutUsecase) resolvePromotions(quantity int, product *entity.Product, promos []*entity.Promotion, products []*entity.Product, currency string) (*productPromotions, error) {
+	sorted := sortPromotions(promos)
+	stack := &productPromotions{subTotal: product.Price.Multiply(quantity)}
+	var stacked, exclusives []*entity.Promotion
+	for _, promo := range sorted {
+		if promo.Exclusive {
+			exclusives = append(exclusives, promo)
+			continue
+		}
+		if !canStack(promo, stacked) {
+			continue
+		}
+		if uc.applyProductPromotion(stack, quantity, quantity, product, promo) {
+			stacked = append(stacked, promo)
+		}
+	}
+	if len(exclusives) == 0 {
+		return stack, nil
+	}
+
+	result := stack
+	maxDiscount, err := uc.productPromotionsDiscount(stack, products, currency)
+	if err != nil {
+		return nil, err
+	}
+	for _, promo := range exclusives {
+		single := &productPromotions{subTotal: product.Price.Multiply(quantity)}
+		if !uc.applyProductPromotion(single, quantity, quantity, product, promo) {
+			continue
+		}
+		discount, err := uc.productPromotionsDiscount(single, products, currency)
+		if err != nil {
+			return nil, err
+		}
+		if discount > maxDiscount {
+			result, maxDiscount = single, discount
+		}
+	}
+	return result, nil
+}
+
+// This function returns promotions without currency or in the checkout currency
+func filterPromotionCurrency(promotionMaps map[int64][]*entity.Promotion, currency string) map[int64][]*entity.Promotion {
+	result := make(map[int64][]*entity.Promotion)
+	for productID, promos := range promotionMaps {
+		for _, promo := range promos {
+			if promo.Currency == "" || promo.Currency == currency {
+				result[productID] = append(result[productID], promo)
+			}
+		}
+	}
+	return result
+}
+
+// This function returns copy of promotions sorted by priority, type and id
+func sortPromotions(promos []*entity.Promotion) []*entity.Promotion {
+	result := make([]*entity.Promotion, len(promos))
+	copy(result, promos)
+	sort.SliceStable(result, func(i, j int) bool {
+		if result[i].Priority != result[j].Priority {
+			return result[i].Priority < result[j].Priority
+		}
+		if result[i].Type != result[j].Type {
+			return result[i].Type < result[j].Type
+		}
+		return result[i].ID < result[j].ID
+	})
+	return result
+}
+
+// can promotion be stacked with all applied promotions
+func canStack(promo *entity.Promotion, applied []*entity.Promotion) bool {
+	for _, other := range applied {
+		if !promo.CanStackWith(other) {
+			return false
+		}
 	}
+	return true
+}
+
+// This function applies promotion on top of previously applied promotions, return false if promotion is not applicable
+// quantity is number of units the promotion is applied to, lineQuantity is purchased quantity of the product
+// discount in percent is applied when purchased quantity reaches match quantity, even to part of the units
+func (uc *checkoutUsecase) applyProductPromotion(result *productPromotions, quantity, lineQuantity int, product *entity.Product, promo *entity.Promotion) bool {
+	// sub total before promotion is applied, to get discount amount
+	currentSubTotal := result.subTotal
+	var applied bool
+	var numOfFreeItems int
+
+	switch promo.Type {
+	case entity.BonusItem:
+		var bonus *entity.AppliedPromotion
+		bonus, applied = uc.handleBonusItemPromotion(quantity, promo)
+		if applied {
+			if result.bonuses == nil {
+				result.bonuses = make(map[int64][]*entity.AppliedPromotion)
+			}
+			result.bonuses[promo.PromoProductID] = append(result.bonuses[promo.PromoProductID], bonus)
+		}
+		return applied
+	case entity.BuyItemsForReducePrice:
+		result.subTotal, applied = uc.handleReducePricePromotion(quantity, result.subTotal, promo)
+	case entity.DiscountInPercent:
+		result.subTotal, applied = uc.handleDiscountPromotion(lineQuantity, result.subTotal, promo)
+	case entity.FreeItem:
+		numOfFreeItems, applied = uc.handleFreeItemPromotion(quantity, promo)
+		result.freeQuantity += numOfFreeItems
+	case entity.TieredPrice:
+		result.subTotal, applied = uc.handleTieredPricePromotion(quantity, lineQuantity, result.subTotal, promo)
+	case entity.DiscountInAmount:
+		result.subTotal, applied = uc.handleAmountDiscountPromotion(quantity, lineQuantity, result.subTotal, promo)
+	case entity.LineDiscountInAmount:
+		result.subTotal, applied = uc.handleLineAmountDiscountPromotion(lineQuantity, result.subTotal, promo)
+	case entity.FixedUnitPrice:
+		result.subTotal, applied = uc.handleFixedUnitPricePromotion(quantity, lineQuantity, result.subTotal, promo)
+	default:
+		return false
+	}
+
+	// record applied promotion
+	if applied {
+		result.applied = append(result.applied, &entity.AppliedPromotion{
+			PromotionID:    promo.ID,
+			Type:           promo.Type,
+			DiscountAmount: currentSubTotal - result.subTotal + product.Price.Multiply(numOfFreeItems),
+			FreeQuantity:   numOfFreeItems,
+		})
+	}
+	return applied
+}
+
+// This function replaces promotions of each product with a cheaper allocation of units
+// products are optimised one by one, an allocation is kept only when it lowers total price of checkout
+// so the result is never worse than sequential promotions
+// cart level promotions and coupon are applied after the optimiser
+func (uc *checkoutUsecase) optimisePromotions(mapQuantity entity.MapProductSerialQuantity, products []*entity.Product, promotionMaps map[int64][]*entity.Promotion, lines map[int64]*productPromotions) {
+	// price of checkout products, to get value of bonus items
+	mapPrice := make(map[int64]entity.Money)
+	for _, product := range products {
+		mapPrice[product.ID] = product.Price
+	}
+
+	total := estimateTotalPrice(products, lines)
+	for _, product := range products {
+		candidate := uc.searchPromotionAllocation(mapQuantity[product.Serial], product, promotionMaps[product.ID], mapPrice)
+		if candidate == nil {
+			continue
+		}
+
+		current := lines[product.ID]
+		lines[product.ID] = candidate
+		if candidateTotal := estimateTotalPrice(products, lines); candidateTotal < total {
+			total = candidateTotal
+		} else {
+			lines[product.ID] = current
+		}
+	}
+}
+
+// This function searches the allocation of units to stacks of promotions with the lowest cost
+// cost is sub total price minus value of bonus items that are already in checkout
+// exclusive promotions take the whole quantity, they are compared by resolvePromotions
+// the search stops after uc.searchBound allocations, return nil if nothing is evaluated
+func (uc *checkoutUsecase) searchPromotionAllocation(quantity int, product *entity.Product, promos []*entity.Promotion, mapPrice map[int64]entity.Money) *productPromotions {
+	stacks := uc.promotionStacks(promos)
+	if len(stacks) == 0 {
+		return nil
+	}
+
+	var result *productPromotions
+	var minCost entity.Money
+	var evaluated int
+	units := make([]int, len(stacks))
 
-	if freeProductItem == nil {
-		freeProductItem = make(map[int64]int)
+	var search func(i, remaining int)
+	search = func(i, remaining int) {
+		if evaluated >= uc.searchBound {
+			return
+		}
+		if i == len(stacks) {
+			evaluated++
+			candidate := uc.allocatePromotions(quantity, product, stacks, units)
+			if cost := promotionCost(candidate, mapPrice); result == nil || cost < minCost {
+				result, minCost = candidate, cost
+			}
+			return
+		}
+
+		// units of the stack are multiples of its match quantity, or all remaining units
+		// most units are allocated first, so a bounded search starts from the greedy allocation
+		step := stackStep(stacks[i])
+		if remaining%step != 0 {
+			units[i] = remaining
+			search(i+1, 0)
+		}
+		for k := remaining - remaining%step; k >= 0; k -= step {
+			units[i] = k
+			search(i+1, remaining-k)
+		}
+		units[i] = 0
+	}
+	search(0, quantity)
+	return result
+}
+
+// This function returns combinations of non exclusive promotions that can be stacked together
+// promotions of each stack are sorted by priority, type and id, bigger stacks come first
+func (uc *checkoutUsecase) promotionStacks(promos []*entity.Promotion) [][]*entity.Promotion {
+	var result [][]*entity.Promotion
+	for _, promo := range sortPromotions(promos) {
+		if promo.Exclusive {
+			continue
+		}
+		for _, stack := range result {
+			if len(result) >= uc.searchBound {
+				break
+			}
+			if canStack(promo, stack) {
+				combined := make([]*entity.Promotion, len(stack), len(stack)+1)
+				copy(combined, stack)
+				result = append(result, append(combined, promo))
+			}
+		}
+		if len(result) < uc.searchBound {
+			result = append(result, []*entity.Promotion{promo})
+		}
+	}
+	sort.SliceStable(result, func(i, j int) bool { return len(result[i]) > len(result[j]) })
+	return result
+}
+
+// units of a stack, the biggest match quantity of its promotions
+func stackStep(stack []*entity.Promotion) int {
+	result := 1
+	for _, promo := range stack {
+		if promo.MatchQuantity > result {
+			result = promo.MatchQuantity
+		}
+	}
+	return result
+}
+
+// This function applies each stack of promotions to its allocated units
+// units that are not allocated are charged at product price
+// line discount in amount is applied once, to the first group of units it is applied to
+func (uc *checkoutUsecase) allocatePromotions(quantity int, product *entity.Product, stacks [][]*entity.Promotion, units []int) *productPromotions {
+	result := &productPromotions{}
+	lineDiscounts := make(map[int64]bool)
+	var allocated int
+	for i, stack := range stacks {
+		if units[i] == 0 {
+			continue
+		}
+		group := &productPromotions{subTotal: product.Price.Multiply(units[i])}
+		for _, promo := range stack {
+			if lineDiscounts[promo.ID] {
+				continue
+			}
+			if uc.applyProductPromotion(group, units[i], quantity, product, promo) && promo.Type == entity.LineDiscountInAmount {
+				lineDiscounts[promo.ID] = true
+			}
+		}
+		mergeProductPromotions(result, group)
+		allocated += units[i]
+	}
+	result.subTotal += product.Price.Multiply(quantity - allocated)
+	return result
+}
+
+// This function adds promotions of a group of units to the result, the same promotion is recorded once
+func mergeProductPromotions(result, group *productPromotions) {
+	result.subTotal += group.subTotal
+	result.freeQuantity += group.freeQuantity
+	result.applied = mergeAppliedPromotions(result.applied, group.applied)
+	for productID, bonuses := range group.bonuses {
+		if result.bonuses == nil {
+			result.bonuses = make(map[int64][]*entity.AppliedPromotion)
+		}
+		result.bonuses[productID] = mergeAppliedPromotions(result.bonuses[productID], bonuses)
+	}
+}
+
+func mergeAppliedPromotions(result, applied []*entity.AppliedPromotion) []*entity.AppliedPromotion {
+	for _, promo := range applied {
+		var found bool
+		for _, existing := range result {
+			if existing.PromotionID == promo.PromotionID {
+				existing.DiscountAmount += promo.DiscountAmount
+				existing.FreeQuantity += promo.FreeQuantity
+				found = true
+				break
+			}
+		}
+		if !found {
+			result = append(result, promo)
+		}
+	}
+	return result
+}
+
+// cost of promotions to the customer, bonus items of checkout products reduce the cost
+func promotionCost(promotions *productPromotions, mapPrice map[int64]entity.Money) entity.Money {
+	result := promotions.subTotal
+	for productID, bonuses := range promotions.bonuses {
+		result -= mapPrice[productID].Multiply(countFreeQuantity(bonuses))
+	}
+	return result
+}
+
+// This function estimates total price of checkout, bonus items are deducted the same way as handleCheckoutFreeItems
+// bonus items of products outside checkout are added for free, so they do not change total price
+func estimateTotalPrice(products []*entity.Product, lines map[int64]*productPromotions) entity.Money {
+	freeQuantity := make(map[int64]int)
+	for _, line := range lines {
+		for productID, bonuses := range line.bonuses {
+			freeQuantity[productID] += countFreeQuantity(bonuses)
+		}
+	}
+
+	var result entity.Money
+	for _, product := range products {
+		subTotal := lines[product.ID].subTotal
+		priceReduction := product.Price.Multiply(freeQuantity[product.ID])
+		if priceReduction > subTotal {
+			priceReduction = subTotal
+		}
+		result += subTotal - priceReduction
+	}
+	return result
+}
+
+// This function sums discount of applied promotions, used to compare promotions
+// bonus items are valued at full price of the promo product
+func (uc *checkoutUsecase) productPromotionsDiscount(promotions *productPromotions, products []*entity.Product, currency string) (entity.Money, error) {
+	var result entity.Money
+	for _, applied := range promotions.applied {
+		result += applied.DiscountAmount
+	}
+	if len(promotions.bonuses) == 0 {
+		return result, nil
+	}
+
+	// price of promo products, from checkout products or repository
+	mapPrice := make(map[int64]entity.Money)
+	for _, product := range products {
+		mapPrice[product.ID] = product.Price
+	}
+	var productIDs []int64
+	for id := range promotions.bonuses {
+		if _, ok := mapPrice[id]; !ok {
+			productIDs = append(productIDs, id)
+		}
+	}
+	if len(productIDs) > 0 {
+		sort.Slice(productIDs, func(i, j int) bool { return productIDs[i] < productIDs[j] })
+		bonusProducts, err := uc.productRepo.GetProductByIDs(productIDs)
+		if err != nil {
+			return 0, entity.NewError(err.Error(), http.StatusInternalServerError)
+		}
+		bonusProducts, err = uc.applyCurrencyPrice(bonusProducts, currency)
+		if err != nil {
+			return 0, err
+		}
+		for _, product := range bonusProducts {
+			mapPrice[product.ID] = product.Price
+		}
+	}
+
+	for id, bonuses := range promotions.bonuses {
+		result += mapPrice[id].Multiply(countFreeQuantity(bonuses))
+	}
+	return result, nil
+}
+
+// This function calculates the free items that will be obtained
+func (uc *checkoutUsecase) handleBonusItemPromotion(quantity int, promo *entity.Promotion) (*entity.AppliedPromotion, bool) {
+	// if promo product id empty or no match quantity, no free item for this promo
+	if promo.PromoProductID == 0 || promo.MatchQuantity <= 0 || promo.PromoValue <= 0 || quantity < promo.MatchQuantity {
+		return nil, false
 	}
 
 	// number of free item will user get
 	numOfFreeItems := (quantity / promo.MatchQuantity) * promo.PromoValue
-	freeProductItem[promo.PromoProductID] += numOfFreeItems
-	return freeProductItem
+	return &entity.AppliedPromotion{
+		PromotionID:  promo.ID,
+		Type:         promo.Type,
+		FreeQuantity: numOfFreeItems,
+	}, true
 }
 
 // This function calculates price reductions that apply multiples
-func (uc *checkoutUsecase) handleReducePricePromotion(quantity int, product *entity.Product, promo *entity.Promotion) float64 {
-	// if match quantity empty, return original price
+// paid items are priced from current sub total, so discount of previous promotions is kept
+func (uc *checkoutUsecase) handleReducePricePromotion(quantity int, currentSubTotal entity.Money, promo *entity.Promotion) (entity.Money, bool) {
+	// if match quantity empty, return current sub total
 	if promo.MatchQuantity <= 0 || quantity < promo.MatchQuantity {
-		return product.Price * float64(quantity)
+		return currentSubTotal, false
 	}
 
 	// get item reduction
 	newQuantity := (quantity / promo.MatchQuantity * promo.PromoValue) + (quantity % promo.MatchQuantity)
-	return product.Price * float64(newQuantity)
+	return currentSubTotal.Ratio(newQuantity, quantity), true
+}
+
+// This function calculates the number of free items of the same product
+// eg: buy 2 get 1 free, every 2 purchased items will get 1 more item for free
+func (uc *checkoutUsecase) handleFreeItemPromotion(quantity int, promo *entity.Promotion) (int, bool) {
+	// if match quantity or promo value empty, no free item for this promo
+	if promo.MatchQuantity <= 0 || promo.PromoValue <= 0 || quantity < promo.MatchQuantity {
+		return 0, false
+	}
+
+	return (quantity / promo.MatchQuantity) * promo.PromoValue, true
 }
 
 // This function calculates the discount price
-func (uc *checkoutUsecase) handleDiscountPromotion(quantity int, currentSubTotal float64, promo *entity.Promotion) float64 {
+// discount amount is rounded half away from zero to the nearest minor unit
+func (uc *checkoutUsecase) handleDiscountPromotion(quantity int, currentSubTotal entity.Money, promo *entity.Promotion) (entity.Money, bool) {
 	// promo value for discount in percent value, only process valid value
-	if promo.PromoValue < 0 || promo.PromoValue > 100 || quantity < promo.MatchQuantity {
-		return currentSubTotal
+	if promo.PromoValue <= 0 || promo.PromoValue > 100 || quantity < promo.MatchQuantity {
+		return currentSubTotal, false
+	}
+
+	return currentSubTotal - currentSubTotal.Percent(promo.PromoValue), true
+}
+
+// This function calculates price by tier of purchased quantity
+// percent tier is applied to current sub total, unit price tier is not applied when current sub total is already lower
+func (uc *checkoutUsecase) handleTieredPricePromotion(quantity, lineQuantity int, currentSubTotal entity.Money, promo *entity.Promotion) (entity.Money, bool) {
+	tier := promo.TierOf(lineQuantity)
+	if tier == nil {
+		return currentSubTotal, false
+	}
+
+	if tier.UnitPrice > 0 {
+		subTotal := tier.UnitPrice.Multiply(quantity)
+		if subTotal >= currentSubTotal {
+			return currentSubTotal, false
+		}
+		return subTotal, true
+	}
+	if tier.Percent <= 0 || tier.Percent > 100 {
+		return currentSubTotal, false
+	}
+	return currentSubTotal - currentSubTotal.Percent(tier.Percent), true
+}
+
+// This function reduces promo price of each item when purchased quantity reaches match quantity
+// sub total never goes below zero
+func (uc *checkoutUsecase) handleAmountDiscountPromotion(quantity, lineQuantity int, currentSubTotal entity.Money, promo *entity.Promotion) (entity.Money, bool) {
+	if promo.PromoPrice <= 0 || lineQuantity < promo.MatchQuantity || currentSubTotal <= 0 {
+		return currentSubTotal, false
+	}
+
+	return currentSubTotal.Deduct(promo.PromoPrice.Multiply(quantity)), true
+}
+
+// This function reduces promo price once of sub total when purchased quantity reaches match quantity
+// sub total never goes below zero
+func (uc *checkoutUsecase) handleLineAmountDiscountPromotion(lineQuantity int, currentSubTotal entity.Money, promo *entity.Promotion) (entity.Money, bool) {
+	if promo.PromoPrice <= 0 || lineQuantity < promo.MatchQuantity || currentSubTotal <= 0 {
+		return currentSubTotal, false
 	}
 
-	return currentSubTotal - (currentSubTotal * float64(promo.PromoValue) / float64(100))
+	return currentSubTotal.Deduct(promo.PromoPrice), true
+}
+
+// This function sets promo price as price of each item when purchased quantity reaches match quantity
+// it is not applied when current sub total is already lower
+func (uc *checkoutUsecase) handleFixedUnitPricePromotion(quantity, lineQuantity int, currentSubTotal entity.Money, promo *entity.Promotion) (entity.Money, bool) {
+	if promo.PromoPrice <= 0 || lineQuantity < promo.MatchQuantity {
+		return currentSubTotal, false
+	}
+
+	subTotal := promo.PromoPrice.Multiply(quantity)
+	if subTotal >= currentSubTotal {
+		return currentSubTotal, false
+	}
+	return subTotal, true
 }
 
 // This will handle free items obtained through promotions
 // If the item is there, the fee will be deducted, if it is not there it will be added to checkout
-func (uc *checkoutUsecase) handleCheckoutFreeItems(checkout *entity.Checkout, freeProductItem map[int64]int) error {
+func (uc *checkoutUsecase) handleCheckoutFreeItems(checkout *entity.Checkout, freeProductItem map[int64][]*entity.AppliedPromotion) error {
 	// check the item in the existing checkout items list
 	for _, item := range checkout.Items {
-		if freeProductItem[item.Product.ID] > 0 {
-			// If the number of items is less than it should be
-			if item.Quantity < freeProductItem[item.Product.ID] {
-				// reduce checkout total price for current quantity
-				checkout.TotalPrice -= float64(item.Quantity) * item.Product.Price
-				// add remaining quantity amount
-				checkout.TotalItem += freeProductItem[item.Product.ID] - item.Quantity
-				item.Quantity = freeProductItem[item.Product.ID]
-				item.SubTotalPrice = 0
-			} else {
-				// if the free items exceed the total items, only reduce the price of the available free items
-				priceReduction := float64(freeProductItem[item.Product.ID]) * item.Product.Price
-				item.SubTotalPrice = item.SubTotalPrice - priceReduction
-				// reduce the sub total price
-				checkout.TotalPrice -= priceReduction
-			}
+		bonuses := freeProductItem[item.Product.ID]
+		if len(bonuses) == 0 {
+			continue
+		}
+
+		numOfFreeItems := countFreeQuantity(bonuses)
+		currentSubTotal := item.SubTotalPrice
+		var addedQuantity int
 
-			// empty free product item
-			freeProductItem[item.Product.ID] = 0
+		// If the number of items is less than it should be
+		if item.Quantity < numOfFreeItems {
+			// reduce checkout total price for current sub total
+			checkout.TotalPrice -= item.SubTotalPrice
+			// add remaining quantity amount
+			addedQuantity = numOfFreeItems - item.Quantity
+			checkout.TotalItem += addedQuantity
+			item.Quantity = numOfFreeItems
+			item.SubTotalPrice = 0
+		} else {
+			// if the free items exceed the total items, only reduce the price of the available free items
+			priceReduction := item.Product.Price.Multiply(numOfFreeItems)
+			// sub total may already be reduced by other promotions
+			if priceReduction > item.SubTotalPrice {
+				priceReduction = item.SubTotalPrice
+			}
+			item.SubTotalPrice = item.SubTotalPrice - priceReduction
+			// reduce the sub total price
+			checkout.TotalPrice -= priceReduction
 		}
+
+		// record bonus promotions with reduced price and price of added items
+		uc.recordBonusPromotions(item, bonuses, currentSubTotal-item.SubTotalPrice+item.Product.Price.Multiply(addedQuantity))
+
+		// empty free product item
+		delete(freeProductItem, item.Product.ID)
 	}
 
 	// if freeProductItem still have quantity, add to checkout
 	var productIDs []int64
-	for id, qty := range freeProductItem {
-		if qty == 0 {
-			continue
-		}
+	for id := range freeProductItem {
 		productIDs = append(productIDs, id)
 	}
 
 	if len(productIDs) == 0 {
 		return nil
 	}
+	sort.Slice(productIDs, func(i, j int) bool { return productIDs[i] < productIDs[j] })
 
 	// get free product
 	products, err := uc.productRepo.GetProductByIDs(productIDs)
 	if err != nil {
 		return entity.NewError(err.Error(), http.StatusInternalServerError)
 	}
+	products, err = uc.applyCurrencyPrice(products, checkout.Currency)
+	if err != nil {
+		return err
+	}
 
 	// append to checkout
 	for _, product := range products {
+		bonuses := freeProductItem[product.ID]
+		numOfFreeItems := countFreeQuantity(bonuses)
+
 		// append new items
-		checkout.Items = append(checkout.Items, &entity.CheckoutItem{
+		item := &entity.CheckoutItem{
 			Product:  product,
-			Quantity: freeProductItem[product.ID],
-		})
+			Quantity: numOfFreeItems,
+		}
+		uc.recordBonusPromotions(item, bonuses, product.Price.Multiply(numOfFreeItems))
+		checkout.Items = append(checkout.Items, item)
+
 		// append checkout total item
-		checkout.TotalItem += freeProductItem[product.ID]
+		checkout.TotalItem += numOfFreeItems
 		// empty free product item
-		freeProductItem[product.ID] = 0
+		delete(freeProductItem, product.ID)
 	}
 
 	return nil
 }
+
+// This will record bonus promotions to the free product item
+// discount is divided in order of bonus promotions, by value of each free items
+func (uc *checkoutUsecase) recordBonusPromotions(item *entity.CheckoutItem, bonuses []*entity.AppliedPromotion, discount entity.Money) {
+	for i, bonus := range bonuses {
+		bonus.DiscountAmount = item.Product.Price.Multiply(bonus.FreeQuantity)
+		if bonus.DiscountAmount > discount || i == len(bonuses)-1 {
+			bonus.DiscountAmount = discount
+		}
+		discount -= bonus.DiscountAmount
+		item.AppliedPromotions = append(item.AppliedPromotions, bonus)
+	}
+}
+
+// This function applies cart level promotions in order of promotion type, percent discount of cart is applied last
+// thresholds are compared with total price after promotions of each product
+func (uc *checkoutUsecase) applyCartPromotions(checkout *entity.Checkout) error {
+	promotions, err := uc.promoRepo.GetCartPromotions(uc.clock.Now())
+	if err != nil {
+		return entity.NewError(err.Error(), http.StatusInternalServerError)
+	}
+	// discount of cart is split to every item, items would not be bundled after it
+	sort.SliceStable(promotions, func(i, j int) bool {
+		return promotions[i].Type != entity.CartDiscountInPercent && promotions[j].Type == entity.CartDiscountInPercent
+	})
+
+	total := checkout.TotalPrice
+	for _, promo := range promotions {
+		// money of promotion is only valid in its currency
+		if promo.Currency != checkout.Currency || total < promo.MinTotal {
+			continue
+		}
+
+		var applied *entity.AppliedPromotion
+		switch promo.Type {
+		case entity.CategoryBundle:
+			applied = uc.handleCategoryBundlePromotion(checkout, promo)
+		case entity.CartBonusItem:
+			applied, err = uc.handleCartBonusItemPromotion(checkout, promo)
+			if err != nil {
+				return err
+			}
+		case entity.CartDiscountInPercent:
+			applied = uc.handleCartDiscountPromotion(checkout, promo)
+		case entity.ProductBundle:
+			applied = uc.handleProductBundlePromotion(checkout, promo)
+		}
+
+		if applied != nil {
+			checkout.Adjustments = append(checkout.Adjustments, applied)
+			checkout.TotalDiscount += applied.DiscountAmount
+		}
+	}
+	return nil
+}
+
+// This function bundles any MatchQuantity items of the category for PromoPrice
+// the most expensive items are bundled first, items with other promotions are not bundled
+func (uc *checkoutUsecase) handleCategoryBundlePromotion(checkout *entity.Checkout, promo *entity.Promotion) *entity.AppliedPromotion {
+	if promo.MatchQuantity <= 0 {
+		return nil
+	}
+
+	var items []*entity.CheckoutItem
+	var quantity int
+	for _, item := range checkout.Items {
+		if item.Product.Category != promo.Category || len(item.AppliedPromotions) > 0 {
+			continue
+		}
+		items = append(items, item)
+		quantity += item.Quantity
+	}
+	numOfBundles := quantity / promo.MatchQuantity
+	if numOfBundles == 0 {
+		return nil
+	}
+	sort.SliceStable(items, func(i, j int) bool { return items[i].Product.Price > items[j].Product.Price })
+
+	// price of bundled items of each checkout item
+	remaining := numOfBundles * promo.MatchQuantity
+	bundledPrices := make([]entity.Money, len(items))
+	var bundledTotal entity.Money
+	for i, item := range items {
+		bundled := item.Quantity
+		if bundled > remaining {
+			bundled = remaining
+		}
+		bundledPrices[i] = item.Product.Price.Multiply(bundled)
+		bundledTotal += bundledPrices[i]
+		remaining -= bundled
+	}
+
+	// bundle price above normal price is not applied
+	discount := bundledTotal - promo.PromoPrice.Multiply(numOfBundles)
+	if discount <= 0 {
+		return nil
+	}
+	uc.splitCartDiscount(checkout, items, bundledPrices, promo, discount)
+	return &entity.AppliedPromotion{PromotionID: promo.ID, Type: promo.Type, DiscountAmount: discount}
+}
+
+// This function bundles required quantity of each product for PromoPrice, or PromoValue percent discount of bundled items
+// as many bundles as possible are applied, items with other promotions are not bundled
+func (uc *checkoutUsecase) handleProductBundlePromotion(checkout *entity.Checkout, promo *entity.Promotion) *entity.AppliedPromotion {
+	if len(promo.Items) == 0 {
+		return nil
+	}
+
+	// map product id with checkout item
+	mapItem := make(map[int64]*entity.CheckoutItem)
+	for _, item := range checkout.Items {
+		if len(item.AppliedPromotions) == 0 {
+			mapItem[item.Product.ID] = item
+		}
+	}
+
+	// number of bundles is limited by product with the least quantity
+	var items []*entity.CheckoutItem
+	numOfBundles := -1
+	for _, bundleItem := range promo.Items {
+		item, ok := mapItem[bundleItem.ProductID]
+		if !ok || bundleItem.Quantity <= 0 {
+			return nil
+		}
+		if n := item.Quantity / bundleItem.Quantity; numOfBundles < 0 || n < numOfBundles {
+			numOfBundles = n
+		}
+		items = append(items, item)
+	}
+	if numOfBundles <= 0 {
+		return nil
+	}
+
+	// price of bundled items of each checkout item
+	bundledPrices := make([]entity.Money, len(items))
+	var bundledTotal entity.Money
+	for i, item := range items {
+		bundledPrices[i] = item.Product.Price.Multiply(promo.Items[i].Quantity * numOfBundles)
+		bundledTotal += bundledPrices[i]
+	}
+
+	discount := bundledTotal.Percent(promo.PromoValue)
+	if promo.PromoPrice > 0 {
+		discount = bundledTotal - promo.PromoPrice.Multiply(numOfBundles)
+	}
+	// bundle price above normal price is not applied
+	if discount <= 0 {
+		return nil
+	}
+	uc.splitCartDiscount(checkout, items, bundledPrices, promo, discount)
+	return &entity.AppliedPromotion{PromotionID: promo.ID, Type: promo.Type, DiscountAmount: discount}
+}
+
+// This function gives PromoValue items of PromoProductID for free, same as product level bonus item
+// items already in checkout are free first, only the missing items are added
+func (uc *checkoutUsecase) handleCartBonusItemPromotion(checkout *entity.Checkout, promo *entity.Promotion) (*entity.AppliedPromotion, error) {
+	bonus := &entity.AppliedPromotion{PromotionID: promo.ID, Type: promo.Type, FreeQuantity: promo.PromoValue}
+	freeProductItem := map[int64][]*entity.AppliedPromotion{promo.PromoProductID: {bonus}}
+	if err := uc.handleCheckoutFreeItems(checkout, freeProductItem); err != nil {
+		return nil, err
+	}
+
+	// free product is not added when it is deleted
+	for _, item := range checkout.Items {
+		if item.Product.ID == promo.PromoProductID {
+			return &entity.AppliedPromotion{
+				PromotionID:    promo.ID,
+				Type:           promo.Type,
+				DiscountAmount: bonus.DiscountAmount,
+				FreeQuantity:   promo.PromoValue,
+			}, nil
+		}
+	}
+	return nil, nil
+}
+
+// This function calculates percent discount of total price
+func (uc *checkoutUsecase) handleCartDiscountPromotion(checkout *entity.Checkout, promo *entity.Promotion) *entity.AppliedPromotion {
+	if promo.PromoValue <= 0 || promo.PromoValue > 100 {
+		return nil
+	}
+
+	discount := checkout.TotalPrice.Percent(promo.PromoValue)
+	if discount <= 0 {
+		return nil
+	}
+
+	var subTotals []entity.Money
+	for _, item := range checkout.Items {
+		subTotals = append(subTotals, item.SubTotalPrice)
+	}
+	uc.splitCartDiscount(checkout, checkout.Items, subTotals, promo, discount)
+	return &entity.AppliedPromotion{PromotionID: promo.ID, Type: promo.Type, DiscountAmount: discount}
+}
+
+// This will split cart level discount to items by weight, so each item is taxed from its discounted sub total
+// share of each item is recorded as applied promotion of the item
+func (uc *checkoutUsecase) splitCartDiscount(checkout *entity.Checkout, items []*entity.CheckoutItem, weights []entity.Money, promo *entity.Promotion, discount entity.Money) {
+	for i, share := range discount.Allocate(weights) {
+		if share == 0 {
+			continue
+		}
+		items[i].SubTotalPrice -= share
+		items[i].AppliedPromotions = append(items[i].AppliedPromotions, &entity.AppliedPromotion{
+			PromotionID:    promo.ID,
+			Type:           promo.Type,
+			DiscountAmount: share,
+		})
+	}
+	checkout.TotalPrice -= discount
+}
+
+// This function validates coupon and splits its discount to items by sub total price
+// usage limits are checked again when the order is submitted, because other orders may redeem the coupon meanwhile
+func (uc *checkoutUsecase) applyCoupon(checkout *entity.Checkout, payload *entity.CheckoutRequest) error {
+	checkout.CustomerID = strings.TrimSpace(payload.CustomerID)
+	code := strings.ToUpper(strings.TrimSpace(payload.CouponCode))
+	if code == "" {
+		return nil
+	}
+
+	coupon, err := uc.promoRepo.GetCouponByCode(code)
+	if err != nil {
+		return entity.NewError(err.Error(), http.StatusInternalServerError)
+	}
+	if coupon == nil {
+		return entity.NewError(entity.CouponNotFound, http.StatusBadRequest)
+	}
+	if !coupon.ActiveAt(uc.clock.Now()) {
+		return entity.NewError(entity.CouponNotActive, http.StatusBadRequest)
+	}
+	if coupon.UsageLimitReached() {
+		return entity.NewError(entity.CouponUsageLimitReached, http.StatusBadRequest)
+	}
+	if coupon.PerCustomerLimit > 0 && checkout.CustomerID == "" {
+		return entity.NewError("customer id is required by this coupon", http.StatusBadRequest)
+	}
+	if coupon.Currency != checkout.Currency {
+		return entity.NewErrorWithDetails(entity.CouponNotApplicable, http.StatusBadRequest, map[string]interface{}{
+			"currency": coupon.Currency,
+		})
+	}
+	if checkout.TotalPrice < coupon.MinTotal {
+		return entity.NewErrorWithDetails(entity.CouponNotApplicable, http.StatusBadRequest, map[string]interface{}{
+			"minTotal": coupon.MinTotal,
+		})
+	}
+
+	// split discount, so each item is taxed from its discounted sub total
+	discount := coupon.Discount(checkout.TotalPrice)
+	var subTotals []entity.Money
+	for _, item := range checkout.Items {
+		subTotals = append(subTotals, item.SubTotalPrice)
+	}
+	for i, share := range discount.Allocate(subTotals) {
+		checkout.Items[i].CouponDiscount = share
+		checkout.Items[i].SubTotalPrice -= share
+	}
+
+	checkout.Coupon = coupon
+	checkout.CouponDiscount = discount
+	checkout.TotalPrice -= discount
+	checkout.TotalDiscount += discount
+	return nil
+}
+
+// quantity of each product must be between 1 and maxItemQuantity
+func validateQuantity(items entity.MapProductSerialQuantity) error {
+	serials := items.PluckSerial()
+	sort.Strings(serials)
+	for _, serial := range serials {
+		if err := validateItemQuantity(serial, items[serial]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func validateItemQuantity(serial string, quantity int) error {
+	if quantity <= 0 {
+		return entity.NewError(entity.EmptyQuantity, http.StatusBadRequest)
+	}
+	if quantity > maxItemQuantity {
+		return entity.NewError(
+			fmt.Sprintf("quantity of %s exceeds maximum of %d items", serial, maxItemQuantity),
+			http.StatusBadRequest)
+	}
+	return nil
+}
+
+// count free items of bonus promotions
+func countFreeQuantity(bonuses []*entity.AppliedPromotion) int {
+	var result int
+	for _, bonus := range bonuses {
+		result += bonus.FreeQuantity
+	}
+	return result
+}
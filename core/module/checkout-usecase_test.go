@@ -1,6 +1,9 @@
 package module_test
 
 import (
+	"context"
+	"math/rand"
+	"net/http"
 	"testing"
 	"time"
 
@@ -12,25 +15,40 @@ import (
 	"github.com/golang/mock/gomock"
 )
 
-func initCheckoutUC(ctrl *gomock.Controller) (module.CheckoutUsecase, *repomocks.MockProductRepo, *repomocks.MockPromotionRepo) {
+// checkout time of all test cases
+var checkoutTime = time.Date(2023, 5, 20, 10, 0, 0, 0, time.UTC)
+
+type fixedClock struct{}
+
+func (fixedClock) Now() time.Time {
+	return checkoutTime
+}
+
+func initCheckoutUC(ctrl *gomock.Controller) (module.CheckoutUsecase, *repomocks.MockProductRepo, *repomocks.MockPromotionRepo, *repomocks.MockTaxRepo) {
 	productRepo := repomocks.NewMockProductRepo(ctrl)
 	promoRepo := repomocks.NewMockPromotionRepo(ctrl)
+	taxRepo := repomocks.NewMockTaxRepo(ctrl)
+	taxCalc := module.NewTaxCalculator(taxRepo, entity.TaxExclusive)
 
-	return module.NewCheckoutUsecase(productRepo, promoRepo), productRepo, promoRepo
+	return module.NewCheckoutUsecase(productRepo, promoRepo, taxCalc, fixedClock{}, "USD", 15*time.Minute, entity.PromotionSequential, 1000), productRepo, promoRepo, taxRepo
 }
 
 func Test_Submit(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
 
-	svc, productRepo, promoRepo := initCheckoutUC(ctrl)
+	svc, productRepo, promoRepo, taxRepo := initCheckoutUC(ctrl)
+	// products in these cases have no tax rate
+	taxRepo.EXPECT().GetTaxRateByCategories(gomock.Any()).Return(nil, nil).AnyTimes()
+	// no cart level promotion in these cases
+	promoRepo.EXPECT().GetCartPromotions(checkoutTime).Return(nil, nil).AnyTimes()
 
 	dayCreated, _ := time.Parse("2006-01-02", "2023-05-16")
 	products := []*entity.Product{
-		{ID: 1, Serial: "120P90", Name: "Google Home", Price: 49.99, UpdatedAt: dayCreated},
-		{ID: 2, Serial: "43N23P", Name: "MacBook Pro", Price: 5399.99, UpdatedAt: dayCreated},
-		{ID: 3, Serial: "A304SD", Name: "Alexa Speaker", Price: 109.50, UpdatedAt: dayCreated},
-		{ID: 4, Serial: "234234", Name: "Raspberry Pi B", Price: 30.00, UpdatedAt: dayCreated},
+		{ID: 1, Serial: "120P90", Name: "Google Home", Price: 4999, UpdatedAt: dayCreated},
+		{ID: 2, Serial: "43N23P", Name: "MacBook Pro", Price: 539999, UpdatedAt: dayCreated},
+		{ID: 3, Serial: "A304SD", Name: "Alexa Speaker", Price: 10950, UpdatedAt: dayCreated},
+		{ID: 4, Serial: "234234", Name: "Raspberry Pi B", Price: 3000, UpdatedAt: dayCreated},
 	}
 	promotions := []*entity.Promotion{
 		{ID: 1, Type: 1, ProductID: 2, MatchQuantity: 1, PromoValue: 1, PromoProductID: 4, UpdatedAt: dayCreated},
@@ -39,13 +57,13 @@ func Test_Submit(t *testing.T) {
 	}
 
 	t.Run("Scanned Items: MacBook Pro, Raspberry Pi B", func(t *testing.T) {
-		payload := entity.MapProductSerialQuantity{"43N23P": 1, "234234": 1}
+		payload := &entity.CheckoutRequest{Items: entity.MapProductSerialQuantity{"43N23P": 1, "234234": 1}}
 		productRepo.EXPECT().GetProductBySerials(gomock.Any()).Return([]*entity.Product{
 			products[1], products[3],
 		}, nil).Times(1)
 		promoRepo.EXPECT().GetPromotionByProducts([]*entity.Product{
 			products[1], products[3],
-		}).Return(map[int64][]*entity.Promotion{
+		}, checkoutTime).Return(map[int64][]*entity.Promotion{
 			2: {promotions[0]},
 		}, nil).Times(1)
 
@@ -54,32 +72,41 @@ func Test_Submit(t *testing.T) {
 				{
 					Product:       products[1],
 					Quantity:      1,
-					SubTotalPrice: 5399.99,
+					SubTotalPrice: 539999,
 				},
 				{
 					Product:       products[3],
 					Quantity:      1,
 					SubTotalPrice: 0,
+					AppliedPromotions: []*entity.AppliedPromotion{
+						{PromotionID: 1, Type: entity.BonusItem, DiscountAmount: 3000, FreeQuantity: 1},
+					},
 				},
 			},
-			TotalItem:  2,
-			TotalPrice: 5399.99,
+			TotalItem:     2,
+			TotalPrice:    539999,
+			GrandTotal:    539999,
+			Currency:      "USD",
+			TotalDiscount: 3000,
 		}
-		productRepo.EXPECT().SubmitCheckout(checkout).Return(nil).Times(1)
+		order := entity.NewOrder(checkout)
+		productRepo.EXPECT().SubmitCheckout(checkout).Return(order, nil).Times(1)
 
 		resp, err := svc.Submit(payload)
 		assert.Nil(t, err)
-		assert.Equal(t, checkout, resp)
+		assert.Equal(t, order, resp)
+		// README example total
+		assert.Equal(t, "5399.99", resp.TotalPrice.String())
 	})
 
 	t.Run("Scanned Items: MacBook Pro, 2 Raspberry Pi B", func(t *testing.T) {
-		payload := entity.MapProductSerialQuantity{"43N23P": 1, "234234": 2}
+		payload := &entity.CheckoutRequest{Items: entity.MapProductSerialQuantity{"43N23P": 1, "234234": 2}}
 		productRepo.EXPECT().GetProductBySerials(gomock.Any()).Return([]*entity.Product{
 			products[1], products[3],
 		}, nil).Times(1)
 		promoRepo.EXPECT().GetPromotionByProducts([]*entity.Product{
 			products[1], products[3],
-		}).Return(map[int64][]*entity.Promotion{
+		}, checkoutTime).Return(map[int64][]*entity.Promotion{
 			2: {promotions[0]},
 		}, nil).Times(1)
 
@@ -88,32 +115,39 @@ func Test_Submit(t *testing.T) {
 				{
 					Product:       products[1],
 					Quantity:      1,
-					SubTotalPrice: 5399.99,
+					SubTotalPrice: 539999,
 				},
 				{
 					Product:       products[3],
 					Quantity:      2,
-					SubTotalPrice: 30,
+					SubTotalPrice: 3000,
+					AppliedPromotions: []*entity.AppliedPromotion{
+						{PromotionID: 1, Type: entity.BonusItem, DiscountAmount: 3000, FreeQuantity: 1},
+					},
 				},
 			},
-			TotalItem:  3,
-			TotalPrice: 5399.99 + 30,
+			TotalItem:     3,
+			TotalPrice:    539999 + 3000,
+			GrandTotal:    539999 + 3000,
+			Currency:      "USD",
+			TotalDiscount: 3000,
 		}
-		productRepo.EXPECT().SubmitCheckout(checkout).Return(nil).Times(1)
+		order := entity.NewOrder(checkout)
+		productRepo.EXPECT().SubmitCheckout(checkout).Return(order, nil).Times(1)
 
 		resp, err := svc.Submit(payload)
 		assert.Nil(t, err)
-		assert.Equal(t, checkout, resp)
+		assert.Equal(t, order, resp)
 	})
 
 	t.Run("Scanned Items: MacBook Pro, without Raspberry Pi B", func(t *testing.T) {
-		payload := entity.MapProductSerialQuantity{"43N23P": 1}
+		payload := &entity.CheckoutRequest{Items: entity.MapProductSerialQuantity{"43N23P": 1}}
 		productRepo.EXPECT().GetProductBySerials(gomock.Any()).Return([]*entity.Product{
 			products[1],
 		}, nil).Times(1)
 		promoRepo.EXPECT().GetPromotionByProducts([]*entity.Product{
 			products[1],
-		}).Return(map[int64][]*entity.Promotion{
+		}, checkoutTime).Return(map[int64][]*entity.Promotion{
 			2: {promotions[0]},
 		}, nil).Times(1)
 		productRepo.EXPECT().GetProductByIDs([]int64{4}).Return([]*entity.Product{
@@ -125,32 +159,39 @@ func Test_Submit(t *testing.T) {
 				{
 					Product:       products[1],
 					Quantity:      1,
-					SubTotalPrice: 5399.99,
+					SubTotalPrice: 539999,
 				},
 				{
 					Product:       products[3],
 					Quantity:      1,
 					SubTotalPrice: 0,
+					AppliedPromotions: []*entity.AppliedPromotion{
+						{PromotionID: 1, Type: entity.BonusItem, DiscountAmount: 3000, FreeQuantity: 1},
+					},
 				},
 			},
-			TotalItem:  2,
-			TotalPrice: 5399.99,
+			TotalItem:     2,
+			TotalPrice:    539999,
+			GrandTotal:    539999,
+			Currency:      "USD",
+			TotalDiscount: 3000,
 		}
-		productRepo.EXPECT().SubmitCheckout(checkout).Return(nil).Times(1)
+		order := entity.NewOrder(checkout)
+		productRepo.EXPECT().SubmitCheckout(checkout).Return(order, nil).Times(1)
 
 		resp, err := svc.Submit(payload)
 		assert.Nil(t, err)
-		assert.Equal(t, checkout, resp)
+		assert.Equal(t, order, resp)
 	})
 
 	t.Run("Scanned Items: 2 MacBook Pro, 1 Raspberry Pi B", func(t *testing.T) {
-		payload := entity.MapProductSerialQuantity{"43N23P": 2, "234234": 1}
+		payload := &entity.CheckoutRequest{Items: entity.MapProductSerialQuantity{"43N23P": 2, "234234": 1}}
 		productRepo.EXPECT().GetProductBySerials(gomock.Any()).Return([]*entity.Product{
 			products[1], products[3],
 		}, nil).Times(1)
 		promoRepo.EXPECT().GetPromotionByProducts([]*entity.Product{
 			products[1], products[3],
-		}).Return(map[int64][]*entity.Promotion{
+		}, checkoutTime).Return(map[int64][]*entity.Promotion{
 			2: {promotions[0]},
 		}, nil).Times(1)
 
@@ -159,32 +200,39 @@ func Test_Submit(t *testing.T) {
 				{
 					Product:       products[1],
 					Quantity:      2,
-					SubTotalPrice: 5399.99 * 2,
+					SubTotalPrice: 539999 * 2,
 				},
 				{
 					Product:       products[3],
 					Quantity:      2,
 					SubTotalPrice: 0,
+					AppliedPromotions: []*entity.AppliedPromotion{
+						{PromotionID: 1, Type: entity.BonusItem, DiscountAmount: 6000, FreeQuantity: 2},
+					},
 				},
 			},
-			TotalItem:  4,
-			TotalPrice: 5399.99 * 2,
+			TotalItem:     4,
+			TotalPrice:    539999 * 2,
+			GrandTotal:    539999 * 2,
+			Currency:      "USD",
+			TotalDiscount: 6000,
 		}
-		productRepo.EXPECT().SubmitCheckout(checkout).Return(nil).Times(1)
+		order := entity.NewOrder(checkout)
+		productRepo.EXPECT().SubmitCheckout(checkout).Return(order, nil).Times(1)
 
 		resp, err := svc.Submit(payload)
 		assert.Nil(t, err)
-		assert.Equal(t, checkout, resp)
+		assert.Equal(t, order, resp)
 	})
 
 	t.Run("Scanned Items: Google Home, Google Home, Google Home", func(t *testing.T) {
-		payload := entity.MapProductSerialQuantity{"120P90": 3}
+		payload := &entity.CheckoutRequest{Items: entity.MapProductSerialQuantity{"120P90": 3}}
 		productRepo.EXPECT().GetProductBySerials(gomock.Any()).Return([]*entity.Product{
 			products[0],
 		}, nil).Times(1)
 		promoRepo.EXPECT().GetPromotionByProducts([]*entity.Product{
 			products[0],
-		}).Return(map[int64][]*entity.Promotion{
+		}, checkoutTime).Return(map[int64][]*entity.Promotion{
 			1: {promotions[1]},
 		}, nil).Times(1)
 
@@ -193,27 +241,36 @@ func Test_Submit(t *testing.T) {
 				{
 					Product:       products[0],
 					Quantity:      3,
-					SubTotalPrice: 49.99 * 2,
+					SubTotalPrice: 4999 * 2,
+					AppliedPromotions: []*entity.AppliedPromotion{
+						{PromotionID: 1, Type: entity.BuyItemsForReducePrice, DiscountAmount: 4999, FreeQuantity: 0},
+					},
 				},
 			},
-			TotalItem:  3,
-			TotalPrice: 49.99 * 2,
+			TotalItem:     3,
+			TotalPrice:    4999 * 2,
+			GrandTotal:    4999 * 2,
+			Currency:      "USD",
+			TotalDiscount: 4999,
 		}
-		productRepo.EXPECT().SubmitCheckout(checkout).Return(nil).Times(1)
+		order := entity.NewOrder(checkout)
+		productRepo.EXPECT().SubmitCheckout(checkout).Return(order, nil).Times(1)
 
 		resp, err := svc.Submit(payload)
 		assert.Nil(t, err)
-		assert.Equal(t, checkout, resp)
+		assert.Equal(t, order, resp)
+		// README example total
+		assert.Equal(t, "99.98", resp.TotalPrice.String())
 	})
 
 	t.Run("Scanned Items: 6 Google Home", func(t *testing.T) {
-		payload := entity.MapProductSerialQuantity{"120P90": 6}
+		payload := &entity.CheckoutRequest{Items: entity.MapProductSerialQuantity{"120P90": 6}}
 		productRepo.EXPECT().GetProductBySerials(gomock.Any()).Return([]*entity.Product{
 			products[0],
 		}, nil).Times(1)
 		promoRepo.EXPECT().GetPromotionByProducts([]*entity.Product{
 			products[0],
-		}).Return(map[int64][]*entity.Promotion{
+		}, checkoutTime).Return(map[int64][]*entity.Promotion{
 			1: {promotions[1]},
 		}, nil).Times(1)
 
@@ -222,27 +279,34 @@ func Test_Submit(t *testing.T) {
 				{
 					Product:       products[0],
 					Quantity:      6,
-					SubTotalPrice: 49.99 * 4,
+					SubTotalPrice: 4999 * 4,
+					AppliedPromotions: []*entity.AppliedPromotion{
+						{PromotionID: 1, Type: entity.BuyItemsForReducePrice, DiscountAmount: 4999 * 2, FreeQuantity: 0},
+					},
 				},
 			},
-			TotalItem:  6,
-			TotalPrice: 49.99 * 4,
+			TotalItem:     6,
+			TotalPrice:    4999 * 4,
+			GrandTotal:    4999 * 4,
+			Currency:      "USD",
+			TotalDiscount: 4999 * 2,
 		}
-		productRepo.EXPECT().SubmitCheckout(checkout).Return(nil).Times(1)
+		order := entity.NewOrder(checkout)
+		productRepo.EXPECT().SubmitCheckout(checkout).Return(order, nil).Times(1)
 
 		resp, err := svc.Submit(payload)
 		assert.Nil(t, err)
-		assert.Equal(t, checkout, resp)
+		assert.Equal(t, order, resp)
 	})
 
 	t.Run("Scanned Items: 4 Google Home", func(t *testing.T) {
-		payload := entity.MapProductSerialQuantity{"120P90": 4}
+		payload := &entity.CheckoutRequest{Items: entity.MapProductSerialQuantity{"120P90": 4}}
 		productRepo.EXPECT().GetProductBySerials(gomock.Any()).Return([]*entity.Product{
 			products[0],
 		}, nil).Times(1)
 		promoRepo.EXPECT().GetPromotionByProducts([]*entity.Product{
 			products[0],
-		}).Return(map[int64][]*entity.Promotion{
+		}, checkoutTime).Return(map[int64][]*entity.Promotion{
 			1: {promotions[1]},
 		}, nil).Times(1)
 
@@ -251,27 +315,34 @@ func Test_Submit(t *testing.T) {
 				{
 					Product:       products[0],
 					Quantity:      4,
-					SubTotalPrice: 49.99 * 3,
+					SubTotalPrice: 4999 * 3,
+					AppliedPromotions: []*entity.AppliedPromotion{
+						{PromotionID: 1, Type: entity.BuyItemsForReducePrice, DiscountAmount: 4999, FreeQuantity: 0},
+					},
 				},
 			},
-			TotalItem:  4,
-			TotalPrice: 49.99 * 3,
+			TotalItem:     4,
+			TotalPrice:    4999 * 3,
+			GrandTotal:    4999 * 3,
+			Currency:      "USD",
+			TotalDiscount: 4999,
 		}
-		productRepo.EXPECT().SubmitCheckout(checkout).Return(nil).Times(1)
+		order := entity.NewOrder(checkout)
+		productRepo.EXPECT().SubmitCheckout(checkout).Return(order, nil).Times(1)
 
 		resp, err := svc.Submit(payload)
 		assert.Nil(t, err)
-		assert.Equal(t, checkout, resp)
+		assert.Equal(t, order, resp)
 	})
 
 	t.Run("Scanned Items: Alexa Speaker, Alexa Speaker, Alexa Speaker", func(t *testing.T) {
-		payload := entity.MapProductSerialQuantity{"A304SD": 3}
+		payload := &entity.CheckoutRequest{Items: entity.MapProductSerialQuantity{"A304SD": 3}}
 		productRepo.EXPECT().GetProductBySerials(gomock.Any()).Return([]*entity.Product{
 			products[2],
 		}, nil).Times(1)
 		promoRepo.EXPECT().GetPromotionByProducts([]*entity.Product{
 			products[2],
-		}).Return(map[int64][]*entity.Promotion{
+		}, checkoutTime).Return(map[int64][]*entity.Promotion{
 			3: {promotions[2]},
 		}, nil).Times(1)
 
@@ -280,27 +351,36 @@ func Test_Submit(t *testing.T) {
 				{
 					Product:       products[2],
 					Quantity:      3,
-					SubTotalPrice: (109.50 * 3) - (109.50 * 3 * 10 / 100),
+					SubTotalPrice: 29565,
+					AppliedPromotions: []*entity.AppliedPromotion{
+						{PromotionID: 3, Type: entity.DiscountInPercent, DiscountAmount: 3285, FreeQuantity: 0},
+					},
 				},
 			},
-			TotalItem:  3,
-			TotalPrice: (109.50 * 3) - (109.50 * 3 * 10 / 100),
+			TotalItem:     3,
+			TotalPrice:    29565,
+			GrandTotal:    29565,
+			Currency:      "USD",
+			TotalDiscount: 3285,
 		}
-		productRepo.EXPECT().SubmitCheckout(checkout).Return(nil).Times(1)
+		order := entity.NewOrder(checkout)
+		productRepo.EXPECT().SubmitCheckout(checkout).Return(order, nil).Times(1)
 
 		resp, err := svc.Submit(payload)
 		assert.Nil(t, err)
-		assert.Equal(t, checkout, resp)
+		assert.Equal(t, order, resp)
+		// README example total
+		assert.Equal(t, "295.65", resp.TotalPrice.String())
 	})
 
 	t.Run("Scanned Items: 4 Alexa Speaker", func(t *testing.T) {
-		payload := entity.MapProductSerialQuantity{"A304SD": 4}
+		payload := &entity.CheckoutRequest{Items: entity.MapProductSerialQuantity{"A304SD": 4}}
 		productRepo.EXPECT().GetProductBySerials(gomock.Any()).Return([]*entity.Product{
 			products[2],
 		}, nil).Times(1)
 		promoRepo.EXPECT().GetPromotionByProducts([]*entity.Product{
 			products[2],
-		}).Return(map[int64][]*entity.Promotion{
+		}, checkoutTime).Return(map[int64][]*entity.Promotion{
 			3: {promotions[2]},
 		}, nil).Times(1)
 
@@ -309,27 +389,34 @@ func Test_Submit(t *testing.T) {
 				{
 					Product:       products[2],
 					Quantity:      4,
-					SubTotalPrice: (109.50 * 4) - (109.50 * 4 * 10 / 100),
+					SubTotalPrice: 39420,
+					AppliedPromotions: []*entity.AppliedPromotion{
+						{PromotionID: 3, Type: entity.DiscountInPercent, DiscountAmount: 4380, FreeQuantity: 0},
+					},
 				},
 			},
-			TotalItem:  4,
-			TotalPrice: (109.50 * 4) - (109.50 * 4 * 10 / 100),
+			TotalItem:     4,
+			TotalPrice:    39420,
+			GrandTotal:    39420,
+			Currency:      "USD",
+			TotalDiscount: 4380,
 		}
-		productRepo.EXPECT().SubmitCheckout(checkout).Return(nil).Times(1)
+		order := entity.NewOrder(checkout)
+		productRepo.EXPECT().SubmitCheckout(checkout).Return(order, nil).Times(1)
 
 		resp, err := svc.Submit(payload)
 		assert.Nil(t, err)
-		assert.Equal(t, checkout, resp)
+		assert.Equal(t, order, resp)
 	})
 
 	t.Run("Scanned Items: 2 Alexa Speaker (don't get discount)", func(t *testing.T) {
-		payload := entity.MapProductSerialQuantity{"A304SD": 2}
+		payload := &entity.CheckoutRequest{Items: entity.MapProductSerialQuantity{"A304SD": 2}}
 		productRepo.EXPECT().GetProductBySerials(gomock.Any()).Return([]*entity.Product{
 			products[2],
 		}, nil).Times(1)
 		promoRepo.EXPECT().GetPromotionByProducts([]*entity.Product{
 			products[2],
-		}).Return(map[int64][]*entity.Promotion{
+		}, checkoutTime).Return(map[int64][]*entity.Promotion{
 			3: {promotions[2]},
 		}, nil).Times(1)
 
@@ -338,16 +425,1776 @@ func Test_Submit(t *testing.T) {
 				{
 					Product:       products[2],
 					Quantity:      2,
-					SubTotalPrice: 109.50 * 2,
+					SubTotalPrice: 10950 * 2,
 				},
 			},
 			TotalItem:  2,
-			TotalPrice: 109.50 * 2,
+			TotalPrice: 10950 * 2,
+			GrandTotal: 10950 * 2,
+			Currency:   "USD",
 		}
-		productRepo.EXPECT().SubmitCheckout(checkout).Return(nil).Times(1)
+		order := entity.NewOrder(checkout)
+		productRepo.EXPECT().SubmitCheckout(checkout).Return(order, nil).Times(1)
 
 		resp, err := svc.Submit(payload)
 		assert.Nil(t, err)
-		assert.Equal(t, checkout, resp)
+		assert.Equal(t, order, resp)
+	})
+}
+
+func Test_SubmitAmountPromotion(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	svc, productRepo, promoRepo, taxRepo := initCheckoutUC(ctrl)
+	// products in these cases have no tax rate
+	taxRepo.EXPECT().GetTaxRateByCategories(gomock.Any()).Return(nil, nil).AnyTimes()
+	// no cart level promotion in these cases
+	promoRepo.EXPECT().GetCartPromotions(checkoutTime).Return(nil, nil).AnyTimes()
+
+	dayCreated, _ := time.Parse("2006-01-02", "2023-05-16")
+	alexa := &entity.Product{ID: 3, Serial: "A304SD", Name: "Alexa Speaker", Price: 10950, UpdatedAt: dayCreated}
+
+	tests := []struct {
+		name     string
+		quantity int
+		promos   []*entity.Promotion
+		subTotal entity.Money
+		applied  []*entity.AppliedPromotion
+	}{
+		{
+			"3 Alexa Speaker, 10.00 off each item", 3,
+			[]*entity.Promotion{{ID: 10, Type: entity.DiscountInAmount, ProductID: 3, MatchQuantity: 1, PromoPrice: 1000, Currency: "USD", UpdatedAt: dayCreated}},
+			10950*3 - 3000,
+			[]*entity.AppliedPromotion{{PromotionID: 10, Type: entity.DiscountInAmount, DiscountAmount: 3000}},
+		},
+		{
+			"3 Alexa Speaker, amount off each item is more than price", 3,
+			[]*entity.Promotion{{ID: 10, Type: entity.DiscountInAmount, ProductID: 3, MatchQuantity: 1, PromoPrice: 15000, Currency: "USD", UpdatedAt: dayCreated}},
+			0,
+			[]*entity.AppliedPromotion{{PromotionID: 10, Type: entity.DiscountInAmount, DiscountAmount: 10950 * 3}},
+		},
+		{
+			"3 Alexa Speaker, 50.00 off when buying 3 items", 3,
+			[]*entity.Promotion{{ID: 11, Type: entity.LineDiscountInAmount, ProductID: 3, MatchQuantity: 3, PromoPrice: 5000, Currency: "USD", UpdatedAt: dayCreated}},
+			10950*3 - 5000,
+			[]*entity.AppliedPromotion{{PromotionID: 11, Type: entity.LineDiscountInAmount, DiscountAmount: 5000}},
+		},
+		{
+			"2 Alexa Speaker, 50.00 off when buying 3 items (don't get discount)", 2,
+			[]*entity.Promotion{{ID: 11, Type: entity.LineDiscountInAmount, ProductID: 3, MatchQuantity: 3, PromoPrice: 5000, Currency: "USD", UpdatedAt: dayCreated}},
+			10950 * 2,
+			nil,
+		},
+		{
+			"3 Alexa Speaker, amount off line is more than sub total", 3,
+			[]*entity.Promotion{{ID: 11, Type: entity.LineDiscountInAmount, ProductID: 3, MatchQuantity: 1, PromoPrice: 50000, Currency: "USD", UpdatedAt: dayCreated}},
+			0,
+			[]*entity.AppliedPromotion{{PromotionID: 11, Type: entity.LineDiscountInAmount, DiscountAmount: 10950 * 3}},
+		},
+		{
+			"2 Alexa Speaker, 99.00 each when buying 2 items", 2,
+			[]*entity.Promotion{{ID: 12, Type: entity.FixedUnitPrice, ProductID: 3, MatchQuantity: 2, PromoPrice: 9900, Currency: "USD", UpdatedAt: dayCreated}},
+			9900 * 2,
+			[]*entity.AppliedPromotion{{PromotionID: 12, Type: entity.FixedUnitPrice, DiscountAmount: 1050 * 2}},
+		},
+		{
+			"2 Alexa Speaker, unit price above list price (don't get discount)", 2,
+			[]*entity.Promotion{{ID: 12, Type: entity.FixedUnitPrice, ProductID: 3, MatchQuantity: 1, PromoPrice: 12000, Currency: "USD", UpdatedAt: dayCreated}},
+			10950 * 2,
+			nil,
+		},
+		{
+			"3 Alexa Speaker, amount off each item stacked after 10% discount", 3,
+			[]*entity.Promotion{
+				{ID: 10, Type: entity.DiscountInAmount, ProductID: 3, MatchQuantity: 1, PromoPrice: 10000, Currency: "USD", UpdatedAt: dayCreated},
+				{ID: 3, Type: entity.DiscountInPercent, ProductID: 3, MatchQuantity: 3, PromoValue: 10, UpdatedAt: dayCreated},
+			},
+			0,
+			[]*entity.AppliedPromotion{
+				{PromotionID: 3, Type: entity.DiscountInPercent, DiscountAmount: 3285},
+				{PromotionID: 10, Type: entity.DiscountInAmount, DiscountAmount: 10950*3 - 3285},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run("Scanned Items: "+tt.name, func(t *testing.T) {
+			payload := &entity.CheckoutRequest{Items: entity.MapProductSerialQuantity{"A304SD": tt.quantity}}
+			productRepo.EXPECT().GetProductBySerials(gomock.Any()).Return([]*entity.Product{alexa}, nil).Times(1)
+			promoRepo.EXPECT().GetPromotionByProducts([]*entity.Product{alexa}, checkoutTime).Return(map[int64][]*entity.Promotion{
+				3: tt.promos,
+			}, nil).Times(1)
+
+			discount := alexa.Price.Multiply(tt.quantity) - tt.subTotal
+			checkout := &entity.Checkout{
+				Items: []*entity.CheckoutItem{
+					{
+						Product:           alexa,
+						Quantity:          tt.quantity,
+						SubTotalPrice:     tt.subTotal,
+						AppliedPromotions: tt.applied,
+					},
+				},
+				TotalItem:     tt.quantity,
+				TotalPrice:    tt.subTotal,
+				GrandTotal:    tt.subTotal,
+				Currency:      "USD",
+				TotalDiscount: discount,
+			}
+			order := entity.NewOrder(checkout)
+			productRepo.EXPECT().SubmitCheckout(checkout).Return(order, nil).Times(1)
+
+			resp, err := svc.Submit(payload)
+			assert.Nil(t, err)
+			assert.Equal(t, order, resp)
+		})
+	}
+}
+
+func Test_SubmitFreeItem(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	svc, productRepo, promoRepo, taxRepo := initCheckoutUC(ctrl)
+	// products in these cases have no tax rate
+	taxRepo.EXPECT().GetTaxRateByCategories(gomock.Any()).Return(nil, nil).AnyTimes()
+	// no cart level promotion in these cases
+	promoRepo.EXPECT().GetCartPromotions(checkoutTime).Return(nil, nil).AnyTimes()
+
+	dayCreated, _ := time.Parse("2006-01-02", "2023-05-16")
+	products := []*entity.Product{
+		{ID: 1, Serial: "120P90", Name: "Google Home", Price: 4999, UpdatedAt: dayCreated},
+		{ID: 2, Serial: "43N23P", Name: "MacBook Pro", Price: 539999, UpdatedAt: dayCreated},
+		{ID: 3, Serial: "A304SD", Name: "Alexa Speaker", Price: 10950, UpdatedAt: dayCreated},
+		{ID: 4, Serial: "234234", Name: "Raspberry Pi B", Price: 3000, UpdatedAt: dayCreated},
+	}
+	promotions := []*entity.Promotion{
+		{ID: 1, Type: 1, ProductID: 2, MatchQuantity: 1, PromoValue: 1, PromoProductID: 4, UpdatedAt: dayCreated},
+		{ID: 2, Type: 2, ProductID: 1, MatchQuantity: 3, PromoValue: 2, PromoProductID: 0, UpdatedAt: dayCreated},
+		{ID: 3, Type: 3, ProductID: 3, MatchQuantity: 3, PromoValue: 10, PromoProductID: 0, UpdatedAt: dayCreated},
+		{ID: 4, Type: 4, ProductID: 1, MatchQuantity: 2, PromoValue: 1, PromoProductID: 0, UpdatedAt: dayCreated},
+		{ID: 5, Type: 4, ProductID: 3, MatchQuantity: 3, PromoValue: 1, PromoProductID: 0, UpdatedAt: dayCreated},
+		{ID: 6, Type: 4, ProductID: 4, MatchQuantity: 1, PromoValue: 1, PromoProductID: 0, UpdatedAt: dayCreated},
+		{ID: 7, Type: 4, ProductID: 2, MatchQuantity: 1, PromoValue: 1, PromoProductID: 0, UpdatedAt: dayCreated},
+	}
+
+	t.Run("Scanned Items: 2 Google Home, buy 2 get 1 free", func(t *testing.T) {
+		payload := &entity.CheckoutRequest{Items: entity.MapProductSerialQuantity{"120P90": 2}}
+		productRepo.EXPECT().GetProductBySerials(gomock.Any()).Return([]*entity.Product{
+			products[0],
+		}, nil).Times(1)
+		promoRepo.EXPECT().GetPromotionByProducts([]*entity.Product{
+			products[0],
+		}, checkoutTime).Return(map[int64][]*entity.Promotion{
+			1: {promotions[3]},
+		}, nil).Times(1)
+
+		checkout := &entity.Checkout{
+			Items: []*entity.CheckoutItem{
+				{
+					Product:       products[0],
+					Quantity:      3,
+					SubTotalPrice: 4999 * 2,
+					AppliedPromotions: []*entity.AppliedPromotion{
+						{PromotionID: 4, Type: entity.FreeItem, DiscountAmount: 4999, FreeQuantity: 1},
+					},
+				},
+			},
+			TotalItem:     3,
+			TotalPrice:    4999 * 2,
+			GrandTotal:    4999 * 2,
+			Currency:      "USD",
+			TotalDiscount: 4999,
+		}
+		order := entity.NewOrder(checkout)
+		productRepo.EXPECT().SubmitCheckout(checkout).Return(order, nil).Times(1)
+
+		resp, err := svc.Submit(payload)
+		assert.Nil(t, err)
+		assert.Equal(t, order, resp)
+	})
+
+	t.Run("Scanned Items: 4 Google Home, buy 2 get 1 free", func(t *testing.T) {
+		payload := &entity.CheckoutRequest{Items: entity.MapProductSerialQuantity{"120P90": 4}}
+		productRepo.EXPECT().GetProductBySerials(gomock.Any()).Return([]*entity.Product{
+			products[0],
+		}, nil).Times(1)
+		promoRepo.EXPECT().GetPromotionByProducts([]*entity.Product{
+			products[0],
+		}, checkoutTime).Return(map[int64][]*entity.Promotion{
+			1: {promotions[3]},
+		}, nil).Times(1)
+
+		checkout := &entity.Checkout{
+			Items: []*entity.CheckoutItem{
+				{
+					Product:       products[0],
+					Quantity:      6,
+					SubTotalPrice: 4999 * 4,
+					AppliedPromotions: []*entity.AppliedPromotion{
+						{PromotionID: 4, Type: entity.FreeItem, DiscountAmount: 4999 * 2, FreeQuantity: 2},
+					},
+				},
+			},
+			TotalItem:     6,
+			TotalPrice:    4999 * 4,
+			GrandTotal:    4999 * 4,
+			Currency:      "USD",
+			TotalDiscount: 4999 * 2,
+		}
+		order := entity.NewOrder(checkout)
+		productRepo.EXPECT().SubmitCheckout(checkout).Return(order, nil).Times(1)
+
+		resp, err := svc.Submit(payload)
+		assert.Nil(t, err)
+		assert.Equal(t, order, resp)
+	})
+
+	t.Run("Scanned Items: 1 Google Home (don't get free item)", func(t *testing.T) {
+		payload := &entity.CheckoutRequest{Items: entity.MapProductSerialQuantity{"120P90": 1}}
+		productRepo.EXPECT().GetProductBySerials(gomock.Any()).Return([]*entity.Product{
+			products[0],
+		}, nil).Times(1)
+		promoRepo.EXPECT().GetPromotionByProducts([]*entity.Product{
+			products[0],
+		}, checkoutTime).Return(map[int64][]*entity.Promotion{
+			1: {promotions[3]},
+		}, nil).Times(1)
+
+		checkout := &entity.Checkout{
+			Items: []*entity.CheckoutItem{
+				{
+					Product:       products[0],
+					Quantity:      1,
+					SubTotalPrice: 4999,
+				},
+			},
+			TotalItem:  1,
+			TotalPrice: 4999,
+			GrandTotal: 4999,
+			Currency:   "USD",
+		}
+		order := entity.NewOrder(checkout)
+		productRepo.EXPECT().SubmitCheckout(checkout).Return(order, nil).Times(1)
+
+		resp, err := svc.Submit(payload)
+		assert.Nil(t, err)
+		assert.Equal(t, order, resp)
+	})
+
+	t.Run("Scanned Items: 3 Google Home, stacked with buy items for reduce price", func(t *testing.T) {
+		payload := &entity.CheckoutRequest{Items: entity.MapProductSerialQuantity{"120P90": 3}}
+		productRepo.EXPECT().GetProductBySerials(gomock.Any()).Return([]*entity.Product{
+			products[0],
+		}, nil).Times(1)
+		promoRepo.EXPECT().GetPromotionByProducts([]*entity.Product{
+			products[0],
+		}, checkoutTime).Return(map[int64][]*entity.Promotion{
+			1: {promotions[1], promotions[3]},
+		}, nil).Times(1)
+
+		// pay 2 of 3 items, and get 1 more item for free
+		checkout := &entity.Checkout{
+			Items: []*entity.CheckoutItem{
+				{
+					Product:       products[0],
+					Quantity:      4,
+					SubTotalPrice: 4999 * 2,
+					AppliedPromotions: []*entity.AppliedPromotion{
+						{PromotionID: 2, Type: entity.BuyItemsForReducePrice, DiscountAmount: 4999, FreeQuantity: 0},
+						{PromotionID: 4, Type: entity.FreeItem, DiscountAmount: 4999, FreeQuantity: 1},
+					},
+				},
+			},
+			TotalItem:     4,
+			TotalPrice:    4999 * 2,
+			GrandTotal:    4999 * 2,
+			Currency:      "USD",
+			TotalDiscount: 4999 + 4999,
+		}
+		order := entity.NewOrder(checkout)
+		productRepo.EXPECT().SubmitCheckout(checkout).Return(order, nil).Times(1)
+
+		resp, err := svc.Submit(payload)
+		assert.Nil(t, err)
+		assert.Equal(t, order, resp)
+	})
+
+	t.Run("Scanned Items: 3 Alexa Speaker, stacked with discount in percent", func(t *testing.T) {
+		payload := &entity.CheckoutRequest{Items: entity.MapProductSerialQuantity{"A304SD": 3}}
+		productRepo.EXPECT().GetProductBySerials(gomock.Any()).Return([]*entity.Product{
+			products[2],
+		}, nil).Times(1)
+		promoRepo.EXPECT().GetPromotionByProducts([]*entity.Product{
+			products[2],
+		}, checkoutTime).Return(map[int64][]*entity.Promotion{
+			3: {promotions[2], promotions[4]},
+		}, nil).Times(1)
+
+		// discount only for purchased items, free item is not charged
+		checkout := &entity.Checkout{
+			Items: []*entity.CheckoutItem{
+				{
+					Product:       products[2],
+					Quantity:      4,
+					SubTotalPrice: 29565,
+					AppliedPromotions: []*entity.AppliedPromotion{
+						{PromotionID: 3, Type: entity.DiscountInPercent, DiscountAmount: 3285, FreeQuantity: 0},
+						{PromotionID: 5, Type: entity.FreeItem, DiscountAmount: 10950, FreeQuantity: 1},
+					},
+				},
+			},
+			TotalItem:     4,
+			TotalPrice:    29565,
+			GrandTotal:    29565,
+			Currency:      "USD",
+			TotalDiscount: 3285 + 10950,
+		}
+		order := entity.NewOrder(checkout)
+		productRepo.EXPECT().SubmitCheckout(checkout).Return(order, nil).Times(1)
+
+		resp, err := svc.Submit(payload)
+		assert.Nil(t, err)
+		assert.Equal(t, order, resp)
+	})
+
+	t.Run("Scanned Items: MacBook Pro, Raspberry Pi B, stacked with bonus item", func(t *testing.T) {
+		payload := &entity.CheckoutRequest{Items: entity.MapProductSerialQuantity{"43N23P": 1, "234234": 1}}
+		productRepo.EXPECT().GetProductBySerials(gomock.Any()).Return([]*entity.Product{
+			products[1], products[3],
+		}, nil).Times(1)
+		promoRepo.EXPECT().GetPromotionByProducts([]*entity.Product{
+			products[1], products[3],
+		}, checkoutTime).Return(map[int64][]*entity.Promotion{
+			2: {promotions[0]},
+			4: {promotions[5]},
+		}, nil).Times(1)
+
+		// raspberry pi is paid by bonus item, and get 1 more for free
+		checkout := &entity.Checkout{
+			Items: []*entity.CheckoutItem{
+				{
+					Product:       products[1],
+					Quantity:      1,
+					SubTotalPrice: 539999,
+				},
+				{
+					Product:       products[3],
+					Quantity:      2,
+					SubTotalPrice: 0,
+					AppliedPromotions: []*entity.AppliedPromotion{
+						{PromotionID: 6, Type: entity.FreeItem, DiscountAmount: 3000, FreeQuantity: 1},
+						{PromotionID: 1, Type: entity.BonusItem, DiscountAmount: 3000, FreeQuantity: 1},
+					},
+				},
+			},
+			TotalItem:     3,
+			TotalPrice:    539999,
+			GrandTotal:    539999,
+			Currency:      "USD",
+			TotalDiscount: 6000,
+		}
+		order := entity.NewOrder(checkout)
+		productRepo.EXPECT().SubmitCheckout(checkout).Return(order, nil).Times(1)
+
+		resp, err := svc.Submit(payload)
+		assert.Nil(t, err)
+		assert.Equal(t, order, resp)
+	})
+
+	t.Run("Scanned Items: MacBook Pro with bonus item and free item", func(t *testing.T) {
+		payload := &entity.CheckoutRequest{Items: entity.MapProductSerialQuantity{"43N23P": 1}}
+		productRepo.EXPECT().GetProductBySerials(gomock.Any()).Return([]*entity.Product{
+			products[1],
+		}, nil).Times(1)
+		promoRepo.EXPECT().GetPromotionByProducts([]*entity.Product{
+			products[1],
+		}, checkoutTime).Return(map[int64][]*entity.Promotion{
+			2: {promotions[0], promotions[6]},
+		}, nil).Times(1)
+		productRepo.EXPECT().GetProductByIDs([]int64{4}).Return([]*entity.Product{
+			products[3],
+		}, nil).Times(1)
+
+		// bonus item only counted from purchased items
+		checkout := &entity.Checkout{
+			Items: []*entity.CheckoutItem{
+				{
+					Product:       products[1],
+					Quantity:      2,
+					SubTotalPrice: 539999,
+					AppliedPromotions: []*entity.AppliedPromotion{
+						{PromotionID: 7, Type: entity.FreeItem, DiscountAmount: 539999, FreeQuantity: 1},
+					},
+				},
+				{
+					Product:       products[3],
+					Quantity:      1,
+					SubTotalPrice: 0,
+					AppliedPromotions: []*entity.AppliedPromotion{
+						{PromotionID: 1, Type: entity.BonusItem, DiscountAmount: 3000, FreeQuantity: 1},
+					},
+				},
+			},
+			TotalItem:     3,
+			TotalPrice:    539999,
+			GrandTotal:    539999,
+			Currency:      "USD",
+			TotalDiscount: 539999 + 3000,
+		}
+		order := entity.NewOrder(checkout)
+		productRepo.EXPECT().SubmitCheckout(checkout).Return(order, nil).Times(1)
+
+		resp, err := svc.Submit(payload)
+		assert.Nil(t, err)
+		assert.Equal(t, order, resp)
+	})
+}
+
+func Test_SubmitPromotionStacking(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	svc, productRepo, promoRepo, taxRepo := initCheckoutUC(ctrl)
+	// products in these cases have no tax rate
+	taxRepo.EXPECT().GetTaxRateByCategories(gomock.Any()).Return(nil, nil).AnyTimes()
+	// no cart level promotion in these cases
+	promoRepo.EXPECT().GetCartPromotions(checkoutTime).Return(nil, nil).AnyTimes()
+
+	dayCreated, _ := time.Parse("2006-01-02", "2023-05-16")
+	products := []*entity.Product{
+		{ID: 1, Serial: "120P90", Name: "Google Home", Price: 4999, UpdatedAt: dayCreated},
+		{ID: 2, Serial: "43N23P", Name: "MacBook Pro", Price: 539999, UpdatedAt: dayCreated},
+		{ID: 3, Serial: "A304SD", Name: "Alexa Speaker", Price: 10950, UpdatedAt: dayCreated},
+		{ID: 4, Serial: "234234", Name: "Raspberry Pi B", Price: 3000, UpdatedAt: dayCreated},
+	}
+	alexaDiscount := &entity.Promotion{ID: 3, Type: entity.DiscountInPercent, ProductID: 3, MatchQuantity: 3, PromoValue: 10, UpdatedAt: dayCreated}
+
+	submitAlexa := func(t *testing.T, promos []*entity.Promotion, item *entity.CheckoutItem) {
+		payload := &entity.CheckoutRequest{Items: entity.MapProductSerialQuantity{"A304SD": 3}}
+		productRepo.EXPECT().GetProductBySerials(gomock.Any()).Return([]*entity.Product{
+			products[2],
+		}, nil).Times(1)
+		promoRepo.EXPECT().GetPromotionByProducts([]*entity.Product{
+			products[2],
+		}, checkoutTime).Return(map[int64][]*entity.Promotion{
+			3: promos,
+		}, nil).Times(1)
+
+		checkout := &entity.Checkout{
+			Items:         []*entity.CheckoutItem{item},
+			TotalItem:     3,
+			TotalPrice:    item.SubTotalPrice,
+			GrandTotal:    item.SubTotalPrice,
+			Currency:      "USD",
+			TotalDiscount: 10950*3 - item.SubTotalPrice,
+		}
+		order := entity.NewOrder(checkout)
+		productRepo.EXPECT().SubmitCheckout(checkout).Return(order, nil).Times(1)
+
+		resp, err := svc.Submit(payload)
+		assert.Nil(t, err)
+		assert.Equal(t, order, resp)
+	}
+
+	t.Run("Scanned Items: 3 Alexa Speaker, exclusive promotion is the best deal", func(t *testing.T) {
+		// 3 for 2 is better than 10% discount, the discount is not stacked
+		submitAlexa(t, []*entity.Promotion{
+			alexaDiscount,
+			{ID: 9, Type: entity.BuyItemsForReducePrice, ProductID: 3, MatchQuantity: 3, PromoValue: 2, Exclusive: true, UpdatedAt: dayCreated},
+		}, &entity.CheckoutItem{
+			Product:       products[2],
+			Quantity:      3,
+			SubTotalPrice: 10950 * 2,
+			AppliedPromotions: []*entity.AppliedPromotion{
+				{PromotionID: 9, Type: entity.BuyItemsForReducePrice, DiscountAmount: 10950},
+			},
+		})
+	})
+
+	t.Run("Scanned Items: 3 Alexa Speaker, stack is better than exclusive promotion", func(t *testing.T) {
+		submitAlexa(t, []*entity.Promotion{
+			{ID: 10, Type: entity.DiscountInPercent, ProductID: 3, MatchQuantity: 1, PromoValue: 5, Exclusive: true, UpdatedAt: dayCreated},
+			alexaDiscount,
+		}, &entity.CheckoutItem{
+			Product:       products[2],
+			Quantity:      3,
+			SubTotalPrice: 29565,
+			AppliedPromotions: []*entity.AppliedPromotion{
+				{PromotionID: 3, Type: entity.DiscountInPercent, DiscountAmount: 3285},
+			},
+		})
+	})
+
+	t.Run("Scanned Items: 3 Alexa Speaker, unmatched reduce price keeps previous discount", func(t *testing.T) {
+		submitAlexa(t, []*entity.Promotion{
+			{ID: 13, Type: entity.BuyItemsForReducePrice, ProductID: 3, MatchQuantity: 5, PromoValue: 4, Priority: 1, UpdatedAt: dayCreated},
+			alexaDiscount,
+		}, &entity.CheckoutItem{
+			Product:       products[2],
+			Quantity:      3,
+			SubTotalPrice: 29565,
+			AppliedPromotions: []*entity.AppliedPromotion{
+				{PromotionID: 3, Type: entity.DiscountInPercent, DiscountAmount: 3285},
+			},
+		})
+	})
+
+	t.Run("Scanned Items: 3 Google Home, stackable promotions are applied by priority", func(t *testing.T) {
+		payload := &entity.CheckoutRequest{Items: entity.MapProductSerialQuantity{"120P90": 3}}
+		productRepo.EXPECT().GetProductBySerials(gomock.Any()).Return([]*entity.Product{
+			products[0],
+		}, nil).Times(1)
+		promoRepo.EXPECT().GetPromotionByProducts([]*entity.Product{
+			products[0],
+		}, checkoutTime).Return(map[int64][]*entity.Promotion{
+			1: {
+				{ID: 2, Type: entity.BuyItemsForReducePrice, ProductID: 1, MatchQuantity: 3, PromoValue: 2, Priority: 1, StackableWith: entity.PromotionIDList{11}, UpdatedAt: dayCreated},
+				{ID: 11, Type: entity.DiscountInPercent, ProductID: 1, MatchQuantity: 1, PromoValue: 10, StackableWith: entity.PromotionIDList{2}, UpdatedAt: dayCreated},
+				// not allowed by promotion 11
+				{ID: 12, Type: entity.FreeItem, ProductID: 1, MatchQuantity: 3, PromoValue: 1, Priority: 2, UpdatedAt: dayCreated},
+			},
+		}, nil).Times(1)
+
+		// 10% discount first, then pay 2 of 3 discounted items
+		checkout := &entity.Checkout{
+			Items: []*entity.CheckoutItem{
+				{
+					Product:       products[0],
+					Quantity:      3,
+					SubTotalPrice: 8998,
+					AppliedPromotions: []*entity.AppliedPromotion{
+						{PromotionID: 11, Type: entity.DiscountInPercent, DiscountAmount: 1500},
+						{PromotionID: 2, Type: entity.BuyItemsForReducePrice, DiscountAmount: 4499},
+					},
+				},
+			},
+			TotalItem:     3,
+			TotalPrice:    8998,
+			GrandTotal:    8998,
+			Currency:      "USD",
+			TotalDiscount: 1500 + 4499,
+		}
+		order := entity.NewOrder(checkout)
+		productRepo.EXPECT().SubmitCheckout(checkout).Return(order, nil).Times(1)
+
+		resp, err := svc.Submit(payload)
+		assert.Nil(t, err)
+		assert.Equal(t, order, resp)
+	})
+
+	t.Run("Scanned Items: MacBook Pro, exclusive bonus item is valued by price of free product", func(t *testing.T) {
+		payload := &entity.CheckoutRequest{Items: entity.MapProductSerialQuantity{"43N23P": 1}}
+		productRepo.EXPECT().GetProductBySerials(gomock.Any()).Return([]*entity.Product{
+			products[1],
+		}, nil).Times(1)
+		promoRepo.EXPECT().GetPromotionByProducts([]*entity.Product{
+			products[1],
+		}, checkoutTime).Return(map[int64][]*entity.Promotion{
+			2: {
+				{ID: 1, Type: entity.BonusItem, ProductID: 2, MatchQuantity: 1, PromoValue: 1, PromoProductID: 4, Exclusive: true, UpdatedAt: dayCreated},
+				{ID: 14, Type: entity.DiscountInPercent, ProductID: 2, MatchQuantity: 1, PromoValue: 1, UpdatedAt: dayCreated},
+			},
+		}, nil).Times(1)
+		// free raspberry pi is worth less than 1% discount of macbook pro
+		productRepo.EXPECT().GetProductByIDs([]int64{4}).Return([]*entity.Product{products[3]}, nil).Times(1)
+
+		checkout := &entity.Checkout{
+			Items: []*entity.CheckoutItem{
+				{
+					Product:       products[1],
+					Quantity:      1,
+					SubTotalPrice: 534599,
+					AppliedPromotions: []*entity.AppliedPromotion{
+						{PromotionID: 14, Type: entity.DiscountInPercent, DiscountAmount: 5400},
+					},
+				},
+			},
+			TotalItem:     1,
+			TotalPrice:    534599,
+			GrandTotal:    534599,
+			Currency:      "USD",
+			TotalDiscount: 5400,
+		}
+		order := entity.NewOrder(checkout)
+		productRepo.EXPECT().SubmitCheckout(checkout).Return(order, nil).Times(1)
+
+		resp, err := svc.Submit(payload)
+		assert.Nil(t, err)
+		assert.Equal(t, order, resp)
+	})
+}
+
+func Test_Quote(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	svc, productRepo, promoRepo, taxRepo := initCheckoutUC(ctrl)
+	// products in these cases have no tax rate
+	taxRepo.EXPECT().GetTaxRateByCategories(gomock.Any()).Return(nil, nil).AnyTimes()
+	// no cart level promotion in these cases
+	promoRepo.EXPECT().GetCartPromotions(checkoutTime).Return(nil, nil).AnyTimes()
+
+	dayCreated, _ := time.Parse("2006-01-02", "2023-05-16")
+	products := []*entity.Product{
+		{ID: 2, Serial: "43N23P", Name: "MacBook Pro", Price: 539999, UpdatedAt: dayCreated},
+		{ID: 4, Serial: "234234", Name: "Raspberry Pi B", Price: 3000, UpdatedAt: dayCreated},
+	}
+	promotions := []*entity.Promotion{
+		{ID: 1, Type: 1, ProductID: 2, MatchQuantity: 1, PromoValue: 1, PromoProductID: 4, UpdatedAt: dayCreated},
+	}
+
+	t.Run("Scanned Items: 3 MacBook Pro, stock is not enough for free items", func(t *testing.T) {
+		payload := &entity.CheckoutRequest{Items: entity.MapProductSerialQuantity{"43N23P": 3}}
+		productRepo.EXPECT().GetProductBySerials(gomock.Any()).Return([]*entity.Product{
+			products[0],
+		}, nil).Times(1)
+		promoRepo.EXPECT().GetPromotionByProducts([]*entity.Product{
+			products[0],
+		}, checkoutTime).Return(map[int64][]*entity.Promotion{
+			2: {promotions[0]},
+		}, nil).Times(1)
+		productRepo.EXPECT().GetProductByIDs([]int64{4}).Return([]*entity.Product{
+			products[1],
+		}, nil).Times(1)
+		productRepo.EXPECT().GetProductQuantities([]int64{2, 4}).Return([]*entity.ProductQuantity{
+			{ID: 2, ProductID: 2, Quantity: 5, UpdatedAt: dayCreated},
+			{ID: 4, ProductID: 4, Quantity: 2, UpdatedAt: dayCreated},
+		}, nil).Times(1)
+		// submit checkout must not be called
+		productRepo.EXPECT().SubmitCheckout(gomock.Any()).Times(0)
+
+		checkout := &entity.Checkout{
+			Items: []*entity.CheckoutItem{
+				{
+					Product:           products[0],
+					Quantity:          3,
+					SubTotalPrice:     539999 * 3,
+					AvailableQuantity: 5,
+				},
+				{
+					Product:  products[1],
+					Quantity: 3,
+					AppliedPromotions: []*entity.AppliedPromotion{
+						{PromotionID: 1, Type: entity.BonusItem, DiscountAmount: 9000, FreeQuantity: 3},
+					},
+					AvailableQuantity: 2,
+				},
+			},
+			TotalItem:     6,
+			TotalPrice:    539999 * 3,
+			GrandTotal:    539999 * 3,
+			Currency:      "USD",
+			TotalDiscount: 9000,
+		}
+
+		resp, err := svc.Quote(payload)
+		assert.Nil(t, err)
+		assert.Equal(t, checkout, resp)
+		assert.True(t, resp.Items[0].InStock())
+		assert.False(t, resp.Items[1].InStock())
+	})
+
+	t.Run("Scanned Items: unknown product", func(t *testing.T) {
+		payload := &entity.CheckoutRequest{Items: entity.MapProductSerialQuantity{"XXXXXX": 1}}
+		productRepo.EXPECT().GetProductBySerials(gomock.Any()).Return(nil, nil).Times(1)
+
+		resp, err := svc.Quote(payload)
+		assert.Nil(t, resp)
+		assert.Equal(t, entity.NewErrorWithDetails(entity.ProductNotFound, http.StatusBadRequest, map[string]interface{}{
+			"serials": []string{"XXXXXX"},
+		}), err)
+	})
+}
+
+func Test_QuoteBestPrice(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	productRepo := repomocks.NewMockProductRepo(ctrl)
+	promoRepo := repomocks.NewMockPromotionRepo(ctrl)
+	taxRepo := repomocks.NewMockTaxRepo(ctrl)
+	taxCalc := module.NewTaxCalculator(taxRepo, entity.TaxExclusive)
+	svc := module.NewCheckoutUsecase(productRepo, promoRepo, taxCalc, fixedClock{}, "USD", 15*time.Minute, entity.PromotionBestPrice, 1000)
+	// products in these cases have no tax rate
+	taxRepo.EXPECT().GetTaxRateByCategories(gomock.Any()).Return(nil, nil).AnyTimes()
+	// no cart level promotion in these cases
+	promoRepo.EXPECT().GetCartPromotions(checkoutTime).Return(nil, nil).AnyTimes()
+
+	dayCreated, _ := time.Parse("2006-01-02", "2023-05-16")
+	googleHome := &entity.Product{ID: 1, Serial: "120P90", Name: "Google Home", Price: 4999, UpdatedAt: dayCreated}
+
+	t.Run("Scanned Items: 4 Google Home, 3 for 2 and 10% discount are not stackable", func(t *testing.T) {
+		payload := &entity.CheckoutRequest{Items: entity.MapProductSerialQuantity{"120P90": 4}}
+		productRepo.EXPECT().GetProductBySerials(gomock.Any()).Return([]*entity.Product{googleHome}, nil).Times(1)
+		promoRepo.EXPECT().GetPromotionByProducts([]*entity.Product{googleHome}, checkoutTime).Return(map[int64][]*entity.Promotion{
+			1: {
+				{ID: 2, Type: entity.BuyItemsForReducePrice, ProductID: 1, MatchQuantity: 3, PromoValue: 2, UpdatedAt: dayCreated},
+				{ID: 11, Type: entity.DiscountInPercent, ProductID: 1, MatchQuantity: 3, PromoValue: 10, StackableWith: entity.PromotionIDList{12}, UpdatedAt: dayCreated},
+			},
+		}, nil).Times(1)
+		productRepo.EXPECT().GetProductQuantities([]int64{1}).Return([]*entity.ProductQuantity{
+			{ID: 1, ProductID: 1, Quantity: 10, UpdatedAt: dayCreated},
+		}, nil).Times(1)
+
+		// sequential pays 3 of 4 items, best price pays 2 of 3 items and gets 10% discount of the 4th item
+		checkout := &entity.Checkout{
+			Items: []*entity.CheckoutItem{
+				{
+					Product:       googleHome,
+					Quantity:      4,
+					SubTotalPrice: 4999*2 + 4499,
+					AppliedPromotions: []*entity.AppliedPromotion{
+						{PromotionID: 2, Type: entity.BuyItemsForReducePrice, DiscountAmount: 4999},
+						{PromotionID: 11, Type: entity.DiscountInPercent, DiscountAmount: 500},
+					},
+					AvailableQuantity: 10,
+				},
+			},
+			TotalItem:     4,
+			TotalPrice:    4999*2 + 4499,
+			GrandTotal:    4999*2 + 4499,
+			Currency:      "USD",
+			TotalDiscount: 4999 + 500,
+		}
+
+		resp, err := svc.Quote(payload)
+		assert.Nil(t, err)
+		assert.Equal(t, checkout, resp)
+	})
+
+	t.Run("Scanned Items: 4 Google Home, line discount in amount is applied once", func(t *testing.T) {
+		payload := &entity.CheckoutRequest{Items: entity.MapProductSerialQuantity{"120P90": 4}}
+		productRepo.EXPECT().GetProductBySerials(gomock.Any()).Return([]*entity.Product{googleHome}, nil).Times(1)
+		promoRepo.EXPECT().GetPromotionByProducts([]*entity.Product{googleHome}, checkoutTime).Return(map[int64][]*entity.Promotion{
+			1: {
+				{ID: 2, Type: entity.BuyItemsForReducePrice, ProductID: 1, MatchQuantity: 3, PromoValue: 2, UpdatedAt: dayCreated},
+				{ID: 11, Type: entity.LineDiscountInAmount, ProductID: 1, MatchQuantity: 1, PromoPrice: 5000, Currency: "USD", UpdatedAt: dayCreated},
+			},
+		}, nil).Times(1)
+		productRepo.EXPECT().GetProductQuantities([]int64{1}).Return([]*entity.ProductQuantity{
+			{ID: 1, ProductID: 1, Quantity: 10, UpdatedAt: dayCreated},
+		}, nil).Times(1)
+
+		// splitting units to other stack must not get the line discount twice
+		checkout := &entity.Checkout{
+			Items: []*entity.CheckoutItem{
+				{
+					Product:       googleHome,
+					Quantity:      4,
+					SubTotalPrice: 4999*3 - 5000,
+					AppliedPromotions: []*entity.AppliedPromotion{
+						{PromotionID: 2, Type: entity.BuyItemsForReducePrice, DiscountAmount: 4999},
+						{PromotionID: 11, Type: entity.LineDiscountInAmount, DiscountAmount: 5000},
+					},
+					AvailableQuantity: 10,
+				},
+			},
+			TotalItem:     4,
+			TotalPrice:    4999*3 - 5000,
+			GrandTotal:    4999*3 - 5000,
+			Currency:      "USD",
+			TotalDiscount: 4999 + 5000,
+		}
+
+		resp, err := svc.Quote(payload)
+		assert.Nil(t, err)
+		assert.Equal(t, checkout, resp)
+	})
+}
+
+// best price mode must never be worse than sequential promotions, for any promotions and search bound
+func Test_QuoteBestPriceNeverWorseThanSequential(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	productRepo := repomocks.NewMockProductRepo(ctrl)
+	promoRepo := repomocks.NewMockPromotionRepo(ctrl)
+	taxRepo := repomocks.NewMockTaxRepo(ctrl)
+	taxCalc := module.NewTaxCalculator(taxRepo, entity.TaxExclusive)
+	sequential := module.NewCheckoutUsecase(productRepo, promoRepo, taxCalc, fixedClock{}, "USD", 15*time.Minute, entity.PromotionSequential, 1000)
+	bestPrices := []module.CheckoutUsecase{
+		module.NewCheckoutUsecase(productRepo, promoRepo, taxCalc, fixedClock{}, "USD", 15*time.Minute, entity.PromotionBestPrice, 1000),
+		// bounded search stops early
+		module.NewCheckoutUsecase(productRepo, promoRepo, taxCalc, fixedClock{}, "USD", 15*time.Minute, entity.PromotionBestPrice, 2),
+		// bound that is not positive falls back to default
+		module.NewCheckoutUsecase(productRepo, promoRepo, taxCalc, fixedClock{}, "USD", 15*time.Minute, entity.PromotionBestPrice, 0),
+	}
+
+	dayCreated, _ := time.Parse("2006-01-02", "2023-05-16")
+	products := []*entity.Product{
+		{ID: 1, Serial: "120P90", Name: "Google Home", Price: 4999, UpdatedAt: dayCreated},
+		{ID: 2, Serial: "43N23P", Name: "MacBook Pro", Price: 539999, UpdatedAt: dayCreated},
+		{ID: 3, Serial: "A304SD", Name: "Alexa Speaker", Price: 10950, UpdatedAt: dayCreated},
+		{ID: 4, Serial: "234234", Name: "Raspberry Pi B", Price: 3000, UpdatedAt: dayCreated},
+	}
+	promotionTypes := []entity.PromotionType{entity.BonusItem, entity.BuyItemsForReducePrice, entity.DiscountInPercent, entity.FreeItem, entity.TieredPrice,
+		entity.DiscountInAmount, entity.LineDiscountInAmount, entity.FixedUnitPrice}
+	var promotionMaps map[int64][]*entity.Promotion
+
+	taxRepo.EXPECT().GetTaxRateByCategories(gomock.Any()).Return(nil, nil).AnyTimes()
+	promoRepo.EXPECT().GetCartPromotions(checkoutTime).Return(nil, nil).AnyTimes()
+	promoRepo.EXPECT().GetPromotionByProducts(gomock.Any(), checkoutTime).DoAndReturn(func([]*entity.Product, time.Time) (map[int64][]*entity.Promotion, error) {
+		return promotionMaps, nil
+	}).AnyTimes()
+	productRepo.EXPECT().GetProductBySerials(gomock.Any()).DoAndReturn(func(serials []string) ([]*entity.Product, error) {
+		var result []*entity.Product
+		for _, product := range products {
+			for _, serial := range serials {
+				if product.Serial == serial {
+					result = append(result, product)
+				}
+			}
+		}
+		return result, nil
+	}).AnyTimes()
+	productRepo.EXPECT().GetProductByIDs(gomock.Any()).DoAndReturn(func(ids []int64) ([]*entity.Product, error) {
+		var result []*entity.Product
+		for _, product := range products {
+			for _, id := range ids {
+				if product.ID == id {
+					result = append(result, product)
+				}
+			}
+		}
+		return result, nil
+	}).AnyTimes()
+	productRepo.EXPECT().GetProductQuantities(gomock.Any()).Return(nil, nil).AnyTimes()
+
+	// number of cases that best price is cheaper, for each search bound
+	cheaper := make([]int, len(bestPrices))
+	random := rand.New(rand.NewSource(20230520))
+	for i := 0; i < 500; i++ {
+		// random items
+		payload := &entity.CheckoutRequest{Items: entity.MapProductSerialQuantity{}}
+		for _, product := range products {
+			if random.Intn(2) == 0 || (product.ID == 4 && len(payload.Items) == 0) {
+				payload.Items[product.Serial] = random.Intn(12) + 1
+			}
+		}
+
+		// random promotions of all products
+		promotionMaps = make(map[int64][]*entity.Promotion)
+		var id int64
+		for _, product := range products {
+			for n := random.Intn(4); n > 0; n-- {
+				id++
+				promo := &entity.Promotion{
+					ID:            id,
+					Type:          promotionTypes[random.Intn(len(promotionTypes))],
+					ProductID:     product.ID,
+					MatchQuantity: random.Intn(4) + 1,
+					Priority:      random.Intn(3),
+					Exclusive:     random.Intn(6) == 0,
+				}
+				switch promo.Type {
+				case entity.BonusItem:
+					promo.PromoProductID = int64(random.Intn(4) + 1)
+					promo.PromoValue = random.Intn(2) + 1
+				case entity.BuyItemsForReducePrice:
+					promo.PromoValue = random.Intn(promo.MatchQuantity)
+				case entity.DiscountInPercent:
+					promo.PromoValue = random.Intn(100) + 1
+				case entity.FreeItem:
+					promo.PromoValue = random.Intn(2) + 1
+				case entity.TieredPrice:
+					// tiers of either percent or unit price
+					unitPrice := random.Intn(2) == 0
+					if unitPrice {
+						promo.Currency = "USD"
+					}
+					minQuantity := 0
+					for n := random.Intn(3) + 1; n > 0; n-- {
+						minQuantity += random.Intn(4) + 1
+						tier := &entity.PromotionTier{MinQuantity: minQuantity, Percent: random.Intn(100) + 1}
+						if unitPrice {
+							tier = &entity.PromotionTier{MinQuantity: minQuantity, UnitPrice: entity.Money(random.Int63n(int64(product.Price)) + 1)}
+						}
+						promo.Tiers = append(promo.Tiers, tier)
+					}
+				case entity.DiscountInAmount, entity.LineDiscountInAmount, entity.FixedUnitPrice:
+					// amount may exceed product price, sub total never goes below zero
+					promo.Currency = "USD"
+					promo.PromoPrice = entity.Money(random.Int63n(int64(product.Price)*2) + 1)
+				}
+				if !promo.Exclusive && random.Intn(3) == 0 {
+					promo.StackableWith = entity.PromotionIDList{int64(random.Intn(12) + 1)}
+				}
+				promotionMaps[product.ID] = append(promotionMaps[product.ID], promo)
+			}
+		}
+
+		expected, err := sequential.Quote(payload)
+		assert.Nil(t, err)
+		for n, svc := range bestPrices {
+			resp, err := svc.Quote(payload)
+			assert.Nil(t, err)
+
+			var subTotal entity.Money
+			for _, item := range resp.Items {
+				subTotal += item.SubTotalPrice
+				assert.GreaterOrEqual(t, int64(item.SubTotalPrice), int64(0))
+			}
+			assert.Equal(t, subTotal, resp.TotalPrice, "case %d", i)
+			assert.LessOrEqual(t, int64(resp.TotalPrice), int64(expected.TotalPrice), "case %d", i)
+			if resp.TotalPrice < expected.TotalPrice {
+				cheaper[n]++
+			}
+		}
+	}
+	for n := range bestPrices {
+		assert.Greater(t, cheaper[n], 0, "best price usecase %d", n)
+	}
+}
+
+func Test_QuoteTieredPrice(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	svc, productRepo, promoRepo, taxRepo := initCheckoutUC(ctrl)
+	// products in these cases have no tax rate
+	taxRepo.EXPECT().GetTaxRateByCategories(gomock.Any()).Return(nil, nil).AnyTimes()
+	// no cart level promotion in these cases
+	promoRepo.EXPECT().GetCartPromotions(checkoutTime).Return(nil, nil).AnyTimes()
+
+	dayCreated, _ := time.Parse("2006-01-02", "2023-05-16")
+	raspberry := &entity.Product{ID: 4, Serial: "234234", Name: "Raspberry Pi B", Price: 3000, UpdatedAt: dayCreated}
+	// 1-9 units list price, 10-49 units 5% off, 50+ units 12% off
+	percentTiers := &entity.Promotion{ID: 9, Type: entity.TieredPrice, ProductID: 4, Tiers: []*entity.PromotionTier{
+		{ID: 1, PromotionID: 9, MinQuantity: 10, Percent: 5},
+		{ID: 2, PromotionID: 9, MinQuantity: 50, Percent: 12},
+	}, UpdatedAt: dayCreated}
+	// 20+ units for 25.00 each in USD
+	unitPriceTiers := &entity.Promotion{ID: 10, Type: entity.TieredPrice, ProductID: 4, Currency: "USD", Tiers: []*entity.PromotionTier{
+		{ID: 3, PromotionID: 10, MinQuantity: 20, UnitPrice: 2500},
+	}, UpdatedAt: dayCreated}
+
+	tests := []struct {
+		name     string
+		quantity int
+		promo    *entity.Promotion
+		subTotal entity.Money
+	}{
+		{"9 Raspberry Pi B, list price", 9, percentTiers, 3000 * 9},
+		{"10 Raspberry Pi B, 5% off", 10, percentTiers, 3000 * 10 * 95 / 100},
+		{"49 Raspberry Pi B, 5% off", 49, percentTiers, 3000 * 49 * 95 / 100},
+		{"50 Raspberry Pi B, 12% off", 50, percentTiers, 3000 * 50 * 88 / 100},
+		{"20 Raspberry Pi B, unit price tier", 20, unitPriceTiers, 2500 * 20},
+	}
+	for _, tt := range tests {
+		t.Run("Scanned Items: "+tt.name, func(t *testing.T) {
+			payload := &entity.CheckoutRequest{Items: entity.MapProductSerialQuantity{"234234": tt.quantity}}
+			productRepo.EXPECT().GetProductBySerials(gomock.Any()).Return([]*entity.Product{raspberry}, nil).Times(1)
+			promoRepo.EXPECT().GetPromotionByProducts([]*entity.Product{raspberry}, checkoutTime).Return(map[int64][]*entity.Promotion{
+				4: {tt.promo},
+			}, nil).Times(1)
+			productRepo.EXPECT().GetProductQuantities([]int64{4}).Return([]*entity.ProductQuantity{
+				{ID: 4, ProductID: 4, Quantity: 100, UpdatedAt: dayCreated},
+			}, nil).Times(1)
+
+			var applied []*entity.AppliedPromotion
+			discount := raspberry.Price.Multiply(tt.quantity) - tt.subTotal
+			if discount > 0 {
+				applied = []*entity.AppliedPromotion{{PromotionID: tt.promo.ID, Type: entity.TieredPrice, DiscountAmount: discount}}
+			}
+			checkout := &entity.Checkout{
+				Items: []*entity.CheckoutItem{
+					{
+						Product:           raspberry,
+						Quantity:          tt.quantity,
+						SubTotalPrice:     tt.subTotal,
+						AppliedPromotions: applied,
+						AvailableQuantity: 100,
+					},
+				},
+				TotalItem:     tt.quantity,
+				TotalPrice:    tt.subTotal,
+				GrandTotal:    tt.subTotal,
+				Currency:      "USD",
+				TotalDiscount: discount,
+			}
+
+			resp, err := svc.Quote(payload)
+			assert.Nil(t, err)
+			assert.Equal(t, checkout, resp)
+		})
+	}
+
+	t.Run("Scanned Items: 20 Raspberry Pi B in EUR, unit price tier is only for USD", func(t *testing.T) {
+		payload := &entity.CheckoutRequest{Items: entity.MapProductSerialQuantity{"234234": 20}, Currency: "EUR"}
+		productRepo.EXPECT().GetProductBySerials(gomock.Any()).Return([]*entity.Product{raspberry}, nil).Times(1)
+		productRepo.EXPECT().GetProductPrices([]int64{4}, "EUR").Return([]*entity.ProductPrice{
+			{ID: 4, ProductID: 4, Currency: "EUR", Price: 2750, UpdatedAt: dayCreated},
+		}, nil).Times(1)
+
+		eurProduct := *raspberry
+		eurProduct.Price = 2750
+		promoRepo.EXPECT().GetPromotionByProducts([]*entity.Product{&eurProduct}, checkoutTime).Return(map[int64][]*entity.Promotion{
+			4: {unitPriceTiers},
+		}, nil).Times(1)
+		productRepo.EXPECT().GetProductQuantities([]int64{4}).Return([]*entity.ProductQuantity{
+			{ID: 4, ProductID: 4, Quantity: 100, UpdatedAt: dayCreated},
+		}, nil).Times(1)
+
+		checkout := &entity.Checkout{
+			Items: []*entity.CheckoutItem{
+				{
+					Product:           &eurProduct,
+					Quantity:          20,
+					SubTotalPrice:     2750 * 20,
+					AvailableQuantity: 100,
+				},
+			},
+			TotalItem:  20,
+			TotalPrice: 2750 * 20,
+			GrandTotal: 2750 * 20,
+			Currency:   "EUR",
+		}
+
+		resp, err := svc.Quote(payload)
+		assert.Nil(t, err)
+		assert.Equal(t, checkout, resp)
+	})
+}
+
+func Test_SubmitCurrency(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	svc, productRepo, promoRepo, taxRepo := initCheckoutUC(ctrl)
+	// products in these cases have no tax rate
+	taxRepo.EXPECT().GetTaxRateByCategories(gomock.Any()).Return(nil, nil).AnyTimes()
+	// no cart level promotion in these cases
+	promoRepo.EXPECT().GetCartPromotions(checkoutTime).Return(nil, nil).AnyTimes()
+
+	dayCreated, _ := time.Parse("2006-01-02", "2023-05-16")
+	products := []*entity.Product{
+		{ID: 1, Serial: "120P90", Name: "Google Home", Price: 4999, UpdatedAt: dayCreated},
+		{ID: 2, Serial: "43N23P", Name: "MacBook Pro", Price: 539999, UpdatedAt: dayCreated},
+		{ID: 4, Serial: "234234", Name: "Raspberry Pi B", Price: 3000, UpdatedAt: dayCreated},
+	}
+	promotions := []*entity.Promotion{
+		{ID: 1, Type: 1, ProductID: 2, MatchQuantity: 1, PromoValue: 1, PromoProductID: 4, UpdatedAt: dayCreated},
+		{ID: 2, Type: 2, ProductID: 1, MatchQuantity: 3, PromoValue: 2, PromoProductID: 0, UpdatedAt: dayCreated},
+	}
+
+	t.Run("Scanned Items: 3 Google Home in EUR", func(t *testing.T) {
+		payload := &entity.CheckoutRequest{Items: entity.MapProductSerialQuantity{"120P90": 3}, Currency: "eur"}
+		productRepo.EXPECT().GetProductBySerials(gomock.Any()).Return([]*entity.Product{
+			products[0],
+		}, nil).Times(1)
+		productRepo.EXPECT().GetProductPrices([]int64{1}, "EUR").Return([]*entity.ProductPrice{
+			{ID: 1, ProductID: 1, Currency: "EUR", Price: 4550, UpdatedAt: dayCreated},
+		}, nil).Times(1)
+
+		eurProduct := *products[0]
+		eurProduct.Price = 4550
+		promoRepo.EXPECT().GetPromotionByProducts([]*entity.Product{
+			&eurProduct,
+		}, checkoutTime).Return(map[int64][]*entity.Promotion{
+			1: {promotions[1]},
+		}, nil).Times(1)
+
+		checkout := &entity.Checkout{
+			Items: []*entity.CheckoutItem{
+				{
+					Product:       &eurProduct,
+					Quantity:      3,
+					SubTotalPrice: 4550 * 2,
+					AppliedPromotions: []*entity.AppliedPromotion{
+						{PromotionID: 2, Type: entity.BuyItemsForReducePrice, DiscountAmount: 4550},
+					},
+				},
+			},
+			TotalItem:     3,
+			TotalPrice:    4550 * 2,
+			GrandTotal:    4550 * 2,
+			TotalDiscount: 4550,
+			Currency:      "EUR",
+		}
+		order := entity.NewOrder(checkout)
+		productRepo.EXPECT().SubmitCheckout(checkout).Return(order, nil).Times(1)
+
+		resp, err := svc.Submit(payload)
+		assert.Nil(t, err)
+		assert.Equal(t, order, resp)
+		assert.Equal(t, "EUR", resp.Currency)
+		// fixture price must not be changed
+		assert.Equal(t, entity.Money(4999), products[0].Price)
+	})
+
+	t.Run("Scanned Items: MacBook Pro in EUR, bonus item has no EUR price", func(t *testing.T) {
+		payload := &entity.CheckoutRequest{Items: entity.MapProductSerialQuantity{"43N23P": 1}, Currency: "EUR"}
+		productRepo.EXPECT().GetProductBySerials(gomock.Any()).Return([]*entity.Product{
+			products[1],
+		}, nil).Times(1)
+		productRepo.EXPECT().GetProductPrices([]int64{2}, "EUR").Return([]*entity.ProductPrice{
+			{ID: 2, ProductID: 2, Currency: "EUR", Price: 499999, UpdatedAt: dayCreated},
+		}, nil).Times(1)
+		promoRepo.EXPECT().GetPromotionByProducts(gomock.Any(), checkoutTime).Return(map[int64][]*entity.Promotion{
+			2: {promotions[0]},
+		}, nil).Times(1)
+		productRepo.EXPECT().GetProductByIDs([]int64{4}).Return([]*entity.Product{
+			products[2],
+		}, nil).Times(1)
+		productRepo.EXPECT().GetProductPrices([]int64{4}, "EUR").Return(nil, nil).Times(1)
+		productRepo.EXPECT().SubmitCheckout(gomock.Any()).Times(0)
+
+		resp, err := svc.Submit(payload)
+		assert.Nil(t, resp)
+		assert.Equal(t, entity.NewError("product Raspberry Pi B(234234) has no price in EUR", http.StatusBadRequest), err)
+	})
+
+	t.Run("invalid currency", func(t *testing.T) {
+		payload := &entity.CheckoutRequest{Items: entity.MapProductSerialQuantity{"120P90": 1}, Currency: "EURO"}
+
+		resp, err := svc.Submit(payload)
+		assert.Nil(t, resp)
+		assert.Equal(t, entity.NewError(entity.InvalidCurrency, http.StatusBadRequest), err)
+	})
+}
+
+func Test_Reserve(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	svc, productRepo, promoRepo, taxRepo := initCheckoutUC(ctrl)
+	// products in these cases have no tax rate
+	taxRepo.EXPECT().GetTaxRateByCategories(gomock.Any()).Return(nil, nil).AnyTimes()
+	// no cart level promotion in these cases
+	promoRepo.EXPECT().GetCartPromotions(checkoutTime).Return(nil, nil).AnyTimes()
+
+	dayCreated, _ := time.Parse("2006-01-02", "2023-05-16")
+	products := []*entity.Product{
+		{ID: 2, Serial: "43N23P", Name: "MacBook Pro", Price: 539999, UpdatedAt: dayCreated},
+		{ID: 4, Serial: "234234", Name: "Raspberry Pi B", Price: 3000, UpdatedAt: dayCreated},
+	}
+	promotions := []*entity.Promotion{
+		{ID: 1, Type: 1, ProductID: 2, MatchQuantity: 1, PromoValue: 1, PromoProductID: 4, UpdatedAt: dayCreated},
+	}
+
+	t.Run("Scanned Items: MacBook Pro, reserve bonus item too", func(t *testing.T) {
+		payload := &entity.CheckoutRequest{Items: entity.MapProductSerialQuantity{"43N23P": 1}}
+		productRepo.EXPECT().GetProductBySerials(gomock.Any()).Return([]*entity.Product{
+			products[0],
+		}, nil).Times(1)
+		promoRepo.EXPECT().GetPromotionByProducts([]*entity.Product{
+			products[0],
+		}, checkoutTime).Return(map[int64][]*entity.Promotion{
+			2: {promotions[0]},
+		}, nil).Times(1)
+		productRepo.EXPECT().GetProductByIDs([]int64{4}).Return([]*entity.Product{
+			products[1],
+		}, nil).Times(1)
+
+		reservation := &entity.Reservation{
+			ExpiresAt: checkoutTime.Add(15 * time.Minute),
+			Items: []*entity.ReservationItem{
+				{ProductID: 2, Quantity: 1, Product: products[0]},
+				{ProductID: 4, Quantity: 1, Product: products[1]},
+			},
+		}
+		productRepo.EXPECT().ReserveStock(reservation).Return(nil).Times(1)
+
+		resp, err := svc.Reserve(payload)
+		assert.Nil(t, err)
+		assert.Equal(t, reservation, resp)
+	})
+
+	t.Run("Submit with reservation", func(t *testing.T) {
+		payload := &entity.CheckoutRequest{Items: entity.MapProductSerialQuantity{"234234": 1}, ReservationID: 5}
+		productRepo.EXPECT().GetProductBySerials(gomock.Any()).Return([]*entity.Product{
+			products[1],
+		}, nil).Times(1)
+		promoRepo.EXPECT().GetPromotionByProducts(gomock.Any(), checkoutTime).Return(nil, nil).Times(1)
+
+		checkout := &entity.Checkout{
+			Items: []*entity.CheckoutItem{
+				{Product: products[1], Quantity: 1, SubTotalPrice: 3000},
+			},
+			TotalItem:     1,
+			TotalPrice:    3000,
+			GrandTotal:    3000,
+			Currency:      "USD",
+			ReservationID: 5,
+			SubmittedAt:   checkoutTime,
+		}
+		productRepo.EXPECT().SubmitCheckout(checkout).
+			Return(nil, entity.NewError(entity.ReservationNotActive, http.StatusBadRequest)).Times(1)
+
+		resp, err := svc.Submit(payload)
+		assert.Nil(t, resp)
+		assert.Equal(t, entity.NewError(entity.ReservationNotActive, http.StatusBadRequest), err)
+	})
+
+	t.Run("Sweep expired reservations at current time", func(t *testing.T) {
+		productRepo.EXPECT().ReleaseExpiredReservations(checkoutTime).Return(int64(2), nil).Times(1)
+
+		total, err := module.NewReservationSweeper(productRepo, fixedClock{}, time.Minute).Sweep()
+		assert.Nil(t, err)
+		assert.Equal(t, int64(2), total)
+	})
+
+	t.Run("Sweeper with interval that is not positive falls back to default", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		// ticker of zero interval would panic, run returns on cancelled context
+		assert.NotPanics(t, func() {
+			module.NewReservationSweeper(productRepo, fixedClock{}, 0).Run(ctx)
+		})
+	})
+}
+
+func Test_SubmitQuantity(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	svc, productRepo, _, _ := initCheckoutUC(ctrl)
+
+	t.Run("invalid quantity", func(t *testing.T) {
+		tests := []struct {
+			name    string
+			items   entity.MapProductSerialQuantity
+			message string
+		}{
+			{"zero quantity", entity.MapProductSerialQuantity{"120P90": 1, "43N23P": 0}, entity.EmptyQuantity},
+			{"negative quantity", entity.MapProductSerialQuantity{"120P90": -2}, entity.EmptyQuantity},
+			{"above maximum per line", entity.MapProductSerialQuantity{"120P90": 1001}, "quantity of 120P90 exceeds maximum of 1000 items"},
+		}
+		// repository must not be called
+		productRepo.EXPECT().GetProductBySerials(gomock.Any()).Times(0)
+		for _, tt := range tests {
+			resp, err := svc.Submit(&entity.CheckoutRequest{Items: tt.items})
+			assert.Nil(t, resp, tt.name)
+			assert.Equal(t, entity.NewError(tt.message, http.StatusBadRequest), err, tt.name)
+		}
+	})
+}
+
+func Test_SubmitUnknownSerial(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	svc, productRepo, promoRepo, taxRepo := initCheckoutUC(ctrl)
+	// products in these cases have no tax rate
+	taxRepo.EXPECT().GetTaxRateByCategories(gomock.Any()).Return(nil, nil).AnyTimes()
+	// no cart level promotion in these cases
+	promoRepo.EXPECT().GetCartPromotions(checkoutTime).Return(nil, nil).AnyTimes()
+
+	dayCreated, _ := time.Parse("2006-01-02", "2023-05-16")
+	products := []*entity.Product{
+		{ID: 1, Serial: "120P90", Name: "Google Home", Price: 4999, UpdatedAt: dayCreated},
+		{ID: 4, Serial: "234234", Name: "Raspberry Pi B", Price: 3000, UpdatedAt: dayCreated},
+	}
+
+	t.Run("strict, list every unknown serial", func(t *testing.T) {
+		payload := &entity.CheckoutRequest{Items: entity.MapProductSerialQuantity{"120P90": 1, "XXXXXX": 1, "234234": 1, "AAAAAA": 2}}
+		productRepo.EXPECT().GetProductBySerials(gomock.Any()).Return(products, nil).Times(1)
+		productRepo.EXPECT().SubmitCheckout(gomock.Any()).Times(0)
+
+		resp, err := svc.Submit(payload)
+		assert.Nil(t, resp)
+		assert.Equal(t, entity.NewErrorWithDetails(entity.ProductNotFound, http.StatusBadRequest, map[string]interface{}{
+			"serials": []string{"AAAAAA", "XXXXXX"},
+		}), err)
+	})
+
+	t.Run("strict, serial with different letter case is unknown", func(t *testing.T) {
+		payload := &entity.CheckoutRequest{Items: entity.MapProductSerialQuantity{"120p90": 1}}
+		// database collation is case insensitive
+		productRepo.EXPECT().GetProductBySerials(gomock.Any()).Return(products[:1], nil).Times(1)
+
+		resp, err := svc.Submit(payload)
+		assert.Nil(t, resp)
+		assert.Equal(t, entity.NewErrorWithDetails(entity.ProductNotFound, http.StatusBadRequest, map[string]interface{}{
+			"serials": []string{"120p90"},
+		}), err)
+	})
+
+	t.Run("lenient, return unknown serial with order", func(t *testing.T) {
+		payload := &entity.CheckoutRequest{Items: entity.MapProductSerialQuantity{"120P90": 1, "XXXXXX": 1}, Lenient: true}
+		productRepo.EXPECT().GetProductBySerials(gomock.Any()).Return(products[:1], nil).Times(1)
+		promoRepo.EXPECT().GetPromotionByProducts(products[:1], checkoutTime).Return(nil, nil).Times(1)
+
+		checkout := &entity.Checkout{
+			Items: []*entity.CheckoutItem{
+				{Product: products[0], Quantity: 1, SubTotalPrice: 4999},
+			},
+			TotalItem:      1,
+			TotalPrice:     4999,
+			GrandTotal:     4999,
+			Currency:       "USD",
+			UnknownSerials: []string{"XXXXXX"},
+		}
+		order := entity.NewOrder(checkout)
+		productRepo.EXPECT().SubmitCheckout(checkout).Return(order, nil).Times(1)
+
+		resp, err := svc.Submit(payload)
+		assert.Nil(t, err)
+		assert.Equal(t, []string{"XXXXXX"}, resp.UnknownSerials)
+	})
+
+	t.Run("lenient, quote without any known serial", func(t *testing.T) {
+		payload := &entity.CheckoutRequest{Items: entity.MapProductSerialQuantity{"XXXXXX": 1, "AAAAAA": 1}, Lenient: true}
+		productRepo.EXPECT().GetProductBySerials(gomock.Any()).Return(nil, nil).Times(1)
+
+		resp, err := svc.Quote(payload)
+		assert.Nil(t, err)
+		assert.Equal(t, &entity.Checkout{Currency: "USD", UnknownSerials: []string{"AAAAAA", "XXXXXX"}}, resp)
+	})
+
+	t.Run("lenient, submit without any known serial", func(t *testing.T) {
+		payload := &entity.CheckoutRequest{Items: entity.MapProductSerialQuantity{"XXXXXX": 1}, Lenient: true}
+		productRepo.EXPECT().GetProductBySerials(gomock.Any()).Return(nil, nil).Times(1)
+		productRepo.EXPECT().SubmitCheckout(gomock.Any()).Times(0)
+
+		resp, err := svc.Submit(payload)
+		assert.Nil(t, resp)
+		assert.Equal(t, entity.NewErrorWithDetails(entity.ProductNotFound, http.StatusBadRequest, map[string]interface{}{
+			"serials": []string{"XXXXXX"},
+		}), err)
+	})
+}
+
+func Test_SubmitCoupon(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	svc, productRepo, promoRepo, taxRepo := initCheckoutUC(ctrl)
+	// products in these cases have no tax rate
+	taxRepo.EXPECT().GetTaxRateByCategories(gomock.Any()).Return(nil, nil).AnyTimes()
+	// no cart level promotion in these cases
+	promoRepo.EXPECT().GetCartPromotions(checkoutTime).Return(nil, nil).AnyTimes()
+
+	dayCreated, _ := time.Parse("2006-01-02", "2023-05-16")
+	products := []*entity.Product{
+		{ID: 1, Serial: "120P90", Name: "Google Home", Price: 4999, UpdatedAt: dayCreated},
+		{ID: 4, Serial: "234234", Name: "Raspberry Pi B", Price: 3000, UpdatedAt: dayCreated},
+	}
+	expired := checkoutTime.Add(-time.Hour)
+
+	t.Run("positive, fixed amount is split to items by sub total", func(t *testing.T) {
+		payload := &entity.CheckoutRequest{Items: entity.MapProductSerialQuantity{"120P90": 1, "234234": 1}, CouponCode: " save20 "}
+		productRepo.EXPECT().GetProductBySerials(gomock.Any()).Return(products, nil).Times(1)
+		promoRepo.EXPECT().GetPromotionByProducts(products, checkoutTime).Return(nil, nil).Times(1)
+		coupon := &entity.Coupon{ID: 2, Code: "SAVE20", Type: entity.CouponFixedAmount, Amount: 2000, MinTotal: 5000, Currency: "USD"}
+		promoRepo.EXPECT().GetCouponByCode("SAVE20").Return(coupon, nil).Times(1)
+
+		// remaining minor unit of 20.00 * 49.99 / 79.99 is given to first item
+		checkout := &entity.Checkout{
+			Items: []*entity.CheckoutItem{
+				{Product: products[0], Quantity: 1, SubTotalPrice: 3749, CouponDiscount: 1250},
+				{Product: products[1], Quantity: 1, SubTotalPrice: 2250, CouponDiscount: 750},
+			},
+			TotalItem:      2,
+			TotalPrice:     5999,
+			TotalDiscount:  2000,
+			GrandTotal:     5999,
+			Currency:       "USD",
+			Coupon:         coupon,
+			CouponDiscount: 2000,
+		}
+		order := entity.NewOrder(checkout)
+		productRepo.EXPECT().SubmitCheckout(checkout).Return(order, nil).Times(1)
+
+		resp, err := svc.Submit(payload)
+		assert.Nil(t, err)
+		assert.Equal(t, order, resp)
+		assert.Equal(t, "SAVE20", resp.CouponCode)
+	})
+
+	t.Run("positive, percent coupon with customer", func(t *testing.T) {
+		payload := &entity.CheckoutRequest{Items: entity.MapProductSerialQuantity{"120P90": 2}, CouponCode: "WELCOME10", CustomerID: "customer-1"}
+		productRepo.EXPECT().GetProductBySerials(gomock.Any()).Return(products[:1], nil).Times(1)
+		promoRepo.EXPECT().GetPromotionByProducts(products[:1], checkoutTime).Return(nil, nil).Times(1)
+		coupon := &entity.Coupon{ID: 1, Code: "WELCOME10", Type: entity.CouponPercent, Percent: 10, Currency: "USD", UsageLimit: 100, PerCustomerLimit: 1, UsedCount: 99}
+		promoRepo.EXPECT().GetCouponByCode("WELCOME10").Return(coupon, nil).Times(1)
+
+		checkout := &entity.Checkout{
+			Items: []*entity.CheckoutItem{
+				{Product: products[0], Quantity: 2, SubTotalPrice: 8998, CouponDiscount: 1000},
+			},
+			TotalItem:      2,
+			TotalPrice:     8998,
+			TotalDiscount:  1000,
+			GrandTotal:     8998,
+			Currency:       "USD",
+			Coupon:         coupon,
+			CouponDiscount: 1000,
+			CustomerID:     "customer-1",
+		}
+		order := entity.NewOrder(checkout)
+		productRepo.EXPECT().SubmitCheckout(checkout).Return(order, nil).Times(1)
+
+		resp, err := svc.Submit(payload)
+		assert.Nil(t, err)
+		assert.Equal(t, order, resp)
+	})
+
+	t.Run("negative, coupon cannot be applied", func(t *testing.T) {
+		tests := []struct {
+			name     string
+			coupon   *entity.Coupon
+			customer string
+			err      error
+		}{
+			{"not found", nil, "", entity.NewError(entity.CouponNotFound, http.StatusBadRequest)},
+			{
+				"expired",
+				&entity.Coupon{ID: 1, Type: entity.CouponFixedAmount, Amount: 1000, Currency: "USD", EndsAt: &expired},
+				"",
+				entity.NewError(entity.CouponNotActive, http.StatusBadRequest),
+			},
+			{
+				"usage limit is reached",
+				&entity.Coupon{ID: 1, Type: entity.CouponFixedAmount, Amount: 1000, Currency: "USD", UsageLimit: 10, UsedCount: 10},
+				"",
+				entity.NewError(entity.CouponUsageLimitReached, http.StatusBadRequest),
+			},
+			{
+				"customer is required",
+				&entity.Coupon{ID: 1, Type: entity.CouponFixedAmount, Amount: 1000, Currency: "USD", PerCustomerLimit: 1},
+				" ",
+				entity.NewError("customer id is required by this coupon", http.StatusBadRequest),
+			},
+			{
+				"other currency",
+				&entity.Coupon{ID: 1, Type: entity.CouponFixedAmount, Amount: 1000, Currency: "EUR"},
+				"",
+				entity.NewErrorWithDetails(entity.CouponNotApplicable, http.StatusBadRequest, map[string]interface{}{"currency": "EUR"}),
+			},
+			{
+				"below minimum total",
+				&entity.Coupon{ID: 1, Type: entity.CouponFixedAmount, Amount: 1000, MinTotal: 10000, Currency: "USD"},
+				"",
+				entity.NewErrorWithDetails(entity.CouponNotApplicable, http.StatusBadRequest, map[string]interface{}{"minTotal": entity.Money(10000)}),
+			},
+		}
+		for _, tt := range tests {
+			payload := &entity.CheckoutRequest{Items: entity.MapProductSerialQuantity{"120P90": 1}, CouponCode: "XXXXXX", CustomerID: tt.customer}
+			productRepo.EXPECT().GetProductBySerials(gomock.Any()).Return(products[:1], nil).Times(1)
+			promoRepo.EXPECT().GetPromotionByProducts(products[:1], checkoutTime).Return(nil, nil).Times(1)
+			promoRepo.EXPECT().GetCouponByCode("XXXXXX").Return(tt.coupon, nil).Times(1)
+
+			resp, err := svc.Submit(payload)
+			assert.Nil(t, resp, tt.name)
+			assert.Equal(t, tt.err, err, tt.name)
+		}
+	})
+}
+
+func Test_SubmitCartPromotion(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	svc, productRepo, promoRepo, taxRepo := initCheckoutUC(ctrl)
+	// products in these cases have no tax rate
+	taxRepo.EXPECT().GetTaxRateByCategories(gomock.Any()).Return(nil, nil).AnyTimes()
+
+	dayCreated, _ := time.Parse("2006-01-02", "2023-05-16")
+	products := []*entity.Product{
+		{ID: 1, Serial: "120P90", Name: "Google Home", Price: 4999, Category: "smart-home", UpdatedAt: dayCreated},
+		{ID: 2, Serial: "43N23P", Name: "MacBook Pro", Price: 539999, Category: "computer", UpdatedAt: dayCreated},
+		{ID: 3, Serial: "A304SD", Name: "Alexa Speaker", Price: 10950, Category: "smart-home", UpdatedAt: dayCreated},
+		{ID: 4, Serial: "234234", Name: "Raspberry Pi B", Price: 3000, Category: "computer", UpdatedAt: dayCreated},
+	}
+	promotions := []*entity.Promotion{
+		{ID: 5, Type: entity.CategoryBundle, MatchQuantity: 3, PromoPrice: 14000, Category: "smart-home", Currency: "USD", UpdatedAt: dayCreated},
+		{ID: 6, Type: entity.CartBonusItem, PromoValue: 1, PromoProductID: 4, MinTotal: 100000, Currency: "USD", UpdatedAt: dayCreated},
+		{ID: 7, Type: entity.CartDiscountInPercent, PromoValue: 5, MinTotal: 50000, Currency: "USD", UpdatedAt: dayCreated},
+		{ID: 8, Type: entity.CartDiscountInPercent, PromoValue: 50, Currency: "EUR", UpdatedAt: dayCreated},
+		{ID: 9, Type: entity.ProductBundle, PromoPrice: 14000, Currency: "USD", UpdatedAt: dayCreated, Items: []*entity.PromotionItem{
+			{ID: 1, PromotionID: 9, ProductID: 1, Quantity: 1},
+			{ID: 2, PromotionID: 9, ProductID: 3, Quantity: 1},
+		}},
+		{ID: 10, Type: entity.ProductBundle, PromoValue: 10, Currency: "USD", UpdatedAt: dayCreated, Items: []*entity.PromotionItem{
+			{ID: 3, PromotionID: 10, ProductID: 2, Quantity: 1},
+			{ID: 4, PromotionID: 10, ProductID: 4, Quantity: 2},
+		}},
+	}
+
+	t.Run("spend 500 get 5% off, split to items by sub total", func(t *testing.T) {
+		payload := &entity.CheckoutRequest{Items: entity.MapProductSerialQuantity{"120P90": 1, "A304SD": 5}}
+		productRepo.EXPECT().GetProductBySerials(gomock.Any()).Return([]*entity.Product{products[0], products[2]}, nil).Times(1)
+		promoRepo.EXPECT().GetPromotionByProducts(gomock.Any(), checkoutTime).Return(nil, nil).Times(1)
+		// other currency promotion is skipped
+		promoRepo.EXPECT().GetCartPromotions(checkoutTime).Return([]*entity.Promotion{promotions[2], promotions[3]}, nil).Times(1)
+
+		// 5% of 597.49 is 29.87, remaining minor unit is given to first item
+		checkout := &entity.Checkout{
+			Items: []*entity.CheckoutItem{
+				{Product: products[0], Quantity: 1, SubTotalPrice: 4749, AppliedPromotions: []*entity.AppliedPromotion{
+					{PromotionID: 7, Type: entity.CartDiscountInPercent, DiscountAmount: 250},
+				}},
+				{Product: products[2], Quantity: 5, SubTotalPrice: 52013, AppliedPromotions: []*entity.AppliedPromotion{
+					{PromotionID: 7, Type: entity.CartDiscountInPercent, DiscountAmount: 2737},
+				}},
+			},
+			TotalItem:     6,
+			TotalPrice:    56762,
+			TotalDiscount: 2987,
+			GrandTotal:    56762,
+			Currency:      "USD",
+			Adjustments: []*entity.AppliedPromotion{
+				{PromotionID: 7, Type: entity.CartDiscountInPercent, DiscountAmount: 2987},
+			},
+		}
+		order := entity.NewOrder(checkout)
+		productRepo.EXPECT().SubmitCheckout(checkout).Return(order, nil).Times(1)
+
+		resp, err := svc.Submit(payload)
+		assert.Nil(t, err)
+		assert.Equal(t, order, resp)
+	})
+
+	t.Run("free raspberry pi on orders over 1000", func(t *testing.T) {
+		payload := &entity.CheckoutRequest{Items: entity.MapProductSerialQuantity{"43N23P": 1}}
+		productRepo.EXPECT().GetProductBySerials(gomock.Any()).Return(products[1:2], nil).Times(1)
+		promoRepo.EXPECT().GetPromotionByProducts(products[1:2], checkoutTime).Return(nil, nil).Times(1)
+		promoRepo.EXPECT().GetCartPromotions(checkoutTime).Return(promotions[1:2], nil).Times(1)
+		productRepo.EXPECT().GetProductByIDs([]int64{4}).Return(products[3:], nil).Times(1)
+
+		checkout := &entity.Checkout{
+			Items: []*entity.CheckoutItem{
+				{Product: products[1], Quantity: 1, SubTotalPrice: 539999},
+				{Product: products[3], Quantity: 1, AppliedPromotions: []*entity.AppliedPromotion{
+					{PromotionID: 6, Type: entity.CartBonusItem, DiscountAmount: 3000, FreeQuantity: 1},
+				}},
+			},
+			TotalItem:     2,
+			TotalPrice:    539999,
+			TotalDiscount: 3000,
+			GrandTotal:    539999,
+			Currency:      "USD",
+			Adjustments: []*entity.AppliedPromotion{
+				{PromotionID: 6, Type: entity.CartBonusItem, DiscountAmount: 3000, FreeQuantity: 1},
+			},
+		}
+		order := entity.NewOrder(checkout)
+		productRepo.EXPECT().SubmitCheckout(checkout).Return(order, nil).Times(1)
+
+		resp, err := svc.Submit(payload)
+		assert.Nil(t, err)
+		assert.Equal(t, order, resp)
+	})
+
+	t.Run("free raspberry pi on orders over 1000, target already in cart", func(t *testing.T) {
+		payload := &entity.CheckoutRequest{Items: entity.MapProductSerialQuantity{"43N23P": 1, "234234": 1}}
+		productRepo.EXPECT().GetProductBySerials(gomock.Any()).Return([]*entity.Product{products[1], products[3]}, nil).Times(1)
+		promoRepo.EXPECT().GetPromotionByProducts(gomock.Any(), checkoutTime).Return(nil, nil).Times(1)
+		promoRepo.EXPECT().GetCartPromotions(checkoutTime).Return(promotions[1:2], nil).Times(1)
+
+		// raspberry pi in cart is free, no other raspberry pi is added
+		checkout := &entity.Checkout{
+			Items: []*entity.CheckoutItem{
+				{Product: products[1], Quantity: 1, SubTotalPrice: 539999},
+				{Product: products[3], Quantity: 1, AppliedPromotions: []*entity.AppliedPromotion{
+					{PromotionID: 6, Type: entity.CartBonusItem, DiscountAmount: 3000, FreeQuantity: 1},
+				}},
+			},
+			TotalItem:     2,
+			TotalPrice:    539999,
+			TotalDiscount: 3000,
+			GrandTotal:    539999,
+			Currency:      "USD",
+			Adjustments: []*entity.AppliedPromotion{
+				{PromotionID: 6, Type: entity.CartBonusItem, DiscountAmount: 3000, FreeQuantity: 1},
+			},
+		}
+		order := entity.NewOrder(checkout)
+		productRepo.EXPECT().SubmitCheckout(checkout).Return(order, nil).Times(1)
+
+		resp, err := svc.Submit(payload)
+		assert.Nil(t, err)
+		assert.Equal(t, order, resp)
+	})
+
+	t.Run("any 3 smart home items for 140, the most expensive items are bundled", func(t *testing.T) {
+		payload := &entity.CheckoutRequest{Items: entity.MapProductSerialQuantity{"120P90": 2, "A304SD": 2}}
+		productRepo.EXPECT().GetProductBySerials(gomock.Any()).Return([]*entity.Product{products[0], products[2]}, nil).Times(1)
+		promoRepo.EXPECT().GetPromotionByProducts(gomock.Any(), checkoutTime).Return(nil, nil).Times(1)
+		promoRepo.EXPECT().GetCartPromotions(checkoutTime).Return(promotions[:1], nil).Times(1)
+
+		// 2 Alexa Speakers and 1 Google Home are bundled, 268.99 - 140.00 is 128.99
+		checkout := &entity.Checkout{
+			Items: []*entity.CheckoutItem{
+				{Product: products[0], Quantity: 2, SubTotalPrice: 7601, AppliedPromotions: []*entity.AppliedPromotion{
+					{PromotionID: 5, Type: entity.CategoryBundle, DiscountAmount: 2397},
+				}},
+				{Product: products[2], Quantity: 2, SubTotalPrice: 11398, AppliedPromotions: []*entity.AppliedPromotion{
+					{PromotionID: 5, Type: entity.CategoryBundle, DiscountAmount: 10502},
+				}},
+			},
+			TotalItem:     4,
+			TotalPrice:    18999,
+			TotalDiscount: 12899,
+			GrandTotal:    18999,
+			Currency:      "USD",
+			Adjustments: []*entity.AppliedPromotion{
+				{PromotionID: 5, Type: entity.CategoryBundle, DiscountAmount: 12899},
+			},
+		}
+		order := entity.NewOrder(checkout)
+		productRepo.EXPECT().SubmitCheckout(checkout).Return(order, nil).Times(1)
+
+		resp, err := svc.Submit(payload)
+		assert.Nil(t, err)
+		assert.Equal(t, order, resp)
+	})
+
+	t.Run("google home and alexa speaker for 140", func(t *testing.T) {
+		payload := &entity.CheckoutRequest{Items: entity.MapProductSerialQuantity{"120P90": 2, "A304SD": 1}}
+		productRepo.EXPECT().GetProductBySerials(gomock.Any()).Return([]*entity.Product{products[0], products[2]}, nil).Times(1)
+		promoRepo.EXPECT().GetPromotionByProducts(gomock.Any(), checkoutTime).Return(nil, nil).Times(1)
+		promoRepo.EXPECT().GetCartPromotions(checkoutTime).Return(promotions[4:5], nil).Times(1)
+
+		// 1 bundle of 159.49 for 140.00, the 2nd Google Home is not bundled
+		checkout := &entity.Checkout{
+			Items: []*entity.CheckoutItem{
+				{Product: products[0], Quantity: 2, SubTotalPrice: 9387, AppliedPromotions: []*entity.AppliedPromotion{
+					{PromotionID: 9, Type: entity.ProductBundle, DiscountAmount: 611},
+				}},
+				{Product: products[2], Quantity: 1, SubTotalPrice: 9612, AppliedPromotions: []*entity.AppliedPromotion{
+					{PromotionID: 9, Type: entity.ProductBundle, DiscountAmount: 1338},
+				}},
+			},
+			TotalItem:     3,
+			TotalPrice:    18999,
+			TotalDiscount: 1949,
+			GrandTotal:    18999,
+			Currency:      "USD",
+			Adjustments: []*entity.AppliedPromotion{
+				{PromotionID: 9, Type: entity.ProductBundle, DiscountAmount: 1949},
+			},
+		}
+		order := entity.NewOrder(checkout)
+		productRepo.EXPECT().SubmitCheckout(checkout).Return(order, nil).Times(1)
+
+		resp, err := svc.Submit(payload)
+		assert.Nil(t, err)
+		assert.Equal(t, order, resp)
+	})
+
+	t.Run("google home and alexa speaker for 140, then 1% off", func(t *testing.T) {
+		payload := &entity.CheckoutRequest{Items: entity.MapProductSerialQuantity{"120P90": 1, "A304SD": 1}}
+		productRepo.EXPECT().GetProductBySerials(gomock.Any()).Return([]*entity.Product{products[0], products[2]}, nil).Times(1)
+		promoRepo.EXPECT().GetPromotionByProducts(gomock.Any(), checkoutTime).Return(nil, nil).Times(1)
+		promoRepo.EXPECT().GetCartPromotions(checkoutTime).Return([]*entity.Promotion{
+			{ID: 11, Type: entity.CartDiscountInPercent, PromoValue: 1, Currency: "USD", UpdatedAt: dayCreated},
+			promotions[4],
+		}, nil).Times(1)
+
+		// bundle is applied first, 1% of 140.00 is 1.40
+		checkout := &entity.Checkout{
+			Items: []*entity.CheckoutItem{
+				{Product: products[0], Quantity: 1, SubTotalPrice: 4344, AppliedPromotions: []*entity.AppliedPromotion{
+					{PromotionID: 9, Type: entity.ProductBundle, DiscountAmount: 611},
+					{PromotionID: 11, Type: entity.CartDiscountInPercent, DiscountAmount: 44},
+				}},
+				{Product: products[2], Quantity: 1, SubTotalPrice: 9516, AppliedPromotions: []*entity.AppliedPromotion{
+					{PromotionID: 9, Type: entity.ProductBundle, DiscountAmount: 1338},
+					{PromotionID: 11, Type: entity.CartDiscountInPercent, DiscountAmount: 96},
+				}},
+			},
+			TotalItem:     2,
+			TotalPrice:    13860,
+			TotalDiscount: 2089,
+			GrandTotal:    13860,
+			Currency:      "USD",
+			Adjustments: []*entity.AppliedPromotion{
+				{PromotionID: 9, Type: entity.ProductBundle, DiscountAmount: 1949},
+				{PromotionID: 11, Type: entity.CartDiscountInPercent, DiscountAmount: 140},
+			},
+		}
+		order := entity.NewOrder(checkout)
+		productRepo.EXPECT().SubmitCheckout(checkout).Return(order, nil).Times(1)
+
+		resp, err := svc.Submit(payload)
+		assert.Nil(t, err)
+		assert.Equal(t, order, resp)
+	})
+
+	t.Run("10% off macbook pro with 2 raspberry pi", func(t *testing.T) {
+		payload := &entity.CheckoutRequest{Items: entity.MapProductSerialQuantity{"43N23P": 1, "234234": 3}}
+		productRepo.EXPECT().GetProductBySerials(gomock.Any()).Return([]*entity.Product{products[1], products[3]}, nil).Times(1)
+		promoRepo.EXPECT().GetPromotionByProducts(gomock.Any(), checkoutTime).Return(nil, nil).Times(1)
+		// smart home bundle has no items in checkout
+		promoRepo.EXPECT().GetCartPromotions(checkoutTime).Return(promotions[4:], nil).Times(1)
+
+		// 10% of 5459.99 is 546.00
+		checkout := &entity.Checkout{
+			Items: []*entity.CheckoutItem{
+				{Product: products[1], Quantity: 1, SubTotalPrice: 485999, AppliedPromotions: []*entity.AppliedPromotion{
+					{PromotionID: 10, Type: entity.ProductBundle, DiscountAmount: 54000},
+				}},
+				{Product: products[3], Quantity: 3, SubTotalPrice: 8400, AppliedPromotions: []*entity.AppliedPromotion{
+					{PromotionID: 10, Type: entity.ProductBundle, DiscountAmount: 600},
+				}},
+			},
+			TotalItem:     4,
+			TotalPrice:    494399,
+			TotalDiscount: 54600,
+			GrandTotal:    494399,
+			Currency:      "USD",
+			Adjustments: []*entity.AppliedPromotion{
+				{PromotionID: 10, Type: entity.ProductBundle, DiscountAmount: 54600},
+			},
+		}
+		order := entity.NewOrder(checkout)
+		productRepo.EXPECT().SubmitCheckout(checkout).Return(order, nil).Times(1)
+
+		resp, err := svc.Submit(payload)
+		assert.Nil(t, err)
+		assert.Equal(t, order, resp)
+	})
+
+	t.Run("invalid cart level promotions are skipped", func(t *testing.T) {
+		payload := &entity.CheckoutRequest{Items: entity.MapProductSerialQuantity{"120P90": 1}}
+		productRepo.EXPECT().GetProductBySerials(gomock.Any()).Return(products[0:1], nil).Times(1)
+		promoRepo.EXPECT().GetPromotionByProducts(products[0:1], checkoutTime).Return(nil, nil).Times(1)
+		promoRepo.EXPECT().GetCartPromotions(checkoutTime).Return([]*entity.Promotion{
+			{ID: 11, Type: entity.CategoryBundle, MatchQuantity: 0, PromoPrice: 1000, Category: "smart-home", Currency: "USD", UpdatedAt: dayCreated},
+			{ID: 12, Type: entity.CartDiscountInPercent, PromoValue: 150, Currency: "USD", UpdatedAt: dayCreated},
+		}, nil).Times(1)
+
+		checkout := &entity.Checkout{
+			Items: []*entity.CheckoutItem{
+				{Product: products[0], Quantity: 1, SubTotalPrice: 4999},
+			},
+			TotalItem:  1,
+			TotalPrice: 4999,
+			GrandTotal: 4999,
+			Currency:   "USD",
+		}
+		order := entity.NewOrder(checkout)
+		productRepo.EXPECT().SubmitCheckout(checkout).Return(order, nil).Times(1)
+
+		resp, err := svc.Submit(payload)
+		assert.Nil(t, err)
+		assert.Equal(t, order, resp)
+	})
+
+	t.Run("below minimum total, no cart level promotion", func(t *testing.T) {
+		payload := &entity.CheckoutRequest{Items: entity.MapProductSerialQuantity{"234234": 1}}
+		productRepo.EXPECT().GetProductBySerials(gomock.Any()).Return(products[3:], nil).Times(1)
+		promoRepo.EXPECT().GetPromotionByProducts(products[3:], checkoutTime).Return(nil, nil).Times(1)
+		promoRepo.EXPECT().GetCartPromotions(checkoutTime).Return(promotions[1:3], nil).Times(1)
+
+		checkout := &entity.Checkout{
+			Items: []*entity.CheckoutItem{
+				{Product: products[3], Quantity: 1, SubTotalPrice: 3000},
+			},
+			TotalItem:  1,
+			TotalPrice: 3000,
+			GrandTotal: 3000,
+			Currency:   "USD",
+		}
+		order := entity.NewOrder(checkout)
+		productRepo.EXPECT().SubmitCheckout(checkout).Return(order, nil).Times(1)
+
+		resp, err := svc.Submit(payload)
+		assert.Nil(t, err)
+		assert.Equal(t, order, resp)
 	})
 }
@@ -0,0 +1,18 @@
+package module
+
+import "time"
+
+// source of current time, so tests can control checkout time
+type Clock interface {
+	Now() time.Time
+}
+
+type systemClock struct{}
+
+func NewSystemClock() Clock {
+	return systemClock{}
+}
+
+func (systemClock) Now() time.Time {
+	return time.Now()
+}
@@ -0,0 +1,81 @@
+package module
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/gendutski/be-candidate-home-test/core/entity"
+	"github.com/gendutski/be-candidate-home-test/core/repository"
+)
+
+const (
+	defaultMovementLimit int = 20
+	maxMovementLimit     int = 100
+)
+
+// admin usecase to manage product stock
+type InventoryUsecase interface {
+	// adjust product quantity, movement product id and balance are filled
+	Adjust(serial string, movement *entity.StockMovement) error
+	// return stock movements of current page and total movements
+	History(serial string, filter *entity.StockMovementFilter) ([]*entity.StockMovement, int64, error)
+}
+
+type inventoryUsecase struct {
+	productRepo repository.ProductRepo
+}
+
+func NewInventoryUsecase(productRepo repository.ProductRepo) InventoryUsecase {
+	return &inventoryUsecase{productRepo}
+}
+
+func (uc *inventoryUsecase) Adjust(serial string, movement *entity.StockMovement) error {
+	// validate movement
+	if movement.Delta == 0 {
+		return entity.NewError("delta cannot be zero", http.StatusBadRequest)
+	}
+	switch movement.Reason {
+	case entity.StockRestock, entity.StockReturn, entity.StockCorrection:
+	default:
+		// sale and initial movement are written by system
+		return entity.NewError("invalid adjustment reason", http.StatusBadRequest)
+	}
+	movement.Reference = strings.TrimSpace(movement.Reference)
+	if movement.Reference == "" {
+		return entity.NewError("admin user is required", http.StatusBadRequest)
+	}
+
+	product, err := getProductBySerial(uc.productRepo, serial)
+	if err != nil {
+		return err
+	}
+	movement.ProductID = product.ID
+
+	// repository must handle error with entity.Err
+	return uc.productRepo.AdjustQuantity(movement)
+}
+
+func (uc *inventoryUsecase) History(serial string, filter *entity.StockMovementFilter) ([]*entity.StockMovement, int64, error) {
+	// set default pagination
+	if filter.Page <= 0 {
+		filter.Page = 1
+	}
+	if filter.Limit <= 0 {
+		filter.Limit = defaultMovementLimit
+	}
+	if filter.Limit > maxMovementLimit {
+		filter.Limit = maxMovementLimit
+	}
+
+	product, err := getProductBySerial(uc.productRepo, serial)
+	if err != nil {
+		return nil, 0, err
+	}
+	filter.ProductID = product.ID
+
+	movements, total, err := uc.productRepo.GetStockMovements(filter)
+	if err != nil {
+		return nil, 0, entity.NewError(err.Error(), http.StatusInternalServerError)
+	}
+	return movements, total, nil
+}
@@ -0,0 +1,95 @@
+package module_test
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/gendutski/be-candidate-home-test/core/entity"
+	"github.com/gendutski/be-candidate-home-test/core/module"
+	repomocks "github.com/gendutski/be-candidate-home-test/core/repository/mocks"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/golang/mock/gomock"
+)
+
+func initInventoryUC(ctrl *gomock.Controller) (module.InventoryUsecase, *repomocks.MockProductRepo) {
+	productRepo := repomocks.NewMockProductRepo(ctrl)
+
+	return module.NewInventoryUsecase(productRepo), productRepo
+}
+
+func Test_AdjustInventory(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	svc, productRepo := initInventoryUC(ctrl)
+
+	dayCreated, _ := time.Parse("2006-01-02", "2023-05-16")
+	product := &entity.Product{ID: 4, Serial: "234234", Name: "Raspberry Pi B", Price: 3000, UpdatedAt: dayCreated}
+
+	t.Run("positive, restock", func(t *testing.T) {
+		productRepo.EXPECT().GetProductBySerials([]string{"234234"}).Return([]*entity.Product{product}, nil).Times(1)
+		productRepo.EXPECT().AdjustQuantity(&entity.StockMovement{
+			ProductID: 4, Delta: 10, Reason: entity.StockRestock, Reference: "admin",
+		}).Return(nil).Times(1)
+
+		err := svc.Adjust("234234", &entity.StockMovement{Delta: 10, Reason: entity.StockRestock, Reference: " admin "})
+		assert.Nil(t, err)
+	})
+
+	t.Run("negative, invalid movement", func(t *testing.T) {
+		tests := []struct {
+			name     string
+			movement *entity.StockMovement
+			message  string
+		}{
+			{"zero delta", &entity.StockMovement{Reason: entity.StockRestock, Reference: "admin"}, "delta cannot be zero"},
+			{"sale is written by checkout", &entity.StockMovement{Delta: -1, Reason: entity.StockSale, Reference: "admin"}, "invalid adjustment reason"},
+			{"empty admin user", &entity.StockMovement{Delta: 1, Reason: entity.StockReturn}, "admin user is required"},
+		}
+		for _, tt := range tests {
+			// repository must not be called
+			err := svc.Adjust("234234", tt.movement)
+			assert.Equal(t, entity.NewError(tt.message, http.StatusBadRequest), err, tt.name)
+		}
+	})
+
+	t.Run("negative, product not found", func(t *testing.T) {
+		productRepo.EXPECT().GetProductBySerials([]string{"XXXXXX"}).Return(nil, nil).Times(1)
+
+		err := svc.Adjust("XXXXXX", &entity.StockMovement{Delta: 1, Reason: entity.StockCorrection, Reference: "admin"})
+		assert.Equal(t, entity.NewError(entity.ProductNotFound, http.StatusNotFound), err)
+	})
+
+	t.Run("negative, serial in different letter case", func(t *testing.T) {
+		productRepo.EXPECT().GetProductBySerials([]string{"a304sd"}).Return([]*entity.Product{{ID: 3, Serial: "A304SD", Name: "Alexa Speaker"}}, nil).Times(1)
+
+		err := svc.Adjust("a304sd", &entity.StockMovement{Delta: 1, Reason: entity.StockCorrection, Reference: "admin"})
+		assert.Equal(t, entity.NewError(entity.ProductNotFound, http.StatusNotFound), err)
+	})
+}
+
+func Test_InventoryHistory(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	svc, productRepo := initInventoryUC(ctrl)
+
+	dayCreated, _ := time.Parse("2006-01-02", "2023-05-16")
+	product := &entity.Product{ID: 4, Serial: "234234", Name: "Raspberry Pi B", Price: 3000, UpdatedAt: dayCreated}
+	movements := []*entity.StockMovement{
+		{ID: 2, ProductID: 4, Delta: -1, Balance: 1, Reason: entity.StockSale, Reference: "7", CreatedAt: dayCreated},
+		{ID: 1, ProductID: 4, Delta: 2, Balance: 2, Reason: entity.StockInitial, CreatedAt: dayCreated},
+	}
+
+	t.Run("positive, set default pagination", func(t *testing.T) {
+		productRepo.EXPECT().GetProductBySerials([]string{"234234"}).Return([]*entity.Product{product}, nil).Times(1)
+		productRepo.EXPECT().GetStockMovements(&entity.StockMovementFilter{ProductID: 4, Page: 1, Limit: 20}).Return(movements, int64(2), nil).Times(1)
+
+		resp, total, err := svc.History("234234", &entity.StockMovementFilter{})
+		assert.Nil(t, err)
+		assert.Equal(t, movements, resp)
+		assert.Equal(t, int64(2), total)
+	})
+}
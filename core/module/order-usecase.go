@@ -0,0 +1,65 @@
+package module
+
+import (
+	"net/http"
+
+	"github.com/gendutski/be-candidate-home-test/core/entity"
+	"github.com/gendutski/be-candidate-home-test/core/repository"
+)
+
+const (
+	defaultOrderLimit int = 10
+	maxOrderLimit     int = 100
+)
+
+type OrderUsecase interface {
+	GetByID(id int64) (*entity.Order, error)
+	// return orders of current page and total orders matched the filter
+	List(filter *entity.OrderFilter) ([]*entity.Order, int64, error)
+}
+
+type orderUsecase struct {
+	orderRepo repository.OrderRepo
+}
+
+func NewOrderUsecase(orderRepo repository.OrderRepo) OrderUsecase {
+	return &orderUsecase{orderRepo}
+}
+
+func (uc *orderUsecase) GetByID(id int64) (*entity.Order, error) {
+	order, err := uc.orderRepo.GetOrderByID(id)
+	if err != nil {
+		return nil, entity.NewError(err.Error(), http.StatusInternalServerError)
+	}
+	if order == nil {
+		return nil, entity.NewError(entity.OrderNotFound, http.StatusNotFound)
+	}
+	return order, nil
+}
+
+func (uc *orderUsecase) List(filter *entity.OrderFilter) ([]*entity.Order, int64, error) {
+	// set default pagination
+	if filter.Page <= 0 {
+		filter.Page = 1
+	}
+	if filter.Limit <= 0 {
+		filter.Limit = defaultOrderLimit
+	}
+	if filter.Limit > maxOrderLimit {
+		filter.Limit = maxOrderLimit
+	}
+
+	// validate range
+	if !filter.CreatedFrom.IsZero() && !filter.CreatedTo.IsZero() && !filter.CreatedFrom.Before(filter.CreatedTo) {
+		return nil, 0, entity.NewError("invalid date range", http.StatusBadRequest)
+	}
+	if filter.MinTotalPrice != nil && filter.MaxTotalPrice != nil && *filter.MinTotalPrice > *filter.MaxTotalPrice {
+		return nil, 0, entity.NewError("invalid total price range", http.StatusBadRequest)
+	}
+
+	orders, total, err := uc.orderRepo.GetOrders(filter)
+	if err != nil {
+		return nil, 0, entity.NewError(err.Error(), http.StatusInternalServerError)
+	}
+	return orders, total, nil
+}
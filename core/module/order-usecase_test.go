@@ -0,0 +1,111 @@
+package module_test
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/gendutski/be-candidate-home-test/core/entity"
+	"github.com/gendutski/be-candidate-home-test/core/module"
+	repomocks "github.com/gendutski/be-candidate-home-test/core/repository/mocks"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/golang/mock/gomock"
+)
+
+func initOrderUC(ctrl *gomock.Controller) (module.OrderUsecase, *repomocks.MockOrderRepo) {
+	orderRepo := repomocks.NewMockOrderRepo(ctrl)
+
+	return module.NewOrderUsecase(orderRepo), orderRepo
+}
+
+func Test_GetOrderByID(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	svc, orderRepo := initOrderUC(ctrl)
+
+	dayCreated, _ := time.Parse("2006-01-02", "2023-05-16")
+	order := &entity.Order{
+		ID:         7,
+		TotalItem:  1,
+		TotalPrice: 4999,
+		CreatedAt:  dayCreated,
+		Items: []*entity.OrderItem{
+			{
+				ID: 1, OrderID: 7, ProductID: 1, Quantity: 1, Price: 4999, SubTotalPrice: 4999,
+				Product: &entity.Product{ID: 1, Serial: "120P90", Name: "Google Home", Price: 4999, UpdatedAt: dayCreated},
+			},
+		},
+	}
+
+	t.Run("positive", func(t *testing.T) {
+		orderRepo.EXPECT().GetOrderByID(int64(7)).Return(order, nil).Times(1)
+
+		resp, err := svc.GetByID(7)
+		assert.Nil(t, err)
+		assert.Equal(t, order, resp)
+	})
+
+	t.Run("negative, order not found", func(t *testing.T) {
+		orderRepo.EXPECT().GetOrderByID(int64(8)).Return(nil, nil).Times(1)
+
+		resp, err := svc.GetByID(8)
+		assert.Nil(t, resp)
+		assert.Equal(t, entity.NewError(entity.OrderNotFound, http.StatusNotFound), err)
+	})
+
+	t.Run("negative, repository error", func(t *testing.T) {
+		orderRepo.EXPECT().GetOrderByID(int64(9)).Return(nil, errors.New("connection refused")).Times(1)
+
+		resp, err := svc.GetByID(9)
+		assert.Nil(t, resp)
+		assert.Equal(t, entity.NewError("connection refused", http.StatusInternalServerError), err)
+	})
+}
+
+func Test_ListOrders(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	svc, orderRepo := initOrderUC(ctrl)
+
+	dayCreated, _ := time.Parse("2006-01-02", "2023-05-16")
+	orders := []*entity.Order{
+		{ID: 8, TotalItem: 3, TotalPrice: 9998, CreatedAt: dayCreated},
+		{ID: 7, TotalItem: 1, TotalPrice: 4999, CreatedAt: dayCreated},
+	}
+
+	t.Run("positive, set default pagination", func(t *testing.T) {
+		orderRepo.EXPECT().GetOrders(&entity.OrderFilter{Page: 1, Limit: 10}).Return(orders, int64(2), nil).Times(1)
+
+		resp, total, err := svc.List(&entity.OrderFilter{})
+		assert.Nil(t, err)
+		assert.Equal(t, int64(2), total)
+		assert.Equal(t, orders, resp)
+	})
+
+	t.Run("positive, limit is capped", func(t *testing.T) {
+		orderRepo.EXPECT().GetOrders(&entity.OrderFilter{Page: 3, Limit: 100}).Return(nil, int64(2), nil).Times(1)
+
+		resp, total, err := svc.List(&entity.OrderFilter{Page: 3, Limit: 1000})
+		assert.Nil(t, err)
+		assert.Equal(t, int64(2), total)
+		assert.Nil(t, resp)
+	})
+
+	t.Run("negative, invalid date range", func(t *testing.T) {
+		resp, _, err := svc.List(&entity.OrderFilter{CreatedFrom: dayCreated, CreatedTo: dayCreated})
+		assert.Nil(t, resp)
+		assert.Equal(t, entity.NewError("invalid date range", http.StatusBadRequest), err)
+	})
+
+	t.Run("negative, invalid total price range", func(t *testing.T) {
+		minTotal := entity.Money(10000)
+		maxTotal := entity.Money(5000)
+		resp, _, err := svc.List(&entity.OrderFilter{MinTotalPrice: &minTotal, MaxTotalPrice: &maxTotal})
+		assert.Nil(t, resp)
+		assert.Equal(t, entity.NewError("invalid total price range", http.StatusBadRequest), err)
+	})
+}
@@ -0,0 +1,148 @@
+package module
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/gendutski/be-candidate-home-test/core/entity"
+	"github.com/gendutski/be-candidate-home-test/core/repository"
+)
+
+const (
+	defaultProductLimit int = 10
+	maxProductLimit     int = 100
+	maxSerialLength     int = 20
+	maxNameLength       int = 255
+	maxCategoryLength   int = 20
+)
+
+// admin usecase to manage product catalog
+type ProductUsecase interface {
+	GetByID(id int64) (*entity.Product, error)
+	// return products of current page and total products
+	List(filter *entity.ProductFilter) ([]*entity.Product, int64, error)
+	// create product with initial quantity
+	Create(product *entity.Product, quantity int) error
+	Update(product *entity.Product) error
+	// soft delete product
+	Delete(id int64) error
+}
+
+type productUsecase struct {
+	productRepo repository.ProductRepo
+}
+
+func NewProductUsecase(productRepo repository.ProductRepo) ProductUsecase {
+	return &productUsecase{productRepo}
+}
+
+func (uc *productUsecase) GetByID(id int64) (*entity.Product, error) {
+	product, err := uc.productRepo.GetProductByID(id)
+	if err != nil {
+		return nil, entity.NewError(err.Error(), http.StatusInternalServerError)
+	}
+	if product == nil {
+		return nil, entity.NewError(entity.ProductNotFound, http.StatusNotFound)
+	}
+	return product, nil
+}
+
+func (uc *productUsecase) List(filter *entity.ProductFilter) ([]*entity.Product, int64, error) {
+	// set default pagination
+	if filter.Page <= 0 {
+		filter.Page = 1
+	}
+	if filter.Limit <= 0 {
+		filter.Limit = defaultProductLimit
+	}
+	if filter.Limit > maxProductLimit {
+		filter.Limit = maxProductLimit
+	}
+
+	products, total, err := uc.productRepo.GetProducts(filter)
+	if err != nil {
+		return nil, 0, entity.NewError(err.Error(), http.StatusInternalServerError)
+	}
+	return products, total, nil
+}
+
+func (uc *productUsecase) Create(product *entity.Product, quantity int) error {
+	// id is generated by database
+	product.ID = 0
+	if quantity < 0 {
+		return entity.NewError("quantity cannot be negative", http.StatusBadRequest)
+	}
+	if err := uc.validate(product); err != nil {
+		return err
+	}
+
+	if err := uc.productRepo.CreateProduct(product, quantity); err != nil {
+		return entity.NewError(err.Error(), http.StatusInternalServerError)
+	}
+	return nil
+}
+
+func (uc *productUsecase) Update(product *entity.Product) error {
+	// product must exists
+	if _, err := uc.GetByID(product.ID); err != nil {
+		return err
+	}
+	if err := uc.validate(product); err != nil {
+		return err
+	}
+
+	if err := uc.productRepo.UpdateProduct(product); err != nil {
+		return entity.NewError(err.Error(), http.StatusInternalServerError)
+	}
+	return nil
+}
+
+func (uc *productUsecase) Delete(id int64) error {
+	// product must exists
+	if _, err := uc.GetByID(id); err != nil {
+		return err
+	}
+
+	if err := uc.productRepo.DeleteProduct(id); err != nil {
+		return entity.NewError(err.Error(), http.StatusInternalServerError)
+	}
+	return nil
+}
+
+// validate product based on product table columns
+func (uc *productUsecase) validate(product *entity.Product) error {
+	product.Serial = strings.TrimSpace(product.Serial)
+	product.Name = strings.TrimSpace(product.Name)
+	product.TaxCategory = strings.TrimSpace(product.TaxCategory)
+	product.Category = strings.TrimSpace(product.Category)
+	if product.TaxCategory == "" {
+		product.TaxCategory = entity.DefaultTaxCategory
+	}
+
+	if product.Serial == "" || len(product.Serial) > maxSerialLength {
+		return entity.NewError("serial must be 1 to 20 characters", http.StatusBadRequest)
+	}
+	if product.Name == "" || len(product.Name) > maxNameLength {
+		return entity.NewError("name must be 1 to 255 characters", http.StatusBadRequest)
+	}
+	if product.Price < 0 || product.Price > entity.MaxPrice {
+		return entity.NewError("price must be between 0 and 99999999.99", http.StatusBadRequest)
+	}
+	if len(product.TaxCategory) > maxCategoryLength {
+		return entity.NewError("tax category must be at most 20 characters", http.StatusBadRequest)
+	}
+	if len(product.Category) > maxCategoryLength {
+		return entity.NewError("category must be at most 20 characters", http.StatusBadRequest)
+	}
+
+	// serial must be unique
+	total, err := uc.productRepo.CountProductBySerial(product.Serial, product.ID)
+	if err != nil {
+		return entity.NewError(err.Error(), http.StatusInternalServerError)
+	}
+	if total > 0 {
+		return entity.NewError(entity.SerialAlreadyUsed, http.StatusBadRequest)
+	}
+
+	return nil
+}
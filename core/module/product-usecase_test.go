@@ -0,0 +1,144 @@
+package module_test
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gendutski/be-candidate-home-test/core/entity"
+	"github.com/gendutski/be-candidate-home-test/core/module"
+	repomocks "github.com/gendutski/be-candidate-home-test/core/repository/mocks"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/golang/mock/gomock"
+)
+
+func initProductUC(ctrl *gomock.Controller) (module.ProductUsecase, *repomocks.MockProductRepo) {
+	productRepo := repomocks.NewMockProductRepo(ctrl)
+
+	return module.NewProductUsecase(productRepo), productRepo
+}
+
+func Test_CreateProduct(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	svc, productRepo := initProductUC(ctrl)
+
+	t.Run("positive, set default tax category", func(t *testing.T) {
+		product := &entity.Product{Serial: " 120P91 ", Name: "Google Home Mini", Price: 2999}
+		productRepo.EXPECT().CountProductBySerial("120P91", int64(0)).Return(int64(0), nil).Times(1)
+		productRepo.EXPECT().CreateProduct(&entity.Product{
+			Serial: "120P91", Name: "Google Home Mini", Price: 2999, TaxCategory: entity.DefaultTaxCategory,
+		}, 10).Return(nil).Times(1)
+
+		err := svc.Create(product, 10)
+		assert.Nil(t, err)
+	})
+
+	t.Run("negative, invalid product", func(t *testing.T) {
+		tests := []struct {
+			name     string
+			product  *entity.Product
+			quantity int
+			message  string
+		}{
+			{"serial too long", &entity.Product{Serial: strings.Repeat("A", 21), Name: "Google Home", Price: 4999}, 1, "serial must be 1 to 20 characters"},
+			{"empty name", &entity.Product{Serial: "120P90", Name: " ", Price: 4999}, 1, "name must be 1 to 255 characters"},
+			{"negative price", &entity.Product{Serial: "120P90", Name: "Google Home", Price: -1}, 1, "price must be between 0 and 99999999.99"},
+			{"price above decimal(10,2)", &entity.Product{Serial: "120P90", Name: "Google Home", Price: entity.MaxPrice + 1}, 1, "price must be between 0 and 99999999.99"},
+			{"category too long", &entity.Product{Serial: "120P90", Name: "Google Home", Price: 4999, Category: strings.Repeat("a", 21)}, 1, "category must be at most 20 characters"},
+			{"negative quantity", &entity.Product{Serial: "120P90", Name: "Google Home", Price: 4999}, -1, "quantity cannot be negative"},
+		}
+		for _, tt := range tests {
+			// repository must not be called
+			err := svc.Create(tt.product, tt.quantity)
+			assert.Equal(t, entity.NewError(tt.message, http.StatusBadRequest), err, tt.name)
+		}
+	})
+
+	t.Run("negative, serial already used", func(t *testing.T) {
+		product := &entity.Product{Serial: "120P90", Name: "Google Home", Price: entity.MaxPrice}
+		productRepo.EXPECT().CountProductBySerial("120P90", int64(0)).Return(int64(1), nil).Times(1)
+
+		err := svc.Create(product, 1)
+		assert.Equal(t, entity.NewError(entity.SerialAlreadyUsed, http.StatusBadRequest), err)
+	})
+
+	t.Run("negative, repository error", func(t *testing.T) {
+		product := &entity.Product{Serial: "120P91", Name: "Google Home Mini", Price: 2999}
+		productRepo.EXPECT().CountProductBySerial("120P91", int64(0)).Return(int64(0), nil).Times(1)
+		productRepo.EXPECT().CreateProduct(product, 0).Return(errors.New("connection refused")).Times(1)
+
+		err := svc.Create(product, 0)
+		assert.Equal(t, entity.NewError("connection refused", http.StatusInternalServerError), err)
+	})
+}
+
+func Test_UpdateProduct(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	svc, productRepo := initProductUC(ctrl)
+
+	dayCreated, _ := time.Parse("2006-01-02", "2023-05-16")
+	existing := &entity.Product{ID: 1, Serial: "120P90", Name: "Google Home", Price: 4999, TaxCategory: "standard", UpdatedAt: dayCreated}
+
+	t.Run("positive", func(t *testing.T) {
+		product := &entity.Product{ID: 1, Serial: "120P90", Name: "Google Home", Price: 4499, TaxCategory: "reduced"}
+		productRepo.EXPECT().GetProductByID(int64(1)).Return(existing, nil).Times(1)
+		// product itself is excluded from unique serial check
+		productRepo.EXPECT().CountProductBySerial("120P90", int64(1)).Return(int64(0), nil).Times(1)
+		productRepo.EXPECT().UpdateProduct(product).Return(nil).Times(1)
+
+		err := svc.Update(product)
+		assert.Nil(t, err)
+	})
+
+	t.Run("negative, product not found", func(t *testing.T) {
+		product := &entity.Product{ID: 9, Serial: "120P90", Name: "Google Home", Price: 4499}
+		productRepo.EXPECT().GetProductByID(int64(9)).Return(nil, nil).Times(1)
+
+		err := svc.Update(product)
+		assert.Equal(t, entity.NewError(entity.ProductNotFound, http.StatusNotFound), err)
+	})
+}
+
+func Test_ListAndDeleteProduct(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	svc, productRepo := initProductUC(ctrl)
+
+	dayCreated, _ := time.Parse("2006-01-02", "2023-05-16")
+	products := []*entity.Product{
+		{ID: 1, Serial: "120P90", Name: "Google Home", Price: 4999, UpdatedAt: dayCreated},
+		{ID: 2, Serial: "43N23P", Name: "MacBook Pro", Price: 539999, UpdatedAt: dayCreated},
+	}
+
+	t.Run("list, set max limit", func(t *testing.T) {
+		productRepo.EXPECT().GetProducts(&entity.ProductFilter{Page: 1, Limit: 100}).Return(products, int64(2), nil).Times(1)
+
+		resp, total, err := svc.List(&entity.ProductFilter{Limit: 500})
+		assert.Nil(t, err)
+		assert.Equal(t, products, resp)
+		assert.Equal(t, int64(2), total)
+	})
+
+	t.Run("delete", func(t *testing.T) {
+		productRepo.EXPECT().GetProductByID(int64(1)).Return(products[0], nil).Times(1)
+		productRepo.EXPECT().DeleteProduct(int64(1)).Return(nil).Times(1)
+
+		err := svc.Delete(1)
+		assert.Nil(t, err)
+	})
+
+	t.Run("delete, product not found", func(t *testing.T) {
+		productRepo.EXPECT().GetProductByID(int64(9)).Return(nil, nil).Times(1)
+
+		err := svc.Delete(9)
+		assert.Equal(t, entity.NewError(entity.ProductNotFound, http.StatusNotFound), err)
+	})
+}
@@ -0,0 +1,404 @@
+package module
+
+import (
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/gendutski/be-candidate-home-test/core/entity"
+	"github.com/gendutski/be-candidate-home-test/core/repository"
+)
+
+// admin usecase to manage promotions
+type PromotionUsecase interface {
+	GetByID(id int64) (*entity.Promotion, error)
+	List() ([]*entity.Promotion, error)
+	Create(promo *entity.Promotion) error
+	Update(promo *entity.Promotion) error
+	// soft delete promotion
+	Delete(id int64) error
+}
+
+type promotionUsecase struct {
+	promoRepo   repository.PromotionRepo
+	productRepo repository.ProductRepo
+}
+
+func NewPromotionUsecase(promoRepo repository.PromotionRepo, productRepo repository.ProductRepo) PromotionUsecase {
+	return &promotionUsecase{promoRepo, productRepo}
+}
+
+func (uc *promotionUsecase) GetByID(id int64) (*entity.Promotion, error) {
+	promo, err := uc.promoRepo.GetPromotionByID(id)
+	if err != nil {
+		return nil, entity.NewError(err.Error(), http.StatusInternalServerError)
+	}
+	if promo == nil {
+		return nil, entity.NewError(entity.PromotionNotFound, http.StatusNotFound)
+	}
+	return promo, nil
+}
+
+func (uc *promotionUsecase) List() ([]*entity.Promotion, error) {
+	promotions, err := uc.promoRepo.GetPromotions()
+	if err != nil {
+		return nil, entity.NewError(err.Error(), http.StatusInternalServerError)
+	}
+	return promotions, nil
+}
+
+func (uc *promotionUsecase) Create(promo *entity.Promotion) error {
+	// id is generated by database
+	promo.ID = 0
+	if err := uc.validate(promo); err != nil {
+		return err
+	}
+
+	if err := uc.promoRepo.CreatePromotion(promo); err != nil {
+		return entity.NewError(err.Error(), http.StatusInternalServerError)
+	}
+	return nil
+}
+
+func (uc *promotionUsecase) Update(promo *entity.Promotion) error {
+	// promotion must exists
+	if _, err := uc.GetByID(promo.ID); err != nil {
+		return err
+	}
+	if err := uc.validate(promo); err != nil {
+		return err
+	}
+
+	if err := uc.promoRepo.UpdatePromotion(promo); err != nil {
+		return entity.NewError(err.Error(), http.StatusInternalServerError)
+	}
+	return nil
+}
+
+func (uc *promotionUsecase) Delete(id int64) error {
+	// promotion must exists
+	if _, err := uc.GetByID(id); err != nil {
+		return err
+	}
+
+	if err := uc.promoRepo.DeletePromotion(id); err != nil {
+		return entity.NewError(err.Error(), http.StatusInternalServerError)
+	}
+	return nil
+}
+
+// validate promotion business rules
+func (uc *promotionUsecase) validate(promo *entity.Promotion) error {
+	// cart level promotion is not attached to any product
+	if promo.IsCartLevel() {
+		return uc.validateCartPromotion(promo)
+	}
+
+	if promo.ProductID == 0 {
+		return entity.NewError("product id is required", http.StatusBadRequest)
+	}
+	// tiered price uses min quantity of each tier
+	if promo.Type != entity.TieredPrice && promo.MatchQuantity < 1 {
+		return entity.NewError("match quantity must be at least 1", http.StatusBadRequest)
+	}
+
+	// validate promo value of each type
+	switch promo.Type {
+	case entity.BonusItem:
+		if promo.PromoProductID == 0 {
+			return entity.NewError("promo product id is required for bonus item promotion", http.StatusBadRequest)
+		}
+		if promo.PromoProductID == promo.ProductID {
+			return entity.NewError("bonus item must be other product, use free item promotion for the same product", http.StatusBadRequest)
+		}
+		if promo.PromoValue < 1 {
+			return entity.NewError("number of bonus items must be at least 1", http.StatusBadRequest)
+		}
+	case entity.BuyItemsForReducePrice:
+		if promo.PromoValue < 0 || promo.PromoValue >= promo.MatchQuantity {
+			return entity.NewError("number of paid items must be less than match quantity", http.StatusBadRequest)
+		}
+	case entity.DiscountInPercent:
+		if promo.PromoValue < 0 || promo.PromoValue > 100 {
+			return entity.NewError("discount percent must be between 0 and 100", http.StatusBadRequest)
+		}
+	case entity.FreeItem:
+		if promo.PromoValue < 1 {
+			return entity.NewError("number of free items must be at least 1", http.StatusBadRequest)
+		}
+	case entity.TieredPrice:
+		if err := validateTiers(promo); err != nil {
+			return err
+		}
+	case entity.DiscountInAmount, entity.LineDiscountInAmount, entity.FixedUnitPrice:
+		// promo price is only valid in its currency
+		promo.Currency = strings.ToUpper(strings.TrimSpace(promo.Currency))
+		if !currencyPattern.MatchString(promo.Currency) {
+			return entity.NewError(entity.InvalidCurrency, http.StatusBadRequest)
+		}
+		if promo.PromoPrice <= 0 || promo.PromoPrice > entity.MaxPrice {
+			return entity.NewError("promo price must be between 0.01 and 99999999.99", http.StatusBadRequest)
+		}
+	default:
+		return entity.NewError("invalid promotion type", http.StatusBadRequest)
+	}
+	if promo.Type != entity.BonusItem && promo.PromoProductID != 0 {
+		return entity.NewError("promo product id is only for bonus item promotion", http.StatusBadRequest)
+	}
+	// currency of tiered price is validated with its tiers, promo price and currency of fixed amount promotion with its type
+	if promo.MinTotal != 0 || promo.Category != "" || (promo.PromoPrice != 0 && !promo.IsFixedAmount()) ||
+		(promo.Currency != "" && promo.Type != entity.TieredPrice && !promo.IsFixedAmount()) {
+		return entity.NewError("min total, promo price, category and currency are only for cart level promotion", http.StatusBadRequest)
+	}
+	if len(promo.Items) > 0 {
+		return entity.NewError("items are only for product bundle promotion", http.StatusBadRequest)
+	}
+	if promo.Type != entity.TieredPrice && len(promo.Tiers) > 0 {
+		return entity.NewError("tiers are only for tiered price promotion", http.StatusBadRequest)
+	}
+	if err := validateStacking(promo); err != nil {
+		return err
+	}
+	if err := validateValidityWindow(promo); err != nil {
+		return err
+	}
+
+	// products must exists
+	productIDs := []int64{promo.ProductID}
+	if promo.PromoProductID != 0 {
+		productIDs = append(productIDs, promo.PromoProductID)
+	}
+	products, err := uc.productRepo.GetProductByIDs(productIDs)
+	if err != nil {
+		return entity.NewError(err.Error(), http.StatusInternalServerError)
+	}
+	if len(products) != len(productIDs) {
+		return entity.NewError(entity.ProductNotFound, http.StatusBadRequest)
+	}
+
+	// products set as free items cannot be promoted
+	total, err := uc.promoRepo.CountBonusPromotions(promo.ProductID, promo.ID)
+	if err != nil {
+		return entity.NewError(err.Error(), http.StatusInternalServerError)
+	}
+	if total > 0 {
+		return entity.NewError("product is a free item of other promotion, it cannot be promoted", http.StatusBadRequest)
+	}
+	if promo.Type == entity.BonusItem {
+		total, err = uc.promoRepo.CountProductPromotions(promo.PromoProductID, promo.ID)
+		if err != nil {
+			return entity.NewError(err.Error(), http.StatusInternalServerError)
+		}
+		if total > 0 {
+			return entity.NewError("promoted product cannot be set as free item", http.StatusBadRequest)
+		}
+	}
+
+	return nil
+}
+
+// validate cart level promotion business rules
+func (uc *promotionUsecase) validateCartPromotion(promo *entity.Promotion) error {
+	promo.Category = strings.TrimSpace(promo.Category)
+	promo.Currency = strings.ToUpper(strings.TrimSpace(promo.Currency))
+
+	if promo.ProductID != 0 {
+		return entity.NewError("product id must be empty for cart level promotion", http.StatusBadRequest)
+	}
+	if !currencyPattern.MatchString(promo.Currency) {
+		return entity.NewError(entity.InvalidCurrency, http.StatusBadRequest)
+	}
+	if promo.MinTotal < 0 || promo.MinTotal > entity.MaxPrice {
+		return entity.NewError("min total must be between 0 and 99999999.99", http.StatusBadRequest)
+	}
+
+	// validate promo value of each type
+	switch promo.Type {
+	case entity.CategoryBundle:
+		if promo.Category == "" || len(promo.Category) > maxCategoryLength {
+			return entity.NewError("category must be 1 to 20 characters", http.StatusBadRequest)
+		}
+		if promo.MatchQuantity < 2 {
+			return entity.NewError("number of bundle items must be at least 2", http.StatusBadRequest)
+		}
+		if promo.PromoPrice <= 0 || promo.PromoPrice > entity.MaxPrice {
+			return entity.NewError("promo price must be between 0.01 and 99999999.99", http.StatusBadRequest)
+		}
+	case entity.CartBonusItem:
+		if promo.PromoProductID == 0 {
+			return entity.NewError("promo product id is required for bonus item promotion", http.StatusBadRequest)
+		}
+		if promo.PromoValue < 1 {
+			return entity.NewError("number of bonus items must be at least 1", http.StatusBadRequest)
+		}
+	case entity.CartDiscountInPercent:
+		if promo.PromoValue < 1 || promo.PromoValue > 100 {
+			return entity.NewError("discount percent must be between 1 and 100", http.StatusBadRequest)
+		}
+	case entity.ProductBundle:
+		if err := validateBundleItems(promo.Items); err != nil {
+			return err
+		}
+		// bundle price, or discount in percent when bundle price is empty
+		if promo.PromoPrice != 0 && promo.PromoValue != 0 {
+			return entity.NewError("promo price and promo value cannot be set together", http.StatusBadRequest)
+		}
+		if promo.PromoPrice == 0 && (promo.PromoValue < 1 || promo.PromoValue > 100) {
+			return entity.NewError("discount percent must be between 1 and 100", http.StatusBadRequest)
+		}
+		if promo.PromoPrice < 0 || promo.PromoPrice > entity.MaxPrice {
+			return entity.NewError("promo price must be between 0.01 and 99999999.99", http.StatusBadRequest)
+		}
+	}
+	if promo.Type != entity.CategoryBundle && promo.Category != "" {
+		return entity.NewError("category is only for category bundle promotion", http.StatusBadRequest)
+	}
+	if promo.Type != entity.CategoryBundle && promo.Type != entity.ProductBundle && promo.PromoPrice != 0 {
+		return entity.NewError("promo price is only for bundle promotion", http.StatusBadRequest)
+	}
+	if promo.Type != entity.ProductBundle && len(promo.Items) > 0 {
+		return entity.NewError("items are only for product bundle promotion", http.StatusBadRequest)
+	}
+	if len(promo.Tiers) > 0 {
+		return entity.NewError("tiers are only for tiered price promotion", http.StatusBadRequest)
+	}
+	if promo.Type != entity.CartBonusItem && promo.PromoProductID != 0 {
+		return entity.NewError("promo product id is only for bonus item promotion", http.StatusBadRequest)
+	}
+	if promo.Priority != 0 || promo.Exclusive || len(promo.StackableWith) > 0 {
+		return entity.NewError("priority, exclusive and stackable with are only for product promotion", http.StatusBadRequest)
+	}
+	if err := validateValidityWindow(promo); err != nil {
+		return err
+	}
+
+	// bonus product must exists
+	if promo.Type == entity.CartBonusItem {
+		products, err := uc.productRepo.GetProductByIDs([]int64{promo.PromoProductID})
+		if err != nil {
+			return entity.NewError(err.Error(), http.StatusInternalServerError)
+		}
+		if len(products) == 0 {
+			return entity.NewError(entity.ProductNotFound, http.StatusBadRequest)
+		}
+
+		// promoted products cannot be set as free items
+		total, err := uc.promoRepo.CountProductPromotions(promo.PromoProductID, promo.ID)
+		if err != nil {
+			return entity.NewError(err.Error(), http.StatusInternalServerError)
+		}
+		if total > 0 {
+			return entity.NewError("promoted product cannot be set as free item", http.StatusBadRequest)
+		}
+	}
+
+	// bundle products must exists
+	if promo.Type == entity.ProductBundle {
+		var productIDs []int64
+		for _, item := range promo.Items {
+			productIDs = append(productIDs, item.ProductID)
+		}
+		products, err := uc.productRepo.GetProductByIDs(productIDs)
+		if err != nil {
+			return entity.NewError(err.Error(), http.StatusInternalServerError)
+		}
+		if len(products) != len(productIDs) {
+			return entity.NewError(entity.ProductNotFound, http.StatusBadRequest)
+		}
+
+		// products set as free items cannot be bundled
+		for _, productID := range productIDs {
+			total, err := uc.promoRepo.CountBonusPromotions(productID, promo.ID)
+			if err != nil {
+				return entity.NewError(err.Error(), http.StatusInternalServerError)
+			}
+			if total > 0 {
+				return entity.NewError("product is a free item of other promotion, it cannot be promoted", http.StatusBadRequest)
+			}
+		}
+	}
+	return nil
+}
+
+// validate tiers of tiered price, tiers are sorted by min quantity
+// currency is required when a tier has unit price
+func validateTiers(promo *entity.Promotion) error {
+	promo.Currency = strings.ToUpper(strings.TrimSpace(promo.Currency))
+	if len(promo.Tiers) == 0 {
+		return entity.NewError("tiers are required for tiered price promotion", http.StatusBadRequest)
+	}
+	sort.SliceStable(promo.Tiers, func(i, j int) bool { return promo.Tiers[i].MinQuantity < promo.Tiers[j].MinQuantity })
+
+	var hasUnitPrice bool
+	for i, tier := range promo.Tiers {
+		if tier.MinQuantity < 1 {
+			return entity.NewError("min quantity of tier must be at least 1", http.StatusBadRequest)
+		}
+		if i > 0 && tier.MinQuantity == promo.Tiers[i-1].MinQuantity {
+			return entity.NewError("min quantity of tier must be unique", http.StatusBadRequest)
+		}
+		if (tier.Percent != 0) == (tier.UnitPrice != 0) {
+			return entity.NewError("tier must have either percent or unit price", http.StatusBadRequest)
+		}
+		if tier.Percent < 0 || tier.Percent > 100 {
+			return entity.NewError("discount percent must be between 1 and 100", http.StatusBadRequest)
+		}
+		if tier.UnitPrice < 0 || tier.UnitPrice > entity.MaxPrice {
+			return entity.NewError("unit price must be between 0.01 and 99999999.99", http.StatusBadRequest)
+		}
+		hasUnitPrice = hasUnitPrice || tier.UnitPrice > 0
+	}
+
+	// unit price is only valid in its currency
+	if hasUnitPrice && !currencyPattern.MatchString(promo.Currency) {
+		return entity.NewError(entity.InvalidCurrency, http.StatusBadRequest)
+	}
+	if !hasUnitPrice && promo.Currency != "" {
+		return entity.NewError("currency is only for tier with unit price", http.StatusBadRequest)
+	}
+	return nil
+}
+
+// validate products of product bundle
+func validateBundleItems(items []*entity.PromotionItem) error {
+	if len(items) < 2 {
+		return entity.NewError("product bundle must have at least 2 products", http.StatusBadRequest)
+	}
+	productIDs := make(map[int64]bool)
+	for _, item := range items {
+		if item.ProductID == 0 {
+			return entity.NewError("product id of bundle item is required", http.StatusBadRequest)
+		}
+		if productIDs[item.ProductID] {
+			return entity.NewError("product of bundle item must be unique", http.StatusBadRequest)
+		}
+		if item.Quantity < 1 {
+			return entity.NewError("quantity of bundle item must be at least 1", http.StatusBadRequest)
+		}
+		productIDs[item.ProductID] = true
+	}
+	return nil
+}
+
+// validate how promotion is combined with other promotions of the product
+func validateStacking(promo *entity.Promotion) error {
+	if promo.Priority < 0 {
+		return entity.NewError("priority cannot be negative", http.StatusBadRequest)
+	}
+	if promo.Exclusive && len(promo.StackableWith) > 0 {
+		return entity.NewError("exclusive promotion cannot be stacked with other promotions", http.StatusBadRequest)
+	}
+	for _, id := range promo.StackableWith {
+		if id <= 0 || (promo.ID != 0 && id == promo.ID) {
+			return entity.NewError("stackable with must be id of other promotions", http.StatusBadRequest)
+		}
+	}
+	return nil
+}
+
+func validateValidityWindow(promo *entity.Promotion) error {
+	if promo.StartsAt != nil && promo.EndsAt != nil && !promo.StartsAt.Before(*promo.EndsAt) {
+		return entity.NewError("starts at must be before ends at", http.StatusBadRequest)
+	}
+	return nil
+}
@@ -0,0 +1,375 @@
+package module_test
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/gendutski/be-candidate-home-test/core/entity"
+	"github.com/gendutski/be-candidate-home-test/core/module"
+	repomocks "github.com/gendutski/be-candidate-home-test/core/repository/mocks"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/golang/mock/gomock"
+)
+
+func initPromotionUC(ctrl *gomock.Controller) (module.PromotionUsecase, *repomocks.MockPromotionRepo, *repomocks.MockProductRepo) {
+	promoRepo := repomocks.NewMockPromotionRepo(ctrl)
+	productRepo := repomocks.NewMockProductRepo(ctrl)
+
+	return module.NewPromotionUsecase(promoRepo, productRepo), promoRepo, productRepo
+}
+
+func Test_CreatePromotion(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	svc, promoRepo, productRepo := initPromotionUC(ctrl)
+
+	dayCreated, _ := time.Parse("2006-01-02", "2023-05-16")
+	products := []*entity.Product{
+		{ID: 2, Serial: "43N23P", Name: "MacBook Pro", Price: 539999, UpdatedAt: dayCreated},
+		{ID: 4, Serial: "234234", Name: "Raspberry Pi B", Price: 3000, UpdatedAt: dayCreated},
+	}
+
+	t.Run("positive, bonus item", func(t *testing.T) {
+		promo := &entity.Promotion{Type: entity.BonusItem, ProductID: 2, MatchQuantity: 1, PromoValue: 1, PromoProductID: 4}
+		productRepo.EXPECT().GetProductByIDs([]int64{2, 4}).Return(products, nil).Times(1)
+		promoRepo.EXPECT().CountBonusPromotions(int64(2), int64(0)).Return(int64(0), nil).Times(1)
+		promoRepo.EXPECT().CountProductPromotions(int64(4), int64(0)).Return(int64(0), nil).Times(1)
+		promoRepo.EXPECT().CreatePromotion(promo).Return(nil).Times(1)
+
+		err := svc.Create(promo)
+		assert.Nil(t, err)
+	})
+
+	t.Run("negative, invalid rules", func(t *testing.T) {
+		startsAt := time.Date(2023, 5, 20, 0, 0, 0, 0, time.UTC)
+		tests := []struct {
+			name    string
+			promo   *entity.Promotion
+			message string
+		}{
+			{"unknown type", &entity.Promotion{Type: 13, ProductID: 2, MatchQuantity: 1}, "invalid promotion type"},
+			{"zero match quantity", &entity.Promotion{Type: entity.DiscountInPercent, ProductID: 2, PromoValue: 10}, "match quantity must be at least 1"},
+			{"percent above 100", &entity.Promotion{Type: entity.DiscountInPercent, ProductID: 2, MatchQuantity: 1, PromoValue: 101}, "discount percent must be between 0 and 100"},
+			{"negative percent", &entity.Promotion{Type: entity.DiscountInPercent, ProductID: 2, MatchQuantity: 1, PromoValue: -1}, "discount percent must be between 0 and 100"},
+			{"bonus item without promo product", &entity.Promotion{Type: entity.BonusItem, ProductID: 2, MatchQuantity: 1, PromoValue: 1}, "promo product id is required for bonus item promotion"},
+			{"bonus item of the same product", &entity.Promotion{Type: entity.BonusItem, ProductID: 2, MatchQuantity: 1, PromoValue: 1, PromoProductID: 2}, "bonus item must be other product, use free item promotion for the same product"},
+			{"pay all items", &entity.Promotion{Type: entity.BuyItemsForReducePrice, ProductID: 2, MatchQuantity: 3, PromoValue: 3}, "number of paid items must be less than match quantity"},
+			{"free item without value", &entity.Promotion{Type: entity.FreeItem, ProductID: 2, MatchQuantity: 2}, "number of free items must be at least 1"},
+			{"promo product on discount", &entity.Promotion{Type: entity.DiscountInPercent, ProductID: 2, MatchQuantity: 1, PromoValue: 10, PromoProductID: 4}, "promo product id is only for bonus item promotion"},
+			{"empty validity window", &entity.Promotion{Type: entity.DiscountInPercent, ProductID: 2, MatchQuantity: 1, PromoValue: 10, StartsAt: &startsAt, EndsAt: &startsAt}, "starts at must be before ends at"},
+			{"negative priority", &entity.Promotion{Type: entity.DiscountInPercent, ProductID: 2, MatchQuantity: 1, PromoValue: 10, Priority: -1}, "priority cannot be negative"},
+			{"stackable exclusive", &entity.Promotion{Type: entity.DiscountInPercent, ProductID: 2, MatchQuantity: 1, PromoValue: 10, Exclusive: true, StackableWith: entity.PromotionIDList{3}}, "exclusive promotion cannot be stacked with other promotions"},
+			{"stackable with empty id", &entity.Promotion{Type: entity.DiscountInPercent, ProductID: 2, MatchQuantity: 1, PromoValue: 10, StackableWith: entity.PromotionIDList{0}}, "stackable with must be id of other promotions"},
+		}
+		for _, tt := range tests {
+			// repository must not be called
+			err := svc.Create(tt.promo)
+			assert.Equal(t, entity.NewError(tt.message, http.StatusBadRequest), err, tt.name)
+		}
+	})
+
+	t.Run("positive, stackable discount", func(t *testing.T) {
+		promo := &entity.Promotion{Type: entity.DiscountInPercent, ProductID: 2, MatchQuantity: 1, PromoValue: 10, Priority: 1, StackableWith: entity.PromotionIDList{3}}
+		productRepo.EXPECT().GetProductByIDs([]int64{2}).Return(products[:1], nil).Times(1)
+		promoRepo.EXPECT().CountBonusPromotions(int64(2), int64(0)).Return(int64(0), nil).Times(1)
+		promoRepo.EXPECT().CreatePromotion(promo).Return(nil).Times(1)
+
+		err := svc.Create(promo)
+		assert.Nil(t, err)
+	})
+
+	t.Run("positive, tiered price sorted by min quantity", func(t *testing.T) {
+		promo := &entity.Promotion{Type: entity.TieredPrice, ProductID: 4, Currency: " usd ", Tiers: []*entity.PromotionTier{
+			{MinQuantity: 50, UnitPrice: 2500},
+			{MinQuantity: 10, Percent: 5},
+		}}
+		productRepo.EXPECT().GetProductByIDs([]int64{4}).Return(products[1:], nil).Times(1)
+		promoRepo.EXPECT().CountBonusPromotions(int64(4), int64(0)).Return(int64(0), nil).Times(1)
+		promoRepo.EXPECT().CreatePromotion(&entity.Promotion{Type: entity.TieredPrice, ProductID: 4, Currency: "USD", Tiers: []*entity.PromotionTier{
+			{MinQuantity: 10, Percent: 5},
+			{MinQuantity: 50, UnitPrice: 2500},
+		}}).Return(nil).Times(1)
+
+		err := svc.Create(promo)
+		assert.Nil(t, err)
+	})
+
+	t.Run("negative, invalid tiers", func(t *testing.T) {
+		tests := []struct {
+			name    string
+			promo   *entity.Promotion
+			message string
+		}{
+			{"without tiers", &entity.Promotion{Type: entity.TieredPrice, ProductID: 4}, "tiers are required for tiered price promotion"},
+			{"zero min quantity", &entity.Promotion{Type: entity.TieredPrice, ProductID: 4, Tiers: []*entity.PromotionTier{{Percent: 5}}}, "min quantity of tier must be at least 1"},
+			{"duplicate min quantity", &entity.Promotion{Type: entity.TieredPrice, ProductID: 4, Tiers: []*entity.PromotionTier{{MinQuantity: 10, Percent: 5}, {MinQuantity: 10, Percent: 12}}}, "min quantity of tier must be unique"},
+			{"percent and unit price", &entity.Promotion{Type: entity.TieredPrice, ProductID: 4, Currency: "USD", Tiers: []*entity.PromotionTier{{MinQuantity: 10, Percent: 5, UnitPrice: 2500}}}, "tier must have either percent or unit price"},
+			{"without percent and unit price", &entity.Promotion{Type: entity.TieredPrice, ProductID: 4, Tiers: []*entity.PromotionTier{{MinQuantity: 10}}}, "tier must have either percent or unit price"},
+			{"percent above 100", &entity.Promotion{Type: entity.TieredPrice, ProductID: 4, Tiers: []*entity.PromotionTier{{MinQuantity: 10, Percent: 101}}}, "discount percent must be between 1 and 100"},
+			{"negative unit price", &entity.Promotion{Type: entity.TieredPrice, ProductID: 4, Currency: "USD", Tiers: []*entity.PromotionTier{{MinQuantity: 10, UnitPrice: -1}}}, "unit price must be between 0.01 and 99999999.99"},
+			{"unit price without currency", &entity.Promotion{Type: entity.TieredPrice, ProductID: 4, Tiers: []*entity.PromotionTier{{MinQuantity: 10, UnitPrice: 2500}}}, entity.InvalidCurrency},
+			{"currency without unit price", &entity.Promotion{Type: entity.TieredPrice, ProductID: 4, Currency: "USD", Tiers: []*entity.PromotionTier{{MinQuantity: 10, Percent: 5}}}, "currency is only for tier with unit price"},
+			{"tiers on discount", &entity.Promotion{Type: entity.DiscountInPercent, ProductID: 4, MatchQuantity: 1, PromoValue: 10, Tiers: []*entity.PromotionTier{{MinQuantity: 10, Percent: 5}}}, "tiers are only for tiered price promotion"},
+			{"tiers on cart discount", &entity.Promotion{Type: entity.CartDiscountInPercent, PromoValue: 5, Currency: "USD", Tiers: []*entity.PromotionTier{{MinQuantity: 10, Percent: 5}}}, "tiers are only for tiered price promotion"},
+		}
+		for _, tt := range tests {
+			// repository must not be called
+			err := svc.Create(tt.promo)
+			assert.Equal(t, entity.NewError(tt.message, http.StatusBadRequest), err, tt.name)
+		}
+	})
+
+	t.Run("positive, 10.00 off each item", func(t *testing.T) {
+		promo := &entity.Promotion{Type: entity.DiscountInAmount, ProductID: 2, MatchQuantity: 1, PromoPrice: 1000, Currency: " usd "}
+		productRepo.EXPECT().GetProductByIDs([]int64{2}).Return(products[:1], nil).Times(1)
+		promoRepo.EXPECT().CountBonusPromotions(int64(2), int64(0)).Return(int64(0), nil).Times(1)
+		promoRepo.EXPECT().CreatePromotion(&entity.Promotion{
+			Type: entity.DiscountInAmount, ProductID: 2, MatchQuantity: 1, PromoPrice: 1000, Currency: "USD",
+		}).Return(nil).Times(1)
+
+		err := svc.Create(promo)
+		assert.Nil(t, err)
+	})
+
+	t.Run("negative, invalid fixed amount", func(t *testing.T) {
+		tests := []struct {
+			name    string
+			promo   *entity.Promotion
+			message string
+		}{
+			{"without currency", &entity.Promotion{Type: entity.DiscountInAmount, ProductID: 2, MatchQuantity: 1, PromoPrice: 1000}, entity.InvalidCurrency},
+			{"zero amount", &entity.Promotion{Type: entity.LineDiscountInAmount, ProductID: 2, MatchQuantity: 5, Currency: "USD"}, "promo price must be between 0.01 and 99999999.99"},
+			{"unit price above decimal(10,2)", &entity.Promotion{Type: entity.FixedUnitPrice, ProductID: 2, MatchQuantity: 1, PromoPrice: entity.MaxPrice + 1, Currency: "USD"}, "promo price must be between 0.01 and 99999999.99"},
+			{"promo price on discount in percent", &entity.Promotion{Type: entity.DiscountInPercent, ProductID: 2, MatchQuantity: 1, PromoValue: 10, PromoPrice: 1000}, "min total, promo price, category and currency are only for cart level promotion"},
+		}
+		for _, tt := range tests {
+			// repository must not be called
+			err := svc.Create(tt.promo)
+			assert.Equal(t, entity.NewError(tt.message, http.StatusBadRequest), err, tt.name)
+		}
+	})
+
+	t.Run("negative, product not found", func(t *testing.T) {
+		promo := &entity.Promotion{Type: entity.BonusItem, ProductID: 2, MatchQuantity: 1, PromoValue: 1, PromoProductID: 5}
+		productRepo.EXPECT().GetProductByIDs([]int64{2, 5}).Return(products[:1], nil).Times(1)
+
+		err := svc.Create(promo)
+		assert.Equal(t, entity.NewError(entity.ProductNotFound, http.StatusBadRequest), err)
+	})
+
+	t.Run("negative, free item product cannot be promoted", func(t *testing.T) {
+		promo := &entity.Promotion{Type: entity.DiscountInPercent, ProductID: 4, MatchQuantity: 1, PromoValue: 10}
+		productRepo.EXPECT().GetProductByIDs([]int64{4}).Return(products[1:], nil).Times(1)
+		promoRepo.EXPECT().CountBonusPromotions(int64(4), int64(0)).Return(int64(1), nil).Times(1)
+
+		err := svc.Create(promo)
+		assert.Equal(t, entity.NewError("product is a free item of other promotion, it cannot be promoted", http.StatusBadRequest), err)
+	})
+
+	t.Run("negative, promoted product cannot be free item", func(t *testing.T) {
+		promo := &entity.Promotion{Type: entity.BonusItem, ProductID: 2, MatchQuantity: 1, PromoValue: 1, PromoProductID: 4}
+		productRepo.EXPECT().GetProductByIDs([]int64{2, 4}).Return(products, nil).Times(1)
+		promoRepo.EXPECT().CountBonusPromotions(int64(2), int64(0)).Return(int64(0), nil).Times(1)
+		promoRepo.EXPECT().CountProductPromotions(int64(4), int64(0)).Return(int64(1), nil).Times(1)
+
+		err := svc.Create(promo)
+		assert.Equal(t, entity.NewError("promoted product cannot be set as free item", http.StatusBadRequest), err)
+	})
+}
+
+func Test_CreateCartPromotion(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	svc, promoRepo, productRepo := initPromotionUC(ctrl)
+
+	dayCreated, _ := time.Parse("2006-01-02", "2023-05-16")
+	raspberry := &entity.Product{ID: 4, Serial: "234234", Name: "Raspberry Pi B", Price: 3000, UpdatedAt: dayCreated}
+
+	t.Run("positive, spend 500 get 5% off", func(t *testing.T) {
+		promo := &entity.Promotion{Type: entity.CartDiscountInPercent, PromoValue: 5, MinTotal: 50000, Currency: " usd "}
+		promoRepo.EXPECT().CreatePromotion(&entity.Promotion{
+			Type: entity.CartDiscountInPercent, PromoValue: 5, MinTotal: 50000, Currency: "USD",
+		}).Return(nil).Times(1)
+
+		err := svc.Create(promo)
+		assert.Nil(t, err)
+	})
+
+	t.Run("positive, free raspberry pi on orders over 1000", func(t *testing.T) {
+		promo := &entity.Promotion{Type: entity.CartBonusItem, PromoValue: 1, PromoProductID: 4, MinTotal: 100000, Currency: "USD"}
+		productRepo.EXPECT().GetProductByIDs([]int64{4}).Return([]*entity.Product{raspberry}, nil).Times(1)
+		promoRepo.EXPECT().CountProductPromotions(int64(4), int64(0)).Return(int64(0), nil).Times(1)
+		promoRepo.EXPECT().CreatePromotion(promo).Return(nil).Times(1)
+
+		err := svc.Create(promo)
+		assert.Nil(t, err)
+	})
+
+	t.Run("negative, promoted product cannot be free item of cart", func(t *testing.T) {
+		promo := &entity.Promotion{Type: entity.CartBonusItem, PromoValue: 1, PromoProductID: 4, MinTotal: 100000, Currency: "USD"}
+		productRepo.EXPECT().GetProductByIDs([]int64{4}).Return([]*entity.Product{raspberry}, nil).Times(1)
+		promoRepo.EXPECT().CountProductPromotions(int64(4), int64(0)).Return(int64(1), nil).Times(1)
+
+		err := svc.Create(promo)
+		assert.Equal(t, entity.NewError("promoted product cannot be set as free item", http.StatusBadRequest), err)
+	})
+
+	t.Run("positive, google home and alexa speaker for 140", func(t *testing.T) {
+		promo := &entity.Promotion{Type: entity.ProductBundle, PromoPrice: 14000, Currency: "USD", Items: []*entity.PromotionItem{
+			{ProductID: 1, Quantity: 1},
+			{ProductID: 3, Quantity: 1},
+		}}
+		productRepo.EXPECT().GetProductByIDs([]int64{1, 3}).Return([]*entity.Product{
+			{ID: 1, Serial: "120P90", Name: "Google Home", Price: 4999, UpdatedAt: dayCreated},
+			{ID: 3, Serial: "A304SD", Name: "Alexa Speaker", Price: 10950, UpdatedAt: dayCreated},
+		}, nil).Times(1)
+		promoRepo.EXPECT().CountBonusPromotions(int64(1), int64(0)).Return(int64(0), nil).Times(1)
+		promoRepo.EXPECT().CountBonusPromotions(int64(3), int64(0)).Return(int64(0), nil).Times(1)
+		promoRepo.EXPECT().CreatePromotion(promo).Return(nil).Times(1)
+
+		err := svc.Create(promo)
+		assert.Nil(t, err)
+	})
+
+	t.Run("negative, free item product cannot be bundled", func(t *testing.T) {
+		promo := &entity.Promotion{Type: entity.ProductBundle, PromoPrice: 6000, Currency: "USD", Items: []*entity.PromotionItem{
+			{ProductID: 1, Quantity: 1},
+			{ProductID: 4, Quantity: 1},
+		}}
+		productRepo.EXPECT().GetProductByIDs([]int64{1, 4}).Return([]*entity.Product{
+			{ID: 1, Serial: "120P90", Name: "Google Home", Price: 4999, UpdatedAt: dayCreated},
+			raspberry,
+		}, nil).Times(1)
+		promoRepo.EXPECT().CountBonusPromotions(int64(1), int64(0)).Return(int64(0), nil).Times(1)
+		promoRepo.EXPECT().CountBonusPromotions(int64(4), int64(0)).Return(int64(1), nil).Times(1)
+
+		err := svc.Create(promo)
+		assert.Equal(t, entity.NewError("product is a free item of other promotion, it cannot be promoted", http.StatusBadRequest), err)
+	})
+
+	t.Run("negative, bundled product cannot be free item", func(t *testing.T) {
+		promo := &entity.Promotion{Type: entity.BonusItem, ProductID: 2, MatchQuantity: 1, PromoValue: 1, PromoProductID: 1}
+		productRepo.EXPECT().GetProductByIDs([]int64{2, 1}).Return([]*entity.Product{
+			{ID: 2, Serial: "43N23P", Name: "MacBook Pro", Price: 539999, UpdatedAt: dayCreated},
+			{ID: 1, Serial: "120P90", Name: "Google Home", Price: 4999, UpdatedAt: dayCreated},
+		}, nil).Times(1)
+		promoRepo.EXPECT().CountBonusPromotions(int64(2), int64(0)).Return(int64(0), nil).Times(1)
+		// google home is counted by its bundle
+		promoRepo.EXPECT().CountProductPromotions(int64(1), int64(0)).Return(int64(1), nil).Times(1)
+
+		err := svc.Create(promo)
+		assert.Equal(t, entity.NewError("promoted product cannot be set as free item", http.StatusBadRequest), err)
+	})
+
+	t.Run("negative, bundle product not found", func(t *testing.T) {
+		promo := &entity.Promotion{Type: entity.ProductBundle, PromoValue: 10, Currency: "USD", Items: []*entity.PromotionItem{
+			{ProductID: 1, Quantity: 1},
+			{ProductID: 9, Quantity: 1},
+		}}
+		productRepo.EXPECT().GetProductByIDs([]int64{1, 9}).Return([]*entity.Product{
+			{ID: 1, Serial: "120P90", Name: "Google Home", Price: 4999, UpdatedAt: dayCreated},
+		}, nil).Times(1)
+
+		err := svc.Create(promo)
+		assert.Equal(t, entity.NewError(entity.ProductNotFound, http.StatusBadRequest), err)
+	})
+
+	t.Run("negative, invalid rules", func(t *testing.T) {
+		tests := []struct {
+			name    string
+			promo   *entity.Promotion
+			message string
+		}{
+			{"product level promotion without product", &entity.Promotion{Type: entity.DiscountInPercent, MatchQuantity: 1, PromoValue: 10}, "product id is required"},
+			{"min total on product level promotion", &entity.Promotion{Type: entity.DiscountInPercent, ProductID: 2, MatchQuantity: 1, PromoValue: 10, MinTotal: 100}, "min total, promo price, category and currency are only for cart level promotion"},
+			{"cart level promotion with product", &entity.Promotion{Type: entity.CartDiscountInPercent, ProductID: 2, PromoValue: 5, Currency: "USD"}, "product id must be empty for cart level promotion"},
+			{"empty currency", &entity.Promotion{Type: entity.CartDiscountInPercent, PromoValue: 5}, entity.InvalidCurrency},
+			{"zero percent", &entity.Promotion{Type: entity.CartDiscountInPercent, MinTotal: 50000, Currency: "USD"}, "discount percent must be between 1 and 100"},
+			{"bundle without category", &entity.Promotion{Type: entity.CategoryBundle, MatchQuantity: 3, PromoPrice: 14000, Currency: "USD"}, "category must be 1 to 20 characters"},
+			{"bundle of 1 item", &entity.Promotion{Type: entity.CategoryBundle, MatchQuantity: 1, PromoPrice: 14000, Category: "smart-home", Currency: "USD"}, "number of bundle items must be at least 2"},
+			{"bundle without price", &entity.Promotion{Type: entity.CategoryBundle, MatchQuantity: 3, Category: "smart-home", Currency: "USD"}, "promo price must be between 0.01 and 99999999.99"},
+			{"category on cart discount", &entity.Promotion{Type: entity.CartDiscountInPercent, PromoValue: 5, Category: "smart-home", Currency: "USD"}, "category is only for category bundle promotion"},
+			{"bonus item without promo product", &entity.Promotion{Type: entity.CartBonusItem, PromoValue: 1, MinTotal: 100000, Currency: "USD"}, "promo product id is required for bonus item promotion"},
+			{"bundle of 1 product", &entity.Promotion{Type: entity.ProductBundle, PromoPrice: 14000, Currency: "USD", Items: []*entity.PromotionItem{{ProductID: 1, Quantity: 2}}}, "product bundle must have at least 2 products"},
+			{"bundle of duplicate products", &entity.Promotion{Type: entity.ProductBundle, PromoPrice: 14000, Currency: "USD", Items: []*entity.PromotionItem{{ProductID: 1, Quantity: 1}, {ProductID: 1, Quantity: 1}}}, "product of bundle item must be unique"},
+			{"bundle item without quantity", &entity.Promotion{Type: entity.ProductBundle, PromoPrice: 14000, Currency: "USD", Items: []*entity.PromotionItem{{ProductID: 1, Quantity: 1}, {ProductID: 3}}}, "quantity of bundle item must be at least 1"},
+			{"bundle with price and percent", &entity.Promotion{Type: entity.ProductBundle, PromoPrice: 14000, PromoValue: 10, Currency: "USD", Items: []*entity.PromotionItem{{ProductID: 1, Quantity: 1}, {ProductID: 3, Quantity: 1}}}, "promo price and promo value cannot be set together"},
+			{"bundle without price and percent", &entity.Promotion{Type: entity.ProductBundle, Currency: "USD", Items: []*entity.PromotionItem{{ProductID: 1, Quantity: 1}, {ProductID: 3, Quantity: 1}}}, "discount percent must be between 1 and 100"},
+			{"items on cart discount", &entity.Promotion{Type: entity.CartDiscountInPercent, PromoValue: 5, Currency: "USD", Items: []*entity.PromotionItem{{ProductID: 1, Quantity: 1}}}, "items are only for product bundle promotion"},
+			{"exclusive cart discount", &entity.Promotion{Type: entity.CartDiscountInPercent, PromoValue: 5, Currency: "USD", Exclusive: true}, "priority, exclusive and stackable with are only for product promotion"},
+		}
+		for _, tt := range tests {
+			// repository must not be called
+			err := svc.Create(tt.promo)
+			assert.Equal(t, entity.NewError(tt.message, http.StatusBadRequest), err, tt.name)
+		}
+	})
+}
+
+func Test_UpdatePromotion(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	svc, promoRepo, productRepo := initPromotionUC(ctrl)
+
+	dayCreated, _ := time.Parse("2006-01-02", "2023-05-16")
+	product := &entity.Product{ID: 3, Serial: "A304SD", Name: "Alexa Speaker", Price: 10950, UpdatedAt: dayCreated}
+	existing := &entity.Promotion{ID: 3, Type: entity.DiscountInPercent, ProductID: 3, MatchQuantity: 3, PromoValue: 10, UpdatedAt: dayCreated}
+
+	t.Run("positive", func(t *testing.T) {
+		promo := &entity.Promotion{ID: 3, Type: entity.DiscountInPercent, ProductID: 3, MatchQuantity: 3, PromoValue: 20}
+		promoRepo.EXPECT().GetPromotionByID(int64(3)).Return(existing, nil).Times(1)
+		productRepo.EXPECT().GetProductByIDs([]int64{3}).Return([]*entity.Product{product}, nil).Times(1)
+		// promotion itself is excluded from rule check
+		promoRepo.EXPECT().CountBonusPromotions(int64(3), int64(3)).Return(int64(0), nil).Times(1)
+		promoRepo.EXPECT().UpdatePromotion(promo).Return(nil).Times(1)
+
+		err := svc.Update(promo)
+		assert.Nil(t, err)
+	})
+
+	t.Run("negative, stackable with itself", func(t *testing.T) {
+		promo := &entity.Promotion{ID: 3, Type: entity.DiscountInPercent, ProductID: 3, MatchQuantity: 3, PromoValue: 20, StackableWith: entity.PromotionIDList{3}}
+		promoRepo.EXPECT().GetPromotionByID(int64(3)).Return(existing, nil).Times(1)
+
+		err := svc.Update(promo)
+		assert.Equal(t, entity.NewError("stackable with must be id of other promotions", http.StatusBadRequest), err)
+	})
+
+	t.Run("negative, promotion not found", func(t *testing.T) {
+		promo := &entity.Promotion{ID: 8, Type: entity.DiscountInPercent, ProductID: 3, MatchQuantity: 3, PromoValue: 20}
+		promoRepo.EXPECT().GetPromotionByID(int64(8)).Return(nil, nil).Times(1)
+
+		err := svc.Update(promo)
+		assert.Equal(t, entity.NewError(entity.PromotionNotFound, http.StatusNotFound), err)
+	})
+}
+
+func Test_DeletePromotion(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	svc, promoRepo, _ := initPromotionUC(ctrl)
+
+	dayCreated, _ := time.Parse("2006-01-02", "2023-05-16")
+	existing := &entity.Promotion{ID: 3, Type: entity.DiscountInPercent, ProductID: 3, MatchQuantity: 3, PromoValue: 10, UpdatedAt: dayCreated}
+
+	t.Run("positive", func(t *testing.T) {
+		promoRepo.EXPECT().GetPromotionByID(int64(3)).Return(existing, nil).Times(1)
+		promoRepo.EXPECT().DeletePromotion(int64(3)).Return(nil).Times(1)
+
+		err := svc.Delete(3)
+		assert.Nil(t, err)
+	})
+
+	t.Run("negative, promotion not found", func(t *testing.T) {
+		promoRepo.EXPECT().GetPromotionByID(int64(8)).Return(nil, nil).Times(1)
+
+		err := svc.Delete(8)
+		assert.Equal(t, entity.NewError(entity.PromotionNotFound, http.StatusNotFound), err)
+	})
+}
@@ -0,0 +1,49 @@
+package module
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/gendutski/be-candidate-home-test/core/repository"
+)
+
+// sweep interval when configured interval is not positive
+const defaultSweepInterval = time.Minute
+
+// release expired reservations periodically
+type ReservationSweeper struct {
+	productRepo repository.ProductRepo
+	clock       Clock
+	interval    time.Duration
+}
+
+func NewReservationSweeper(productRepo repository.ProductRepo, clock Clock, interval time.Duration) *ReservationSweeper {
+	if interval <= 0 {
+		interval = defaultSweepInterval
+	}
+	return &ReservationSweeper{productRepo, clock, interval}
+}
+
+// run sweeper until context is done, it is meant to be run as goroutine
+func (s *ReservationSweeper) Run(ctx context.Context) {
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if _, err := s.Sweep(); err != nil {
+				log.Printf("failed to release expired reservations: %s", err.Error())
+			}
+		}
+	}
+}
+
+// release reservations expired at current time
+// return number of released reservations
+func (s *ReservationSweeper) Sweep() (int64, error) {
+	return s.productRepo.ReleaseExpiredReservations(s.clock.Now())
+}
@@ -0,0 +1,80 @@
+package module
+
+import (
+	"net/http"
+	"sort"
+
+	"github.com/gendutski/be-candidate-home-test/core/entity"
+	"github.com/gendutski/be-candidate-home-test/core/repository"
+)
+
+// calculate tax of checkout
+// it is applied after promotions, so tax is calculated from sub total price of each item
+type TaxCalculator interface {
+	Calculate(checkout *entity.Checkout) error
+}
+
+// tax calculator with rate of each product tax category from database
+type rateTaxCalculator struct {
+	taxRepo repository.TaxRepo
+	mode    entity.TaxMode
+}
+
+func NewTaxCalculator(taxRepo repository.TaxRepo, mode entity.TaxMode) TaxCalculator {
+	// unknown mode is exclusive
+	if mode != entity.TaxInclusive {
+		mode = entity.TaxExclusive
+	}
+	return &rateTaxCalculator{taxRepo, mode}
+}
+
+func (c *rateTaxCalculator) Calculate(checkout *entity.Checkout) error {
+	// pluck tax categories
+	var categories []string
+	mapCategory := make(map[string]bool)
+	for _, item := range checkout.Items {
+		category := c.taxCategory(item.Product)
+		if !mapCategory[category] {
+			mapCategory[category] = true
+			categories = append(categories, category)
+		}
+	}
+	sort.Strings(categories)
+
+	// get tax rates
+	rates, err := c.taxRepo.GetTaxRateByCategories(categories)
+	if err != nil {
+		return entity.NewError(err.Error(), http.StatusInternalServerError)
+	}
+	mapRate := make(map[string]int)
+	for _, rate := range rates {
+		mapRate[rate.Category] = rate.Rate
+	}
+
+	// calculate tax of each item
+	// category without tax rate is tax free
+	checkout.TaxTotal = 0
+	for _, item := range checkout.Items {
+		rate := mapRate[c.taxCategory(item.Product)]
+		if c.mode == entity.TaxInclusive {
+			item.TaxAmount = item.SubTotalPrice.IncludedRate(rate)
+		} else {
+			item.TaxAmount = item.SubTotalPrice.Rate(rate)
+		}
+		checkout.TaxTotal += item.TaxAmount
+	}
+
+	// inclusive tax is already in total price
+	checkout.GrandTotal = checkout.TotalPrice
+	if c.mode == entity.TaxExclusive {
+		checkout.GrandTotal += checkout.TaxTotal
+	}
+	return nil
+}
+
+func (c *rateTaxCalculator) taxCategory(product *entity.Product) string {
+	if product.TaxCategory == "" {
+		return entity.DefaultTaxCategory
+	}
+	return product.TaxCategory
+}
@@ -0,0 +1,79 @@
+package module_test
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/gendutski/be-candidate-home-test/core/entity"
+	"github.com/gendutski/be-candidate-home-test/core/module"
+	repomocks "github.com/gendutski/be-candidate-home-test/core/repository/mocks"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_TaxCalculate(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	taxRepo := repomocks.NewMockTaxRepo(ctrl)
+
+	dayCreated, _ := time.Parse("2006-01-02", "2023-05-16")
+	products := []*entity.Product{
+		{ID: 1, Serial: "120P90", Name: "Google Home", Price: 4999, UpdatedAt: dayCreated},
+		{ID: 3, Serial: "A304SD", Name: "Alexa Speaker", Price: 10950, TaxCategory: "reduced", UpdatedAt: dayCreated},
+		{ID: 4, Serial: "234234", Name: "Raspberry Pi B", Price: 3000, TaxCategory: "exempt", UpdatedAt: dayCreated},
+	}
+	rates := []*entity.TaxRate{
+		{ID: 1, Category: "reduced", Rate: 500, UpdatedAt: dayCreated},
+		{ID: 2, Category: "standard", Rate: 1100, UpdatedAt: dayCreated},
+	}
+	newCheckout := func() *entity.Checkout {
+		return &entity.Checkout{
+			Items: []*entity.CheckoutItem{
+				{Product: products[0], Quantity: 3, SubTotalPrice: 4999 * 2},
+				{Product: products[1], Quantity: 1, SubTotalPrice: 10950},
+				{Product: products[2], Quantity: 1, SubTotalPrice: 3000},
+			},
+			TotalItem:     5,
+			TotalPrice:    4999*2 + 10950 + 3000,
+			TotalDiscount: 4999,
+		}
+	}
+
+	t.Run("exclusive tax", func(t *testing.T) {
+		taxRepo.EXPECT().GetTaxRateByCategories([]string{"exempt", "reduced", "standard"}).Return(rates, nil).Times(1)
+		checkout := newCheckout()
+
+		err := module.NewTaxCalculator(taxRepo, entity.TaxExclusive).Calculate(checkout)
+		assert.Nil(t, err)
+		// 11% of 99.98, 5% of 109.50, exempt has no rate
+		assert.Equal(t, entity.Money(1100), checkout.Items[0].TaxAmount)
+		assert.Equal(t, entity.Money(548), checkout.Items[1].TaxAmount)
+		assert.Equal(t, entity.Money(0), checkout.Items[2].TaxAmount)
+		assert.Equal(t, entity.Money(1648), checkout.TaxTotal)
+		assert.Equal(t, checkout.TotalPrice+1648, checkout.GrandTotal)
+	})
+
+	t.Run("inclusive tax", func(t *testing.T) {
+		taxRepo.EXPECT().GetTaxRateByCategories([]string{"exempt", "reduced", "standard"}).Return(rates, nil).Times(1)
+		checkout := newCheckout()
+
+		err := module.NewTaxCalculator(taxRepo, entity.TaxInclusive).Calculate(checkout)
+		assert.Nil(t, err)
+		// tax included in 99.98 with 11%, and 109.50 with 5%
+		assert.Equal(t, entity.Money(991), checkout.Items[0].TaxAmount)
+		assert.Equal(t, entity.Money(521), checkout.Items[1].TaxAmount)
+		assert.Equal(t, entity.Money(0), checkout.Items[2].TaxAmount)
+		assert.Equal(t, entity.Money(1512), checkout.TaxTotal)
+		assert.Equal(t, checkout.TotalPrice, checkout.GrandTotal)
+	})
+
+	t.Run("failed to get tax rates", func(t *testing.T) {
+		taxRepo.EXPECT().GetTaxRateByCategories(gomock.Any()).Return(nil, errors.New("connection lost")).Times(1)
+
+		err := module.NewTaxCalculator(taxRepo, entity.TaxExclusive).Calculate(newCheckout())
+		assert.Equal(t, entity.NewError("connection lost", http.StatusInternalServerError), err)
+	})
+}
@@ -0,0 +1,13 @@
+package repository
+
+import "github.com/gendutski/be-candidate-home-test/core/entity"
+
+type CartRepo interface {
+	CreateCart(cart *entity.Cart) error
+	// get cart with its items and products
+	// will return nil if cart not found
+	GetCartByID(id int64) (*entity.Cart, error)
+	// insert cart item, or update its quantity if product is already in cart
+	SaveCartItem(item *entity.CartItem) error
+	DeleteCartItem(cartID, productID int64) error
+}
@@ -6,6 +6,7 @@ package mock_repository
 
 import (
 	reflect "reflect"
+	time "time"
 
 	entity "github.com/gendutski/be-candidate-home-test/core/entity"
 	gomock "github.com/golang/mock/gomock"
@@ -34,6 +35,78 @@ func (m *MockProductRepo) EXPECT() *MockProductRepoMockRecorder {
 	return m.recorder
 }
 
+// AdjustQuantity mocks base method.
+func (m *MockProductRepo) AdjustQuantity(movement *entity.StockMovement) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AdjustQuantity", movement)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AdjustQuantity indicates an expected call of AdjustQuantity.
+func (mr *MockProductRepoMockRecorder) AdjustQuantity(movement interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AdjustQuantity", reflect.TypeOf((*MockProductRepo)(nil).AdjustQuantity), movement)
+}
+
+// CountProductBySerial mocks base method.
+func (m *MockProductRepo) CountProductBySerial(serial string, exceptID int64) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CountProductBySerial", serial, exceptID)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CountProductBySerial indicates an expected call of CountProductBySerial.
+func (mr *MockProductRepoMockRecorder) CountProductBySerial(serial, exceptID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountProductBySerial", reflect.TypeOf((*MockProductRepo)(nil).CountProductBySerial), serial, exceptID)
+}
+
+// CreateProduct mocks base method.
+func (m *MockProductRepo) CreateProduct(product *entity.Product, quantity int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateProduct", product, quantity)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateProduct indicates an expected call of CreateProduct.
+func (mr *MockProductRepoMockRecorder) CreateProduct(product, quantity interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateProduct", reflect.TypeOf((*MockProductRepo)(nil).CreateProduct), product, quantity)
+}
+
+// DeleteProduct mocks base method.
+func (m *MockProductRepo) DeleteProduct(id int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteProduct", id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteProduct indicates an expected call of DeleteProduct.
+func (mr *MockProductRepoMockRecorder) DeleteProduct(id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteProduct", reflect.TypeOf((*MockProductRepo)(nil).DeleteProduct), id)
+}
+
+// GetProductByID mocks base method.
+func (m *MockProductRepo) GetProductByID(id int64) (*entity.Product, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetProductByID", id)
+	ret0, _ := ret[0].(*entity.Product)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetProductByID indicates an expected call of GetProductByID.
+func (mr *MockProductRepoMockRecorder) GetProductByID(id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetProductByID", reflect.TypeOf((*MockProductRepo)(nil).GetProductByID), id)
+}
+
 // GetProductByIDs mocks base method.
 func (m *MockProductRepo) GetProductByIDs(ids []int64) ([]*entity.Product, error) {
 	m.ctrl.T.Helper()
@@ -64,16 +137,122 @@ func (mr *MockProductRepoMockRecorder) GetProductBySerials(serials interface{})
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetProductBySerials", reflect.TypeOf((*MockProductRepo)(nil).GetProductBySerials), serials)
 }
 
-// SubmitCheckout mocks base method.
-func (m *MockProductRepo) SubmitCheckout(payload *entity.Checkout) error {
+// GetProductPrices mocks base method.
+func (m *MockProductRepo) GetProductPrices(productIDs []int64, currency string) ([]*entity.ProductPrice, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "SubmitCheckout", payload)
+	ret := m.ctrl.Call(m, "GetProductPrices", productIDs, currency)
+	ret0, _ := ret[0].([]*entity.ProductPrice)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetProductPrices indicates an expected call of GetProductPrices.
+func (mr *MockProductRepoMockRecorder) GetProductPrices(productIDs, currency interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetProductPrices", reflect.TypeOf((*MockProductRepo)(nil).GetProductPrices), productIDs, currency)
+}
+
+// GetProductQuantities mocks base method.
+func (m *MockProductRepo) GetProductQuantities(productIDs []int64) ([]*entity.ProductQuantity, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetProductQuantities", productIDs)
+	ret0, _ := ret[0].([]*entity.ProductQuantity)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetProductQuantities indicates an expected call of GetProductQuantities.
+func (mr *MockProductRepoMockRecorder) GetProductQuantities(productIDs interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetProductQuantities", reflect.TypeOf((*MockProductRepo)(nil).GetProductQuantities), productIDs)
+}
+
+// GetProducts mocks base method.
+func (m *MockProductRepo) GetProducts(filter *entity.ProductFilter) ([]*entity.Product, int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetProducts", filter)
+	ret0, _ := ret[0].([]*entity.Product)
+	ret1, _ := ret[1].(int64)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// GetProducts indicates an expected call of GetProducts.
+func (mr *MockProductRepoMockRecorder) GetProducts(filter interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetProducts", reflect.TypeOf((*MockProductRepo)(nil).GetProducts), filter)
+}
+
+// GetStockMovements mocks base method.
+func (m *MockProductRepo) GetStockMovements(filter *entity.StockMovementFilter) ([]*entity.StockMovement, int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetStockMovements", filter)
+	ret0, _ := ret[0].([]*entity.StockMovement)
+	ret1, _ := ret[1].(int64)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// GetStockMovements indicates an expected call of GetStockMovements.
+func (mr *MockProductRepoMockRecorder) GetStockMovements(filter interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetStockMovements", reflect.TypeOf((*MockProductRepo)(nil).GetStockMovements), filter)
+}
+
+// ReleaseExpiredReservations mocks base method.
+func (m *MockProductRepo) ReleaseExpiredReservations(at time.Time) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReleaseExpiredReservations", at)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ReleaseExpiredReservations indicates an expected call of ReleaseExpiredReservations.
+func (mr *MockProductRepoMockRecorder) ReleaseExpiredReservations(at interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReleaseExpiredReservations", reflect.TypeOf((*MockProductRepo)(nil).ReleaseExpiredReservations), at)
+}
+
+// ReserveStock mocks base method.
+func (m *MockProductRepo) ReserveStock(reservation *entity.Reservation) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReserveStock", reservation)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
+// ReserveStock indicates an expected call of ReserveStock.
+func (mr *MockProductRepoMockRecorder) ReserveStock(reservation interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReserveStock", reflect.TypeOf((*MockProductRepo)(nil).ReserveStock), reservation)
+}
+
+// SubmitCheckout mocks base method.
+func (m *MockProductRepo) SubmitCheckout(payload *entity.Checkout) (*entity.Order, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SubmitCheckout", payload)
+	ret0, _ := ret[0].(*entity.Order)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
 // SubmitCheckout indicates an expected call of SubmitCheckout.
 func (mr *MockProductRepoMockRecorder) SubmitCheckout(payload interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SubmitCheckout", reflect.TypeOf((*MockProductRepo)(nil).SubmitCheckout), payload)
 }
+
+// UpdateProduct mocks base method.
+func (m *MockProductRepo) UpdateProduct(product *entity.Product) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateProduct", product)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateProduct indicates an expected call of UpdateProduct.
+func (mr *MockProductRepoMockRecorder) UpdateProduct(product interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateProduct", reflect.TypeOf((*MockProductRepo)(nil).UpdateProduct), product)
+}
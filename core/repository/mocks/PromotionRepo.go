@@ -6,6 +6,7 @@ package mock_repository
 
 import (
 	reflect "reflect"
+	time "time"
 
 	entity "github.com/gendutski/be-candidate-home-test/core/entity"
 	gomock "github.com/golang/mock/gomock"
@@ -34,17 +35,149 @@ func (m *MockPromotionRepo) EXPECT() *MockPromotionRepoMockRecorder {
 	return m.recorder
 }
 
+// CountBonusPromotions mocks base method.
+func (m *MockPromotionRepo) CountBonusPromotions(promoProductID, exceptID int64) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CountBonusPromotions", promoProductID, exceptID)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CountBonusPromotions indicates an expected call of CountBonusPromotions.
+func (mr *MockPromotionRepoMockRecorder) CountBonusPromotions(promoProductID, exceptID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountBonusPromotions", reflect.TypeOf((*MockPromotionRepo)(nil).CountBonusPromotions), promoProductID, exceptID)
+}
+
+// CountProductPromotions mocks base method.
+func (m *MockPromotionRepo) CountProductPromotions(productID, exceptID int64) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CountProductPromotions", productID, exceptID)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CountProductPromotions indicates an expected call of CountProductPromotions.
+func (mr *MockPromotionRepoMockRecorder) CountProductPromotions(productID, exceptID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountProductPromotions", reflect.TypeOf((*MockPromotionRepo)(nil).CountProductPromotions), productID, exceptID)
+}
+
+// CreatePromotion mocks base method.
+func (m *MockPromotionRepo) CreatePromotion(promo *entity.Promotion) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreatePromotion", promo)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreatePromotion indicates an expected call of CreatePromotion.
+func (mr *MockPromotionRepoMockRecorder) CreatePromotion(promo interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreatePromotion", reflect.TypeOf((*MockPromotionRepo)(nil).CreatePromotion), promo)
+}
+
+// DeletePromotion mocks base method.
+func (m *MockPromotionRepo) DeletePromotion(id int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeletePromotion", id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeletePromotion indicates an expected call of DeletePromotion.
+func (mr *MockPromotionRepoMockRecorder) DeletePromotion(id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeletePromotion", reflect.TypeOf((*MockPromotionRepo)(nil).DeletePromotion), id)
+}
+
+// GetCartPromotions mocks base method.
+func (m *MockPromotionRepo) GetCartPromotions(at time.Time) ([]*entity.Promotion, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetCartPromotions", at)
+	ret0, _ := ret[0].([]*entity.Promotion)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetCartPromotions indicates an expected call of GetCartPromotions.
+func (mr *MockPromotionRepoMockRecorder) GetCartPromotions(at interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCartPromotions", reflect.TypeOf((*MockPromotionRepo)(nil).GetCartPromotions), at)
+}
+
+// GetCouponByCode mocks base method.
+func (m *MockPromotionRepo) GetCouponByCode(code string) (*entity.Coupon, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetCouponByCode", code)
+	ret0, _ := ret[0].(*entity.Coupon)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetCouponByCode indicates an expected call of GetCouponByCode.
+func (mr *MockPromotionRepoMockRecorder) GetCouponByCode(code interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCouponByCode", reflect.TypeOf((*MockPromotionRepo)(nil).GetCouponByCode), code)
+}
+
+// GetPromotionByID mocks base method.
+func (m *MockPromotionRepo) GetPromotionByID(id int64) (*entity.Promotion, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPromotionByID", id)
+	ret0, _ := ret[0].(*entity.Promotion)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetPromotionByID indicates an expected call of GetPromotionByID.
+func (mr *MockPromotionRepoMockRecorder) GetPromotionByID(id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPromotionByID", reflect.TypeOf((*MockPromotionRepo)(nil).GetPromotionByID), id)
+}
+
 // GetPromotionByProducts mocks base method.
-func (m *MockPromotionRepo) GetPromotionByProducts(products []*entity.Product) (map[int64][]*entity.Promotion, error) {
+func (m *MockPromotionRepo) GetPromotionByProducts(products []*entity.Product, at time.Time) (map[int64][]*entity.Promotion, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetPromotionByProducts", products)
+	ret := m.ctrl.Call(m, "GetPromotionByProducts", products, at)
 	ret0, _ := ret[0].(map[int64][]*entity.Promotion)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetPromotionByProducts indicates an expected call of GetPromotionByProducts.
-func (mr *MockPromotionRepoMockRecorder) GetPromotionByProducts(products interface{}) *gomock.Call {
+func (mr *MockPromotionRepoMockRecorder) GetPromotionByProducts(products, at interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPromotionByProducts", reflect.TypeOf((*MockPromotionRepo)(nil).GetPromotionByProducts), products, at)
+}
+
+// GetPromotions mocks base method.
+func (m *MockPromotionRepo) GetPromotions() ([]*entity.Promotion, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPromotions")
+	ret0, _ := ret[0].([]*entity.Promotion)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetPromotions indicates an expected call of GetPromotions.
+func (mr *MockPromotionRepoMockRecorder) GetPromotions() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPromotions", reflect.TypeOf((*MockPromotionRepo)(nil).GetPromotions))
+}
+
+// UpdatePromotion mocks base method.
+func (m *MockPromotionRepo) UpdatePromotion(promo *entity.Promotion) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdatePromotion", promo)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdatePromotion indicates an expected call of UpdatePromotion.
+func (mr *MockPromotionRepoMockRecorder) UpdatePromotion(promo interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPromotionByProducts", reflect.TypeOf((*MockPromotionRepo)(nil).GetPromotionByProducts), products)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdatePromotion", reflect.TypeOf((*MockPromotionRepo)(nil).UpdatePromotion), promo)
 }
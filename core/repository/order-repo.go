@@ -0,0 +1,12 @@
+package repository
+
+import "github.com/gendutski/be-candidate-home-test/core/entity"
+
+type OrderRepo interface {
+	// get order with its items and products
+	// will return nil if order not found
+	GetOrderByID(id int64) (*entity.Order, error)
+	// get paginated orders
+	// will return orders of current page and total orders matched the filter
+	GetOrders(filter *entity.OrderFilter) ([]*entity.Order, int64, error)
+}
@@ -1,9 +1,39 @@
 package repository
 
-import "github.com/gendutski/be-candidate-home-test/core/entity"
+import (
+	"time"
+
+	"github.com/gendutski/be-candidate-home-test/core/entity"
+)
 
 type ProductRepo interface {
 	GetProductBySerials(serials []string) ([]*entity.Product, error)
 	GetProductByIDs(ids []int64) ([]*entity.Product, error)
-	SubmitCheckout(payload *entity.Checkout) error
+	// get product price list in the currency
+	GetProductPrices(productIDs []int64, currency string) ([]*entity.ProductPrice, error)
+	// get product quantity without locking
+	GetProductQuantities(productIDs []int64) ([]*entity.ProductQuantity, error)
+	// decrease product quantity, write the order and close the cart in one transaction
+	SubmitCheckout(payload *entity.Checkout) (*entity.Order, error)
+	// get product by id, return nil if not found
+	GetProductByID(id int64) (*entity.Product, error)
+	// get products of current page and total products
+	GetProducts(filter *entity.ProductFilter) ([]*entity.Product, int64, error)
+	// count products with the serial including deleted products, except product with exceptID
+	CountProductBySerial(serial string, exceptID int64) (int64, error)
+	// create product and its product quantity in one transaction
+	CreateProduct(product *entity.Product, quantity int) error
+	UpdateProduct(product *entity.Product) error
+	// soft delete product
+	DeleteProduct(id int64) error
+	// change product quantity by movement delta and write the movement in one transaction
+	// movement balance is filled with the new quantity
+	AdjustQuantity(movement *entity.StockMovement) error
+	// get stock movements of product, latest first, and total movements
+	GetStockMovements(filter *entity.StockMovementFilter) ([]*entity.StockMovement, int64, error)
+	// hold available quantity of reservation items and write the reservation in one transaction
+	ReserveStock(reservation *entity.Reservation) error
+	// release reserved quantity of active reservations that expired at the time
+	// return number of released reservations
+	ReleaseExpiredReservations(at time.Time) (int64, error)
 }
@@ -1,9 +1,33 @@
 package repository
 
-import "github.com/gendutski/be-candidate-home-test/core/entity"
+import (
+	"time"
+
+	"github.com/gendutski/be-candidate-home-test/core/entity"
+)
 
 type PromotionRepo interface {
-	// get promotion by products
+	// get promotion by products, only promotions that are active at the time
 	// will return map[int64] where int64 is product id
-	GetPromotionByProducts(products []*entity.Product) (map[int64][]*entity.Promotion, error)
+	GetPromotionByProducts(products []*entity.Product, at time.Time) (map[int64][]*entity.Promotion, error)
+	// get cart level promotions that are active at the time
+	// sorted by type in ascending order, with items of product bundle
+	GetCartPromotions(at time.Time) ([]*entity.Promotion, error)
+	// get promotion by id with its items, return nil if not found
+	GetPromotionByID(id int64) (*entity.Promotion, error)
+	// get all promotions which are not deleted, with their items
+	GetPromotions() ([]*entity.Promotion, error)
+	// count promotions of product, except promotion with exceptID
+	CountProductPromotions(productID int64, exceptID int64) (int64, error)
+	// count bonus item promotions that give product as free item, except promotion with exceptID
+	CountBonusPromotions(promoProductID int64, exceptID int64) (int64, error)
+	// create promotion and its items in one transaction
+	CreatePromotion(promo *entity.Promotion) error
+	// update promotion and replace its items in one transaction
+	UpdatePromotion(promo *entity.Promotion) error
+	// soft delete promotion
+	DeletePromotion(id int64) error
+	// get coupon by code, code is case insensitive
+	// return nil if not found
+	GetCouponByCode(code string) (*entity.Coupon, error)
 }
@@ -0,0 +1,8 @@
+package repository
+
+import "github.com/gendutski/be-candidate-home-test/core/entity"
+
+type TaxRepo interface {
+	// get tax rates of product tax categories
+	GetTaxRateByCategories(categories []string) ([]*entity.TaxRate, error)
+}
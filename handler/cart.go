@@ -0,0 +1,149 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/gendutski/be-candidate-home-test/core/entity"
+	"github.com/gendutski/be-candidate-home-test/core/module"
+	"github.com/labstack/echo/v4"
+)
+
+type CartHandler struct {
+	cartUC module.CartUsecase
+}
+
+func NewCartHandler(cartUC module.CartUsecase) *CartHandler {
+	return &CartHandler{cartUC}
+}
+
+type cartPayload struct {
+	Currency string `json:"currency"`
+}
+
+type cartDetailPayload struct {
+	ID int64 `param:"id" validate:"required"`
+}
+
+type cartItemPayload struct {
+	ID       int64  `param:"id" json:"-" validate:"required"`
+	Serial   string `param:"serial" json:"-" validate:"required"`
+	Quantity int    `json:"quantity"`
+}
+
+type cartCheckoutPayload struct {
+	ID int64 `param:"id" json:"-" validate:"required"`
+	// reservation from reserve endpoint, optional
+	ReservationID int64 `json:"reservationId"`
+}
+
+type cartResponse struct {
+	CartID int64 `json:"cartId"`
+	// order created from this cart, 0 for open cart
+	OrderID int64 `json:"orderId"`
+	*quoteResponse
+}
+
+func (h *CartHandler) Create(c echo.Context) error {
+	p := new(cartPayload)
+	// bind json payload
+	if err := c.Bind(p); err != nil {
+		return err
+	}
+
+	cart := entity.Cart{Currency: p.Currency}
+	if err := h.cartUC.Create(&cart); err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusCreated, newCartResponse(&cart, &entity.Checkout{Currency: cart.Currency}))
+}
+
+func (h *CartHandler) Get(c echo.Context) error {
+	p := new(cartDetailPayload)
+	// bind path param
+	if err := c.Bind(p); err != nil {
+		return err
+	}
+	// validate payload
+	if err := c.Validate(p); err != nil {
+		return err
+	}
+
+	return h.responseCart(p.ID, c)
+}
+
+func (h *CartHandler) SetItem(c echo.Context) error {
+	p, err := h.bindItemPayload(c)
+	if err != nil {
+		return err
+	}
+
+	if err := h.cartUC.SetItem(p.ID, p.Serial, p.Quantity); err != nil {
+		return err
+	}
+
+	return h.responseCart(p.ID, c)
+}
+
+func (h *CartHandler) RemoveItem(c echo.Context) error {
+	p, err := h.bindItemPayload(c)
+	if err != nil {
+		return err
+	}
+
+	if err := h.cartUC.RemoveItem(p.ID, p.Serial); err != nil {
+		return err
+	}
+
+	return c.NoContent(http.StatusNoContent)
+}
+
+func (h *CartHandler) Checkout(c echo.Context) error {
+	p := new(cartCheckoutPayload)
+	// bind path param and json payload
+	if err := c.Bind(p); err != nil {
+		return err
+	}
+	// validate payload
+	if err := c.Validate(p); err != nil {
+		return err
+	}
+
+	order, err := h.cartUC.Checkout(p.ID, p.ReservationID)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusOK, newOrderResponse(order))
+}
+
+func (h *CartHandler) bindItemPayload(c echo.Context) (*cartItemPayload, error) {
+	p := new(cartItemPayload)
+	// bind path param and json payload
+	if err := c.Bind(p); err != nil {
+		return nil, err
+	}
+	// validate payload
+	if err := c.Validate(p); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+// response cart with live quote
+func (h *CartHandler) responseCart(id int64, c echo.Context) error {
+	cart, checkout, err := h.cartUC.Get(id)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusOK, newCartResponse(cart, checkout))
+}
+
+func newCartResponse(cart *entity.Cart, checkout *entity.Checkout) *cartResponse {
+	return &cartResponse{
+		CartID:        cart.ID,
+		OrderID:       cart.OrderID,
+		quoteResponse: newQuoteResponse(checkout),
+	}
+}
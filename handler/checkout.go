@@ -2,6 +2,7 @@ package handler
 
 import (
 	"net/http"
+	"time"
 
 	"github.com/gendutski/be-candidate-home-test/core/entity"
 	"github.com/gendutski/be-candidate-home-test/core/module"
@@ -16,65 +17,299 @@ func NewCheckoutHandler(checkoutUC module.CheckoutUsecase) *CheckoutHandler {
 	return &CheckoutHandler{checkoutUC}
 }
 
+type payloadItem struct {
+	Serial   string `json:"serial" validate:"required"`
+	Quantity int    `json:"quantity"`
+}
+
 type payload struct {
-	ProductSerials []string `json:"productSerials" validate:"required"`
+	// each serial is one item, duplicate serials are counted as quantity
+	ProductSerials []string `json:"productSerials"`
+	// explicit quantity of each serial, can be combined with product serials
+	Items    []*payloadItem `json:"items" validate:"dive,required"`
+	Currency string         `json:"currency"`
+	// skip unknown serials and return them as warnings, instead of rejecting the request
+	Lenient bool `json:"lenient"`
+	// reservation from reserve endpoint, only for submit
+	ReservationID int64 `json:"reservationId"`
+	// voucher code applied after promotions
+	CouponCode string `json:"couponCode"`
+	// customer who redeems the coupon, required by coupon with per customer limit
+	CustomerID string `json:"customerId"`
+}
+
+type appliedPromotionResponse struct {
+	PromotionID    int64        `json:"promotionId"`
+	Type           int          `json:"type"`
+	DiscountAmount entity.Money `json:"discountAmount"`
+	FreeQuantity   int          `json:"freeQuantity"`
+}
+
+type warningResponse struct {
+	Serial  string `json:"serial"`
+	Message string `json:"message"`
 }
 
 type responseItem struct {
-	Serial   string  `json:"serial"`
-	Name     string  `json:"name"`
-	Quantity int     `json:"quantity"`
-	Price    float64 `json:"price"`
-	SubTotal float64 `json:"subTotal"`
+	Serial            string                      `json:"serial"`
+	Name              string                      `json:"name"`
+	Quantity          int                         `json:"quantity"`
+	Price             entity.Money                `json:"price"`
+	SubTotal          entity.Money                `json:"subTotal"`
+	Tax               entity.Money                `json:"tax"`
+	AppliedPromotions []*appliedPromotionResponse `json:"appliedPromotions"`
+	// share of coupon discount, already deducted from sub total
+	CouponDiscount entity.Money `json:"couponDiscount"`
 }
 
 type response struct {
-	Items      []*responseItem `json:"items"`
-	TotalItems int             `json:"totalItems"`
-	TotalPrice float64         `json:"totalPrice"`
+	OrderID       int64           `json:"orderId"`
+	Items         []*responseItem `json:"items"`
+	TotalItems    int             `json:"totalItems"`
+	TotalPrice    entity.Money    `json:"totalPrice"`
+	TotalDiscount entity.Money    `json:"totalDiscount"`
+	TaxTotal      entity.Money    `json:"taxTotal"`
+	GrandTotal    entity.Money    `json:"grandTotal"`
+	Currency      string          `json:"currency"`
+	// cart level promotions
+	Adjustments []*appliedPromotionResponse `json:"adjustments"`
+	// coupon used by the order, empty for order without coupon
+	CouponCode     string       `json:"couponCode"`
+	CouponDiscount entity.Money `json:"couponDiscount"`
+	CreatedAt      time.Time    `json:"createdAt"`
+	// unknown serials skipped by lenient request
+	Warnings []*warningResponse `json:"warnings"`
+}
+
+type quoteResponseItem struct {
+	responseItem
+	Available int  `json:"available"`
+	InStock   bool `json:"inStock"`
+}
+
+type quoteResponse struct {
+	Items         []*quoteResponseItem `json:"items"`
+	TotalItems    int                  `json:"totalItems"`
+	TotalPrice    entity.Money         `json:"totalPrice"`
+	TotalDiscount entity.Money         `json:"totalDiscount"`
+	TaxTotal      entity.Money         `json:"taxTotal"`
+	GrandTotal    entity.Money         `json:"grandTotal"`
+	Currency      string               `json:"currency"`
+	// cart level promotions
+	Adjustments []*appliedPromotionResponse `json:"adjustments"`
+	// applied coupon, empty for checkout without coupon
+	CouponCode     string       `json:"couponCode"`
+	CouponDiscount entity.Money `json:"couponDiscount"`
+	InStock        bool         `json:"inStock"`
+	// unknown serials skipped by lenient request
+	Warnings []*warningResponse `json:"warnings"`
+}
+
+type reservationItemResponse struct {
+	Serial   string `json:"serial"`
+	Name     string `json:"name"`
+	Quantity int    `json:"quantity"`
+}
+
+type reservationResponse struct {
+	ReservationID int64                      `json:"reservationId"`
+	Items         []*reservationItemResponse `json:"items"`
+	ExpiresAt     time.Time                  `json:"expiresAt"`
+	// unknown serials skipped by lenient request
+	Warnings []*warningResponse `json:"warnings"`
 }
 
 func (h *CheckoutHandler) Submit(c echo.Context) error {
+	request, err := h.bindPayload(c)
+	if err != nil {
+		return err
+	}
+
+	resp, err := h.checkoutUC.Submit(request)
+	if err != nil {
+		return err
+	}
+
+	return h.parseToResponse(resp, c)
+}
+
+func (h *CheckoutHandler) Quote(c echo.Context) error {
+	request, err := h.bindPayload(c)
+	if err != nil {
+		return err
+	}
+
+	resp, err := h.checkoutUC.Quote(request)
+	if err != nil {
+		return err
+	}
+
+	return h.parseToQuoteResponse(resp, c)
+}
+
+func (h *CheckoutHandler) Reserve(c echo.Context) error {
+	request, err := h.bindPayload(c)
+	if err != nil {
+		return err
+	}
+
+	resp, err := h.checkoutUC.Reserve(request)
+	if err != nil {
+		return err
+	}
+
+	result := reservationResponse{
+		ReservationID: resp.ID,
+		ExpiresAt:     resp.ExpiresAt,
+		Warnings:      newWarningsResponse(resp.UnknownSerials),
+	}
+	for _, item := range resp.Items {
+		result.Items = append(result.Items, &reservationItemResponse{
+			Serial:   item.Product.Serial,
+			Name:     item.Product.Name,
+			Quantity: item.Quantity,
+		})
+	}
+
+	return c.JSON(http.StatusOK, result)
+}
+
+func (h *CheckoutHandler) bindPayload(c echo.Context) (*entity.CheckoutRequest, error) {
 	p := new(payload)
 	// bind json payload
 	if err := c.Bind(p); err != nil {
-		return err
+		return nil, err
 	}
 	// validate payload
 	if err := c.Validate(p); err != nil {
-		return err
+		return nil, err
+	}
+
+	if len(p.ProductSerials) == 0 && len(p.Items) == 0 {
+		return nil, entity.NewError("productSerials or items is required", http.StatusBadRequest)
 	}
 
 	// map payload
-	mapPayload := make(map[string]int)
+	mapPayload := make(entity.MapProductSerialQuantity)
 	for _, serial := range p.ProductSerials {
 		mapPayload[serial]++
 	}
+	for _, item := range p.Items {
+		// zero or negative line must not reduce quantity of other lines
+		if item.Quantity <= 0 {
+			return nil, entity.NewError(entity.EmptyQuantity, http.StatusBadRequest)
+		}
+		mapPayload[item.Serial] += item.Quantity
+	}
+	return &entity.CheckoutRequest{
+		Items:         mapPayload,
+		Currency:      p.Currency,
+		ReservationID: p.ReservationID,
+		Lenient:       p.Lenient,
+		CouponCode:    p.CouponCode,
+		CustomerID:    p.CustomerID,
+	}, nil
+}
 
-	resp, err := h.checkoutUC.Submit(mapPayload)
-	if err != nil {
-		return err
+func (h *CheckoutHandler) parseToResponse(p *entity.Order, c echo.Context) error {
+	return c.JSON(http.StatusOK, newOrderResponse(p))
+}
+
+func (h *CheckoutHandler) parseToQuoteResponse(p *entity.Checkout, c echo.Context) error {
+	return c.JSON(http.StatusOK, newQuoteResponse(p))
+}
+
+func newQuoteResponse(p *entity.Checkout) *quoteResponse {
+	result := quoteResponse{
+		Items:         []*quoteResponseItem{},
+		TotalItems:    p.TotalItem,
+		TotalPrice:    p.TotalPrice,
+		TotalDiscount: p.TotalDiscount,
+		TaxTotal:      p.TaxTotal,
+		GrandTotal:    p.GrandTotal,
+		Currency:      p.Currency,
+		Adjustments:   newAppliedPromotionsResponse(p.Adjustments),
+		InStock:       true,
+		Warnings:      newWarningsResponse(p.UnknownSerials),
+	}
+	if p.Coupon != nil {
+		result.CouponCode = p.Coupon.Code
+		result.CouponDiscount = p.CouponDiscount
 	}
 
-	return h.parseToResponse(resp, c)
+	for _, item := range p.Items {
+		result.Items = append(result.Items, &quoteResponseItem{
+			responseItem: responseItem{
+				Serial:            item.Product.Serial,
+				Name:              item.Product.Name,
+				Quantity:          item.Quantity,
+				Price:             item.Product.Price,
+				SubTotal:          item.SubTotalPrice,
+				Tax:               item.TaxAmount,
+				AppliedPromotions: newAppliedPromotionsResponse(item.AppliedPromotions),
+				CouponDiscount:    item.CouponDiscount,
+			},
+			Available: item.AvailableQuantity,
+			InStock:   item.InStock(),
+		})
+		result.InStock = result.InStock && item.InStock()
+	}
+
+	return &result
 }
 
-func (h *CheckoutHandler) parseToResponse(p *entity.Checkout, c echo.Context) error {
+func newOrderResponse(p *entity.Order) *response {
 	result := response{
-		TotalItems: p.TotalItem,
-		TotalPrice: p.TotalPrice,
+		OrderID:        p.ID,
+		TotalItems:     p.TotalItem,
+		TotalPrice:     p.TotalPrice,
+		TotalDiscount:  p.TotalDiscount,
+		TaxTotal:       p.TaxTotal,
+		GrandTotal:     p.GrandTotal,
+		Currency:       p.Currency,
+		Adjustments:    newAppliedPromotionsResponse(p.Adjustments),
+		CouponCode:     p.CouponCode,
+		CouponDiscount: p.CouponDiscount,
+		CreatedAt:      p.CreatedAt,
+		Warnings:       newWarningsResponse(p.UnknownSerials),
 	}
 
 	for _, item := range p.Items {
 		result.Items = append(result.Items, &responseItem{
-			Serial:   item.Product.Serial,
-			Name:     item.Product.Name,
-			Quantity: item.Quantity,
-			Price:    item.Product.Price,
-			SubTotal: item.SubTotalPrice,
+			Serial:            item.Product.Serial,
+			Name:              item.Product.Name,
+			Quantity:          item.Quantity,
+			Price:             item.Price,
+			SubTotal:          item.SubTotalPrice,
+			Tax:               item.TaxAmount,
+			AppliedPromotions: newAppliedPromotionsResponse(item.AppliedPromotions),
+			CouponDiscount:    item.CouponDiscount,
 		})
 	}
 
-	return c.JSON(http.StatusOK, result)
+	return &result
+}
+
+func newAppliedPromotionsResponse(p []*entity.AppliedPromotion) []*appliedPromotionResponse {
+	result := []*appliedPromotionResponse{}
+	for _, applied := range p {
+		result = append(result, &appliedPromotionResponse{
+			PromotionID:    applied.PromotionID,
+			Type:           int(applied.Type),
+			DiscountAmount: applied.DiscountAmount,
+			FreeQuantity:   applied.FreeQuantity,
+		})
+	}
+	return result
+}
 
+func newWarningsResponse(unknownSerials []string) []*warningResponse {
+	result := []*warningResponse{}
+	for _, serial := range unknownSerials {
+		result = append(result, &warningResponse{
+			Serial:  serial,
+			Message: entity.ProductNotFound,
+		})
+	}
+	return result
 }
@@ -0,0 +1,47 @@
+package handler_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gendutski/be-candidate-home-test/handler"
+	"github.com/go-playground/validator/v10"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+type testValidator struct {
+	validator *validator.Validate
+}
+
+func (v *testValidator) Validate(i interface{}) error {
+	return v.validator.Struct(i)
+}
+
+func newTestContext(e *echo.Echo, body string) echo.Context {
+	req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(body))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	return e.NewContext(req, httptest.NewRecorder())
+}
+
+func Test_CheckoutInvalidPayload(t *testing.T) {
+	e := echo.New()
+	e.Validator = &testValidator{validator: validator.New()}
+	// usecase must not be called by invalid payload
+	h := handler.NewCheckoutHandler(nil)
+
+	tests := []struct {
+		name string
+		body string
+	}{
+		{"null item", `{"items":[null]}`},
+		{"null item after valid item", `{"items":[{"serial":"120P90","quantity":1},null]}`},
+		{"item without serial", `{"items":[{"quantity":1}]}`},
+	}
+	for _, tt := range tests {
+		err := h.Quote(newTestContext(e, tt.body))
+		assert.IsType(t, validator.ValidationErrors{}, err, tt.name)
+	}
+}
@@ -0,0 +1,115 @@
+package handler
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/gendutski/be-candidate-home-test/core/entity"
+	"github.com/gendutski/be-candidate-home-test/core/module"
+	"github.com/labstack/echo/v4"
+)
+
+type InventoryHandler struct {
+	inventoryUC module.InventoryUsecase
+}
+
+func NewInventoryHandler(inventoryUC module.InventoryUsecase) *InventoryHandler {
+	return &InventoryHandler{inventoryUC}
+}
+
+type adjustPayload struct {
+	Serial string `param:"serial" json:"-" validate:"required"`
+	// positive to add items, negative to remove items
+	Delta int `json:"delta" validate:"required"`
+	// restock, return or correction
+	Reason    string `json:"reason" validate:"required"`
+	AdminUser string `json:"adminUser" validate:"required"`
+}
+
+type historyPayload struct {
+	Serial string `param:"serial" validate:"required"`
+	Page   int    `query:"page"`
+	Limit  int    `query:"limit"`
+}
+
+type stockMovementResponse struct {
+	ID        int64     `json:"id"`
+	Serial    string    `json:"serial"`
+	Delta     int       `json:"delta"`
+	Balance   int       `json:"balance"`
+	Reason    string    `json:"reason"`
+	Reference string    `json:"reference"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+type stockMovementListResponse struct {
+	Items []*stockMovementResponse `json:"items"`
+	Page  int                      `json:"page"`
+	Limit int                      `json:"limit"`
+	Total int64                    `json:"total"`
+}
+
+func (h *InventoryHandler) Adjust(c echo.Context) error {
+	p := new(adjustPayload)
+	// bind path param and json payload
+	if err := c.Bind(p); err != nil {
+		return err
+	}
+	// validate payload
+	if err := c.Validate(p); err != nil {
+		return err
+	}
+
+	movement := entity.StockMovement{
+		Delta:     p.Delta,
+		Reason:    entity.StockMovementReason(p.Reason),
+		Reference: p.AdminUser,
+	}
+	if err := h.inventoryUC.Adjust(p.Serial, &movement); err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusOK, newStockMovementResponse(p.Serial, &movement))
+}
+
+func (h *InventoryHandler) History(c echo.Context) error {
+	p := new(historyPayload)
+	// bind path and query param
+	if err := c.Bind(p); err != nil {
+		return err
+	}
+	// validate payload
+	if err := c.Validate(p); err != nil {
+		return err
+	}
+
+	filter := entity.StockMovementFilter{Page: p.Page, Limit: p.Limit}
+	movements, total, err := h.inventoryUC.History(p.Serial, &filter)
+	if err != nil {
+		return err
+	}
+
+	result := stockMovementListResponse{
+		Items: []*stockMovementResponse{},
+		Page:  filter.Page,
+		Limit: filter.Limit,
+		Total: total,
+	}
+	for _, movement := range movements {
+		result.Items = append(result.Items, newStockMovementResponse(p.Serial, movement))
+	}
+
+	return c.JSON(http.StatusOK, result)
+}
+
+func newStockMovementResponse(serial string, p *entity.StockMovement) *stockMovementResponse {
+	return &stockMovementResponse{
+		ID:        p.ID,
+		Serial:    serial,
+		Delta:     p.Delta,
+		Balance:   p.Balance,
+		Reason:    string(p.Reason),
+		Reference: p.Reference,
+		CreatedAt: p.CreatedAt,
+	}
+}
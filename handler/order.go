@@ -0,0 +1,122 @@
+package handler
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/gendutski/be-candidate-home-test/core/entity"
+	"github.com/gendutski/be-candidate-home-test/core/module"
+	"github.com/labstack/echo/v4"
+)
+
+const dateLayout string = "2006-01-02"
+
+type OrderHandler struct {
+	orderUC module.OrderUsecase
+}
+
+func NewOrderHandler(orderUC module.OrderUsecase) *OrderHandler {
+	return &OrderHandler{orderUC}
+}
+
+type orderDetailPayload struct {
+	ID int64 `param:"id" validate:"required"`
+}
+
+type orderListPayload struct {
+	// date in format YYYY-MM-DD
+	StartDate     string `query:"startDate"`
+	EndDate       string `query:"endDate"`
+	Serial        string `query:"serial"`
+	MinTotalPrice string `query:"minTotalPrice"`
+	MaxTotalPrice string `query:"maxTotalPrice"`
+	Page          int    `query:"page"`
+	Limit         int    `query:"limit"`
+}
+
+type orderListResponse struct {
+	Items []*response `json:"items"`
+	Page  int         `json:"page"`
+	Limit int         `json:"limit"`
+	Total int64       `json:"total"`
+}
+
+func (h *OrderHandler) Get(c echo.Context) error {
+	p := new(orderDetailPayload)
+	// bind path param
+	if err := c.Bind(p); err != nil {
+		return err
+	}
+	// validate payload
+	if err := c.Validate(p); err != nil {
+		return err
+	}
+
+	resp, err := h.orderUC.GetByID(p.ID)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusOK, newOrderResponse(resp))
+}
+
+func (h *OrderHandler) List(c echo.Context) error {
+	p := new(orderListPayload)
+	// bind query param
+	if err := c.Bind(p); err != nil {
+		return err
+	}
+
+	// map payload
+	filter := entity.OrderFilter{
+		ProductSerial: p.Serial,
+		Page:          p.Page,
+		Limit:         p.Limit,
+	}
+	if p.StartDate != "" {
+		startDate, err := time.ParseInLocation(dateLayout, p.StartDate, time.Local)
+		if err != nil {
+			return entity.NewError("invalid startDate", http.StatusBadRequest)
+		}
+		filter.CreatedFrom = startDate
+	}
+	if p.EndDate != "" {
+		endDate, err := time.ParseInLocation(dateLayout, p.EndDate, time.Local)
+		if err != nil {
+			return entity.NewError("invalid endDate", http.StatusBadRequest)
+		}
+		// end date is inclusive
+		filter.CreatedTo = endDate.AddDate(0, 0, 1)
+	}
+	if p.MinTotalPrice != "" {
+		minTotalPrice, err := entity.ParseMoney(p.MinTotalPrice)
+		if err != nil {
+			return entity.NewError("invalid minTotalPrice", http.StatusBadRequest)
+		}
+		filter.MinTotalPrice = &minTotalPrice
+	}
+	if p.MaxTotalPrice != "" {
+		maxTotalPrice, err := entity.ParseMoney(p.MaxTotalPrice)
+		if err != nil {
+			return entity.NewError("invalid maxTotalPrice", http.StatusBadRequest)
+		}
+		filter.MaxTotalPrice = &maxTotalPrice
+	}
+
+	orders, total, err := h.orderUC.List(&filter)
+	if err != nil {
+		return err
+	}
+
+	result := orderListResponse{
+		Items: []*response{},
+		Page:  filter.Page,
+		Limit: filter.Limit,
+		Total: total,
+	}
+	for _, order := range orders {
+		result.Items = append(result.Items, newOrderResponse(order))
+	}
+
+	return c.JSON(http.StatusOK, result)
+}
@@ -0,0 +1,186 @@
+package handler
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/gendutski/be-candidate-home-test/core/entity"
+	"github.com/gendutski/be-candidate-home-test/core/module"
+	"github.com/labstack/echo/v4"
+)
+
+type ProductHandler struct {
+	productUC module.ProductUsecase
+}
+
+func NewProductHandler(productUC module.ProductUsecase) *ProductHandler {
+	return &ProductHandler{productUC}
+}
+
+type productDetailPayload struct {
+	ID int64 `param:"id" validate:"required"`
+}
+
+type productPayload struct {
+	// only for update, taken from path param
+	ID          int64        `param:"id" json:"-"`
+	Serial      string       `json:"serial" validate:"required"`
+	Name        string       `json:"name" validate:"required"`
+	Price       entity.Money `json:"price"`
+	TaxCategory string       `json:"taxCategory"`
+	Category    string       `json:"category"`
+	// initial quantity, only for create
+	Quantity int `json:"quantity"`
+}
+
+type productListPayload struct {
+	Page  int `query:"page"`
+	Limit int `query:"limit"`
+}
+
+type productResponse struct {
+	ID          int64        `json:"id"`
+	Serial      string       `json:"serial"`
+	Name        string       `json:"name"`
+	Price       entity.Money `json:"price"`
+	TaxCategory string       `json:"taxCategory"`
+	Category    string       `json:"category"`
+	UpdatedAt   time.Time    `json:"updatedAt"`
+}
+
+type productListResponse struct {
+	Items []*productResponse `json:"items"`
+	Page  int                `json:"page"`
+	Limit int                `json:"limit"`
+	Total int64              `json:"total"`
+}
+
+func (h *ProductHandler) Get(c echo.Context) error {
+	id, err := h.bindID(c)
+	if err != nil {
+		return err
+	}
+
+	resp, err := h.productUC.GetByID(id)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusOK, newProductResponse(resp))
+}
+
+func (h *ProductHandler) List(c echo.Context) error {
+	p := new(productListPayload)
+	// bind query param
+	if err := c.Bind(p); err != nil {
+		return err
+	}
+
+	filter := entity.ProductFilter{Page: p.Page, Limit: p.Limit}
+	products, total, err := h.productUC.List(&filter)
+	if err != nil {
+		return err
+	}
+
+	result := productListResponse{
+		Items: []*productResponse{},
+		Page:  filter.Page,
+		Limit: filter.Limit,
+		Total: total,
+	}
+	for _, product := range products {
+		result.Items = append(result.Items, newProductResponse(product))
+	}
+
+	return c.JSON(http.StatusOK, result)
+}
+
+func (h *ProductHandler) Create(c echo.Context) error {
+	p, err := h.bindPayload(c)
+	if err != nil {
+		return err
+	}
+
+	product := p.toEntity()
+	if err := h.productUC.Create(product, p.Quantity); err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusCreated, newProductResponse(product))
+}
+
+func (h *ProductHandler) Update(c echo.Context) error {
+	p, err := h.bindPayload(c)
+	if err != nil {
+		return err
+	}
+
+	product := p.toEntity()
+	if err := h.productUC.Update(product); err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusOK, newProductResponse(product))
+}
+
+func (h *ProductHandler) Delete(c echo.Context) error {
+	id, err := h.bindID(c)
+	if err != nil {
+		return err
+	}
+
+	if err := h.productUC.Delete(id); err != nil {
+		return err
+	}
+
+	return c.NoContent(http.StatusNoContent)
+}
+
+func (h *ProductHandler) bindID(c echo.Context) (int64, error) {
+	p := new(productDetailPayload)
+	// bind path param
+	if err := c.Bind(p); err != nil {
+		return 0, err
+	}
+	// validate payload
+	if err := c.Validate(p); err != nil {
+		return 0, err
+	}
+	return p.ID, nil
+}
+
+func (h *ProductHandler) bindPayload(c echo.Context) (*productPayload, error) {
+	p := new(productPayload)
+	// bind path param and json payload
+	if err := c.Bind(p); err != nil {
+		return nil, err
+	}
+	// validate payload
+	if err := c.Validate(p); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+func (p *productPayload) toEntity() *entity.Product {
+	return &entity.Product{
+		ID:          p.ID,
+		Serial:      p.Serial,
+		Name:        p.Name,
+		Price:       p.Price,
+		TaxCategory: p.TaxCategory,
+		Category:    p.Category,
+	}
+}
+
+func newProductResponse(p *entity.Product) *productResponse {
+	return &productResponse{
+		ID:          p.ID,
+		Serial:      p.Serial,
+		Name:        p.Name,
+		Price:       p.Price,
+		TaxCategory: p.TaxCategory,
+		Category:    p.Category,
+		UpdatedAt:   p.UpdatedAt,
+	}
+}
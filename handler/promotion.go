@@ -0,0 +1,237 @@
+package handler
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/gendutski/be-candidate-home-test/core/entity"
+	"github.com/gendutski/be-candidate-home-test/core/module"
+	"github.com/labstack/echo/v4"
+)
+
+type PromotionHandler struct {
+	promotionUC module.PromotionUsecase
+}
+
+func NewPromotionHandler(promotionUC module.PromotionUsecase) *PromotionHandler {
+	return &PromotionHandler{promotionUC}
+}
+
+type promotionDetailPayload struct {
+	ID int64 `param:"id" validate:"required"`
+}
+
+type promotionPayload struct {
+	// only for update, taken from path param
+	ID   int64 `param:"id" json:"-"`
+	Type int   `json:"type" validate:"required"`
+	// empty for cart level promotion
+	ProductID      int64 `json:"productId"`
+	MatchQuantity  int   `json:"matchQuantity"`
+	PromoValue     int   `json:"promoValue"`
+	PromoProductID int64 `json:"promoProductId"`
+	// only for cart level promotion, promo price and currency are also for fixed amount promotion
+	MinTotal   entity.Money `json:"minTotal"`
+	PromoPrice entity.Money `json:"promoPrice"`
+	Category   string       `json:"category"`
+	Currency   string       `json:"currency"`
+	// only for product bundle
+	Items []*promotionItemPayload `json:"items" validate:"dive,required"`
+	// only for tiered price, currency is required for tier with unit price
+	Tiers []*promotionTierPayload `json:"tiers" validate:"dive,required"`
+	// only for product promotion
+	Priority      int        `json:"priority"`
+	Exclusive     bool       `json:"exclusive"`
+	StackableWith []int64    `json:"stackableWith"`
+	StartsAt      *time.Time `json:"startsAt"`
+	EndsAt        *time.Time `json:"endsAt"`
+}
+
+type promotionItemPayload struct {
+	ProductID int64 `json:"productId"`
+	Quantity  int   `json:"quantity"`
+}
+
+type promotionTierPayload struct {
+	MinQuantity int          `json:"minQuantity"`
+	Percent     int          `json:"percent"`
+	UnitPrice   entity.Money `json:"unitPrice"`
+}
+
+type promotionResponse struct {
+	ID             int64                   `json:"id"`
+	Type           int                     `json:"type"`
+	ProductID      int64                   `json:"productId"`
+	MatchQuantity  int                     `json:"matchQuantity"`
+	PromoValue     int                     `json:"promoValue"`
+	PromoProductID int64                   `json:"promoProductId"`
+	MinTotal       entity.Money            `json:"minTotal"`
+	PromoPrice     entity.Money            `json:"promoPrice"`
+	Category       string                  `json:"category"`
+	Currency       string                  `json:"currency"`
+	Items          []*promotionItemPayload `json:"items"`
+	Tiers          []*promotionTierPayload `json:"tiers"`
+	Priority       int                     `json:"priority"`
+	Exclusive      bool                    `json:"exclusive"`
+	StackableWith  []int64                 `json:"stackableWith"`
+	StartsAt       *time.Time              `json:"startsAt"`
+	EndsAt         *time.Time              `json:"endsAt"`
+	UpdatedAt      time.Time               `json:"updatedAt"`
+}
+
+type promotionListResponse struct {
+	Items []*promotionResponse `json:"items"`
+}
+
+func (h *PromotionHandler) Get(c echo.Context) error {
+	id, err := h.bindID(c)
+	if err != nil {
+		return err
+	}
+
+	resp, err := h.promotionUC.GetByID(id)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusOK, newPromotionResponse(resp))
+}
+
+func (h *PromotionHandler) List(c echo.Context) error {
+	promotions, err := h.promotionUC.List()
+	if err != nil {
+		return err
+	}
+
+	result := promotionListResponse{Items: []*promotionResponse{}}
+	for _, promo := range promotions {
+		result.Items = append(result.Items, newPromotionResponse(promo))
+	}
+
+	return c.JSON(http.StatusOK, result)
+}
+
+func (h *PromotionHandler) Create(c echo.Context) error {
+	promo, err := h.bindPayload(c)
+	if err != nil {
+		return err
+	}
+
+	if err := h.promotionUC.Create(promo); err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusCreated, newPromotionResponse(promo))
+}
+
+func (h *PromotionHandler) Update(c echo.Context) error {
+	promo, err := h.bindPayload(c)
+	if err != nil {
+		return err
+	}
+
+	if err := h.promotionUC.Update(promo); err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusOK, newPromotionResponse(promo))
+}
+
+func (h *PromotionHandler) Delete(c echo.Context) error {
+	id, err := h.bindID(c)
+	if err != nil {
+		return err
+	}
+
+	if err := h.promotionUC.Delete(id); err != nil {
+		return err
+	}
+
+	return c.NoContent(http.StatusNoContent)
+}
+
+func (h *PromotionHandler) bindID(c echo.Context) (int64, error) {
+	p := new(promotionDetailPayload)
+	// bind path param
+	if err := c.Bind(p); err != nil {
+		return 0, err
+	}
+	// validate payload
+	if err := c.Validate(p); err != nil {
+		return 0, err
+	}
+	return p.ID, nil
+}
+
+func (h *PromotionHandler) bindPayload(c echo.Context) (*entity.Promotion, error) {
+	p := new(promotionPayload)
+	// bind path param and json payload
+	if err := c.Bind(p); err != nil {
+		return nil, err
+	}
+	// validate payload
+	if err := c.Validate(p); err != nil {
+		return nil, err
+	}
+
+	var items []*entity.PromotionItem
+	for _, item := range p.Items {
+		items = append(items, &entity.PromotionItem{ProductID: item.ProductID, Quantity: item.Quantity})
+	}
+	var tiers []*entity.PromotionTier
+	for _, tier := range p.Tiers {
+		tiers = append(tiers, &entity.PromotionTier{MinQuantity: tier.MinQuantity, Percent: tier.Percent, UnitPrice: tier.UnitPrice})
+	}
+
+	return &entity.Promotion{
+		ID:             p.ID,
+		Type:           entity.PromotionType(p.Type),
+		ProductID:      p.ProductID,
+		MatchQuantity:  p.MatchQuantity,
+		PromoValue:     p.PromoValue,
+		PromoProductID: p.PromoProductID,
+		MinTotal:       p.MinTotal,
+		PromoPrice:     p.PromoPrice,
+		Category:       p.Category,
+		Currency:       p.Currency,
+		Items:          items,
+		Tiers:          tiers,
+		Priority:       p.Priority,
+		Exclusive:      p.Exclusive,
+		StackableWith:  p.StackableWith,
+		StartsAt:       p.StartsAt,
+		EndsAt:         p.EndsAt,
+	}, nil
+}
+
+func newPromotionResponse(p *entity.Promotion) *promotionResponse {
+	items := []*promotionItemPayload{}
+	for _, item := range p.Items {
+		items = append(items, &promotionItemPayload{ProductID: item.ProductID, Quantity: item.Quantity})
+	}
+	tiers := []*promotionTierPayload{}
+	for _, tier := range p.Tiers {
+		tiers = append(tiers, &promotionTierPayload{MinQuantity: tier.MinQuantity, Percent: tier.Percent, UnitPrice: tier.UnitPrice})
+	}
+
+	return &promotionResponse{
+		ID:             p.ID,
+		Type:           int(p.Type),
+		ProductID:      p.ProductID,
+		MatchQuantity:  p.MatchQuantity,
+		PromoValue:     p.PromoValue,
+		PromoProductID: p.PromoProductID,
+		MinTotal:       p.MinTotal,
+		PromoPrice:     p.PromoPrice,
+		Category:       p.Category,
+		Currency:       p.Currency,
+		Items:          items,
+		Tiers:          tiers,
+		Priority:       p.Priority,
+		Exclusive:      p.Exclusive,
+		StackableWith:  p.StackableWith,
+		StartsAt:       p.StartsAt,
+		EndsAt:         p.EndsAt,
+		UpdatedAt:      p.UpdatedAt,
+	}
+}
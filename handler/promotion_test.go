@@ -0,0 +1,31 @@
+package handler_test
+
+import (
+	"testing"
+
+	"github.com/gendutski/be-candidate-home-test/handler"
+	"github.com/go-playground/validator/v10"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_PromotionInvalidPayload(t *testing.T) {
+	e := echo.New()
+	e.Validator = &testValidator{validator: validator.New()}
+	// usecase must not be called by invalid payload
+	h := handler.NewPromotionHandler(nil)
+
+	tests := []struct {
+		name string
+		body string
+	}{
+		{"null bundle item", `{"type":8,"promoPrice":140,"currency":"USD","items":[null]}`},
+		{"null bundle item after valid item", `{"type":8,"promoPrice":140,"currency":"USD","items":[{"productId":1,"quantity":1},null]}`},
+		{"null tier", `{"type":9,"productId":1,"tiers":[null]}`},
+		{"null tier after valid tier", `{"type":9,"productId":1,"tiers":[{"minQuantity":10,"percent":5},null]}`},
+	}
+	for _, tt := range tests {
+		err := h.Create(newTestContext(e, tt.body))
+		assert.IsType(t, validator.ValidationErrors{}, err, tt.name)
+	}
+}
@@ -1,6 +1,7 @@
 package main
 
 import (
+	"context"
 	"flag"
 	"fmt"
 	"log"
@@ -10,8 +11,11 @@ import (
 	"github.com/gendutski/be-candidate-home-test/core/entity"
 	"github.com/gendutski/be-candidate-home-test/core/module"
 	"github.com/gendutski/be-candidate-home-test/handler"
+	cartrepository "github.com/gendutski/be-candidate-home-test/repository/cart-repository"
+	orderrepository "github.com/gendutski/be-candidate-home-test/repository/order-repository"
 	productrepository "github.com/gendutski/be-candidate-home-test/repository/product-repository"
 	promotionrepository "github.com/gendutski/be-candidate-home-test/repository/promotion-repository"
+	taxrepository "github.com/gendutski/be-candidate-home-test/repository/tax-repository"
 	"github.com/go-playground/validator/v10"
 	"github.com/joho/godotenv"
 	"github.com/labstack/echo/v4"
@@ -45,12 +49,32 @@ func main() {
 	// load repository
 	productRepo := productrepository.New(db)
 	promoRepo := promotionrepository.New(db)
+	orderRepo := orderrepository.New(db)
+	taxRepo := taxrepository.New(db)
+	cartRepo := cartrepository.New(db)
 
 	// load usecase
-	checkoutUC := module.NewCheckoutUsecase(productRepo, promoRepo)
+	clock := module.NewSystemClock()
+	taxCalc := module.NewTaxCalculator(taxRepo, entity.TaxMode(cfg.TaxMode))
+	checkoutUC := module.NewCheckoutUsecase(productRepo, promoRepo, taxCalc, clock, cfg.DefaultCurrency, cfg.ReservationTTL,
+		entity.PromotionMode(cfg.PromotionMode), cfg.PromotionSearchBound)
+	orderUC := module.NewOrderUsecase(orderRepo)
+	promotionUC := module.NewPromotionUsecase(promoRepo, productRepo)
+	productUC := module.NewProductUsecase(productRepo)
+	inventoryUC := module.NewInventoryUsecase(productRepo)
+	cartUC := module.NewCartUsecase(cartRepo, productRepo, checkoutUC, cfg.DefaultCurrency)
+
+	// release expired reservations in background
+	sweeper := module.NewReservationSweeper(productRepo, clock, cfg.ReservationSweepInterval)
+	go sweeper.Run(context.Background())
 
 	// load handler
 	checkoutHandler := handler.NewCheckoutHandler(checkoutUC)
+	orderHandler := handler.NewOrderHandler(orderUC)
+	promotionHandler := handler.NewPromotionHandler(promotionUC)
+	productHandler := handler.NewProductHandler(productUC)
+	inventoryHandler := handler.NewInventoryHandler(inventoryUC)
+	cartHandler := handler.NewCartHandler(cartUC)
 
 	// load echo framework
 	e := echo.New()
@@ -61,30 +85,77 @@ func main() {
 
 	// route
 	e.POST("/checkout", checkoutHandler.Submit)
+	e.POST("/checkout/quote", checkoutHandler.Quote)
+	e.POST("/checkout/reserve", checkoutHandler.Reserve)
+	e.GET("/orders", orderHandler.List)
+	e.GET("/orders/:id", orderHandler.Get)
+	e.POST("/carts", cartHandler.Create)
+	e.GET("/carts/:id", cartHandler.Get)
+	e.PUT("/carts/:id/items/:serial", cartHandler.SetItem)
+	e.DELETE("/carts/:id/items/:serial", cartHandler.RemoveItem)
+	e.POST("/carts/:id/checkout", cartHandler.Checkout)
+
+	// admin route
+	admin := e.Group("/admin")
+	admin.GET("/promotions", promotionHandler.List)
+	admin.GET("/promotions/:id", promotionHandler.Get)
+	admin.POST("/promotions", promotionHandler.Create)
+	admin.PUT("/promotions/:id", promotionHandler.Update)
+	admin.DELETE("/promotions/:id", promotionHandler.Delete)
+	admin.GET("/products", productHandler.List)
+	admin.GET("/products/:id", productHandler.Get)
+	admin.POST("/products", productHandler.Create)
+	admin.PUT("/products/:id", productHandler.Update)
+	admin.DELETE("/products/:id", productHandler.Delete)
+	admin.POST("/inventory/:serial/adjust", inventoryHandler.Adjust)
+	admin.GET("/inventory/:serial/history", inventoryHandler.History)
 
 	// run
 	e.Logger.Fatal(e.Start(":" + cfg.HttpPort))
 }
 
+// error response envelope of all errors
+type errorResponse struct {
+	Error errorBody `json:"error"`
+}
+
+type errorBody struct {
+	Code    entity.ErrorCode       `json:"code"`
+	Message string                 `json:"message"`
+	Details map[string]interface{} `json:"details,omitempty"`
+}
+
 func errorHandler(err error, c echo.Context) {
-	report, ok := err.(*echo.HTTPError)
-	if !ok {
-		report = echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+	if c.Response().Committed {
+		return
 	}
 
-	if castedObject, ok := err.(validator.ValidationErrors); ok {
-		for _, err := range castedObject {
-			switch err.Tag() {
-			case "required":
-				report.Message = fmt.Sprintf("%s is required",
-					err.Field())
-			}
+	status := http.StatusInternalServerError
+	body := errorBody{Code: entity.CodeInternalError, Message: err.Error()}
+
+	switch e := err.(type) {
+	case entity.Err:
+		status = e.GetCode()
+		body = errorBody{Code: e.GetErrorCode(), Message: e.GetMessage(), Details: e.GetDetails()}
+	case validator.ValidationErrors:
+		// map field name with failed validation tag
+		status = http.StatusBadRequest
+		fields := make(map[string]interface{})
+		for _, fieldErr := range e {
+			fields[fieldErr.Field()] = fieldErr.Tag()
 		}
+		body = errorBody{Code: entity.CodeValidationFailed, Details: map[string]interface{}{"fields": fields}}
+		switch e[0].Tag() {
+		case "required":
+			body.Message = fmt.Sprintf("%s is required", e[0].Field())
+		default:
+			body.Message = fmt.Sprintf("%s is invalid", e[0].Field())
+		}
+	case *echo.HTTPError:
+		// eg: route not found or malformed json payload
+		status = e.Code
+		body = errorBody{Code: entity.ErrorCodeFromStatus(e.Code), Message: fmt.Sprint(e.Message)}
 	}
 
-	if entityError, ok := err.(entity.Err); ok {
-		report.Code = entityError.GetCode()
-	}
-
-	c.JSON(report.Code, report)
+	c.JSON(status, errorResponse{Error: body})
 }
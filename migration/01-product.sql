@@ -2,8 +2,11 @@ CREATE TABLE `product` (
   `id` bigint UNSIGNED NOT NULL AUTO_INCREMENT,
   `serial` varchar(20) COLLATE utf8mb4_unicode_ci NOT NULL,
   `name` varchar(255) COLLATE utf8mb4_unicode_ci NOT NULL,
-  `price` double(10,2) NOT NULL DEFAULT 0,
+  `price` decimal(10,2) NOT NULL DEFAULT 0,
+  `tax_category` varchar(20) COLLATE utf8mb4_unicode_ci NOT NULL DEFAULT 'standard',
+  `category` varchar(20) COLLATE utf8mb4_unicode_ci NOT NULL DEFAULT '',
   `updated_at` timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  `deleted_at` timestamp NULL DEFAULT NULL,
 
   PRIMARY KEY (`id`),
   UNIQUE KEY `product_UNQ1` (`serial`)
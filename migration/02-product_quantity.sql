@@ -2,6 +2,7 @@ CREATE TABLE `product_quantity` (
   `id` bigint UNSIGNED NOT NULL AUTO_INCREMENT,
   `product_id` bigint UNSIGNED NOT NULL,
   `quantity` int UNSIGNED NOT NULL DEFAULT 0,
+  `reserved` int UNSIGNED NOT NULL DEFAULT 0,
   `updated_at` timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP,
 
   PRIMARY KEY (`id`),
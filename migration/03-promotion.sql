@@ -1,14 +1,23 @@
 CREATE TABLE `promotion` (
   `id` bigint UNSIGNED NOT NULL AUTO_INCREMENT,
   `type` int UNSIGNED NOT NULL,
-  `product_id` bigint UNSIGNED NOT NULL,
+  `product_id` bigint UNSIGNED NOT NULL DEFAULT 0,
   `match_quantity` int UNSIGNED NOT NULL DEFAULT 0,
   `promo_value` int UNSIGNED NOT NULL DEFAULT 0,
   `promo_product_id` bigint UNSIGNED NOT NULL DEFAULT 0,
+  `min_total` decimal(10,2) NOT NULL DEFAULT 0,
+  `promo_price` decimal(10,2) NOT NULL DEFAULT 0,
+  `category` varchar(20) COLLATE utf8mb4_unicode_ci NOT NULL DEFAULT '',
+  `currency` char(3) NOT NULL DEFAULT '',
+  `priority` int NOT NULL DEFAULT 0,
+  `exclusive` tinyint(1) NOT NULL DEFAULT 0,
+  `stackable_with` varchar(255) NOT NULL DEFAULT '[]',
+  `starts_at` timestamp NULL DEFAULT NULL,
+  `ends_at` timestamp NULL DEFAULT NULL,
   `updated_at` timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP,
   `deleted_at` timestamp NULL DEFAULT NULL,
 
   PRIMARY KEY (`id`),
-  FOREIGN KEY `promotion_FK1` (`product_id`) REFERENCES `product` (`id`),
-  KEY `promotion_IDX1` (`promo_product_id`)
+  KEY `promotion_IDX1` (`promo_product_id`),
+  KEY `promotion_IDX2` (`product_id`)
 );
\ No newline at end of file
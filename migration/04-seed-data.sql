@@ -1,15 +1,41 @@
 -- truncate all table
 SET FOREIGN_KEY_CHECKS = 0;
+TRUNCATE TABLE `order_item`;
+TRUNCATE TABLE `order`;
+TRUNCATE TABLE `promotion_tier`;
+TRUNCATE TABLE `promotion_item`;
 TRUNCATE TABLE `promotion`;
+TRUNCATE TABLE `product_price`;
+TRUNCATE TABLE `tax_rate`;
+TRUNCATE TABLE `stock_movement`;
+TRUNCATE TABLE `reservation_item`;
+TRUNCATE TABLE `reservation`;
+TRUNCATE TABLE `coupon_redemption`;
+TRUNCATE TABLE `coupon`;
+TRUNCATE TABLE `cart_item`;
+TRUNCATE TABLE `cart`;
 TRUNCATE TABLE `product_quantity`;
 TRUNCATE TABLE `product`;
 
 -- seed sample product
-INSERT INTO `product` (`serial`, `name`, `price`) VALUES
-('120P90', 'Google Home', 49.99),
-('43N23P', 'MacBook Pro', 5399.99),
-('A304SD', 'Alexa Speaker', 109.50),
-('234234', 'Raspberry Pi B', 30.00);
+INSERT INTO `product` (`serial`, `name`, `price`, `category`) VALUES
+('120P90', 'Google Home', 49.99, 'smart-home'),
+('43N23P', 'MacBook Pro', 5399.99, 'computer'),
+('A304SD', 'Alexa Speaker', 109.50, 'smart-home'),
+('234234', 'Raspberry Pi B', 30.00, 'computer');
+
+-- seed sample product_price, product.price is in USD
+INSERT INTO `product_price` (`product_id`, `currency`, `price`) VALUES
+(1, 'EUR', 45.50),
+(2, 'EUR', 4999.99),
+(3, 'EUR', 99.00),
+(4, 'EUR', 27.50);
+
+-- seed tax rate in basis points, all sample products use standard category
+INSERT INTO `tax_rate` (`category`, `rate`) VALUES
+('standard', 1100),
+('reduced', 500),
+('exempt', 0);
 
 -- seed sample product_quantity
 INSERT INTO `product_quantity` (`product_id`, `quantity`) VALUES
@@ -18,10 +44,29 @@ INSERT INTO `product_quantity` (`product_id`, `quantity`) VALUES
 (3, 10),
 (4, 2);
 
+-- seed initial stock movement of product_quantity
+INSERT INTO `stock_movement` (`product_id`, `delta`, `balance`, `reason`) VALUES
+(1, 10, 10, 'initial'),
+(2, 5, 5, 'initial'),
+(3, 10, 10, 'initial'),
+(4, 2, 2, 'initial');
+
 -- seed promotion
 INSERT INTO `promotion` (`type`, `product_id`, `match_quantity`, `promo_value`, `promo_product_id`) VALUES
 (1, 2, 1, 1, 4),
 (2, 1, 3, 2, 0),
 (3, 3, 3, 10, 0);
 
+-- seed product bundle, Google Home and Alexa Speaker for $140
+INSERT INTO `promotion` (`type`, `promo_price`, `currency`) VALUES
+(8, 140.00, 'USD');
+INSERT INTO `promotion_item` (`promotion_id`, `product_id`, `quantity`) VALUES
+(4, 1, 1),
+(4, 3, 1);
+
+-- seed coupon, type 1 is fixed amount and type 2 is percent
+INSERT INTO `coupon` (`code`, `type`, `amount`, `percent`, `min_total`, `currency`, `usage_limit`, `per_customer_limit`) VALUES
+('WELCOME10', 2, 0, 10, 100.00, 'USD', 100, 1),
+('SAVE20', 1, 20.00, 0, 150.00, 'USD', 0, 0);
+
 SET FOREIGN_KEY_CHECKS = 1;
@@ -0,0 +1,18 @@
+CREATE TABLE `order` (
+  `id` bigint UNSIGNED NOT NULL AUTO_INCREMENT,
+  `total_item` int UNSIGNED NOT NULL DEFAULT 0,
+  `total_price` decimal(10,2) NOT NULL DEFAULT 0,
+  `total_discount` decimal(10,2) NOT NULL DEFAULT 0,
+  `tax_total` decimal(10,2) NOT NULL DEFAULT 0,
+  `grand_total` decimal(10,2) NOT NULL DEFAULT 0,
+  `currency` char(3) NOT NULL DEFAULT 'USD',
+  `adjustments` text COLLATE utf8mb4_unicode_ci NOT NULL,
+  `customer_id` varchar(64) NOT NULL DEFAULT '',
+  `coupon_id` bigint UNSIGNED NOT NULL DEFAULT 0,
+  `coupon_code` varchar(32) NOT NULL DEFAULT '',
+  `coupon_discount` decimal(10,2) NOT NULL DEFAULT 0,
+  `created_at` timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP,
+
+  PRIMARY KEY (`id`),
+  KEY `order_IDX1` (`created_at`)
+);
\ No newline at end of file
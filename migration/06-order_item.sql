@@ -0,0 +1,15 @@
+CREATE TABLE `order_item` (
+  `id` bigint UNSIGNED NOT NULL AUTO_INCREMENT,
+  `order_id` bigint UNSIGNED NOT NULL,
+  `product_id` bigint UNSIGNED NOT NULL,
+  `quantity` int UNSIGNED NOT NULL DEFAULT 0,
+  `price` decimal(10,2) NOT NULL DEFAULT 0,
+  `sub_total_price` decimal(10,2) NOT NULL DEFAULT 0,
+  `tax_amount` decimal(10,2) NOT NULL DEFAULT 0,
+  `applied_promotions` text COLLATE utf8mb4_unicode_ci NOT NULL,
+  `coupon_discount` decimal(10,2) NOT NULL DEFAULT 0,
+
+  PRIMARY KEY (`id`),
+  FOREIGN KEY `order_item_FK1` (`order_id`) REFERENCES `order` (`id`),
+  FOREIGN KEY `order_item_FK2` (`product_id`) REFERENCES `product` (`id`)
+);
\ No newline at end of file
@@ -0,0 +1,11 @@
+CREATE TABLE `product_price` (
+  `id` bigint UNSIGNED NOT NULL AUTO_INCREMENT,
+  `product_id` bigint UNSIGNED NOT NULL,
+  `currency` char(3) NOT NULL,
+  `price` decimal(10,2) NOT NULL DEFAULT 0,
+  `updated_at` timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP,
+
+  PRIMARY KEY (`id`),
+  UNIQUE KEY `product_price_UN1` (`product_id`, `currency`),
+  FOREIGN KEY `product_price_FK1` (`product_id`) REFERENCES `product` (`id`)
+);
\ No newline at end of file
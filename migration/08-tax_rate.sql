@@ -0,0 +1,9 @@
+CREATE TABLE `tax_rate` (
+  `id` bigint UNSIGNED NOT NULL AUTO_INCREMENT,
+  `category` varchar(20) COLLATE utf8mb4_unicode_ci NOT NULL,
+  `rate` int UNSIGNED NOT NULL DEFAULT 0,
+  `updated_at` timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP,
+
+  PRIMARY KEY (`id`),
+  UNIQUE KEY `tax_rate_UNQ1` (`category`)
+);
\ No newline at end of file
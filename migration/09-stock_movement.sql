@@ -0,0 +1,12 @@
+CREATE TABLE `stock_movement` (
+  `id` bigint UNSIGNED NOT NULL AUTO_INCREMENT,
+  `product_id` bigint UNSIGNED NOT NULL,
+  `delta` int NOT NULL,
+  `balance` int UNSIGNED NOT NULL DEFAULT 0,
+  `reason` varchar(20) COLLATE utf8mb4_unicode_ci NOT NULL,
+  `reference` varchar(255) COLLATE utf8mb4_unicode_ci NOT NULL DEFAULT '',
+  `created_at` timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP,
+
+  PRIMARY KEY (`id`),
+  FOREIGN KEY `stock_movement_FK1` (`product_id`) REFERENCES `product` (`id`)
+);
\ No newline at end of file
@@ -0,0 +1,9 @@
+CREATE TABLE `reservation` (
+  `id` bigint UNSIGNED NOT NULL AUTO_INCREMENT,
+  `status` varchar(20) COLLATE utf8mb4_unicode_ci NOT NULL,
+  `expires_at` timestamp NOT NULL,
+  `created_at` timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP,
+
+  PRIMARY KEY (`id`),
+  KEY `reservation_IDX1` (`status`, `expires_at`)
+);
\ No newline at end of file
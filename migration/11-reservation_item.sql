@@ -0,0 +1,10 @@
+CREATE TABLE `reservation_item` (
+  `id` bigint UNSIGNED NOT NULL AUTO_INCREMENT,
+  `reservation_id` bigint UNSIGNED NOT NULL,
+  `product_id` bigint UNSIGNED NOT NULL,
+  `quantity` int UNSIGNED NOT NULL DEFAULT 0,
+
+  PRIMARY KEY (`id`),
+  FOREIGN KEY `reservation_item_FK1` (`reservation_id`) REFERENCES `reservation` (`id`),
+  FOREIGN KEY `reservation_item_FK2` (`product_id`) REFERENCES `product` (`id`)
+);
\ No newline at end of file
@@ -0,0 +1,9 @@
+CREATE TABLE `cart` (
+  `id` bigint UNSIGNED NOT NULL AUTO_INCREMENT,
+  `currency` char(3) NOT NULL,
+  `order_id` bigint UNSIGNED NOT NULL DEFAULT 0,
+  `created_at` timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  `updated_at` timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP,
+
+  PRIMARY KEY (`id`)
+);
\ No newline at end of file
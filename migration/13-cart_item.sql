@@ -0,0 +1,12 @@
+CREATE TABLE `cart_item` (
+  `id` bigint UNSIGNED NOT NULL AUTO_INCREMENT,
+  `cart_id` bigint UNSIGNED NOT NULL,
+  `product_id` bigint UNSIGNED NOT NULL,
+  `quantity` int UNSIGNED NOT NULL DEFAULT 0,
+  `updated_at` timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP,
+
+  PRIMARY KEY (`id`),
+  UNIQUE KEY `cart_item_UNQ1` (`cart_id`, `product_id`),
+  FOREIGN KEY `cart_item_FK1` (`cart_id`) REFERENCES `cart` (`id`),
+  FOREIGN KEY `cart_item_FK2` (`product_id`) REFERENCES `product` (`id`)
+);
\ No newline at end of file
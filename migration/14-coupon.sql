@@ -0,0 +1,19 @@
+CREATE TABLE `coupon` (
+  `id` bigint UNSIGNED NOT NULL AUTO_INCREMENT,
+  `code` varchar(32) NOT NULL,
+  `type` int UNSIGNED NOT NULL,
+  `amount` decimal(10,2) NOT NULL DEFAULT 0,
+  `percent` int UNSIGNED NOT NULL DEFAULT 0,
+  `min_total` decimal(10,2) NOT NULL DEFAULT 0,
+  `currency` char(3) NOT NULL DEFAULT 'USD',
+  `usage_limit` int UNSIGNED NOT NULL DEFAULT 0,
+  `per_customer_limit` int UNSIGNED NOT NULL DEFAULT 0,
+  `used_count` int UNSIGNED NOT NULL DEFAULT 0,
+  `starts_at` timestamp NULL DEFAULT NULL,
+  `ends_at` timestamp NULL DEFAULT NULL,
+  `updated_at` timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  `deleted_at` timestamp NULL DEFAULT NULL,
+
+  PRIMARY KEY (`id`),
+  UNIQUE KEY `coupon_UNQ1` (`code`)
+);
\ No newline at end of file
@@ -0,0 +1,12 @@
+CREATE TABLE `coupon_redemption` (
+  `id` bigint UNSIGNED NOT NULL AUTO_INCREMENT,
+  `coupon_id` bigint UNSIGNED NOT NULL,
+  `order_id` bigint UNSIGNED NOT NULL,
+  `customer_id` varchar(64) NOT NULL DEFAULT '',
+  `created_at` timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP,
+
+  PRIMARY KEY (`id`),
+  FOREIGN KEY `coupon_redemption_FK1` (`coupon_id`) REFERENCES `coupon` (`id`),
+  FOREIGN KEY `coupon_redemption_FK2` (`order_id`) REFERENCES `order` (`id`),
+  KEY `coupon_redemption_IDX1` (`coupon_id`, `customer_id`)
+);
\ No newline at end of file
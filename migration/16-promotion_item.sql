@@ -0,0 +1,11 @@
+CREATE TABLE `promotion_item` (
+  `id` bigint UNSIGNED NOT NULL AUTO_INCREMENT,
+  `promotion_id` bigint UNSIGNED NOT NULL,
+  `product_id` bigint UNSIGNED NOT NULL,
+  `quantity` int UNSIGNED NOT NULL DEFAULT 1,
+
+  PRIMARY KEY (`id`),
+  UNIQUE KEY `promotion_item_UNQ1` (`promotion_id`, `product_id`),
+  FOREIGN KEY `promotion_item_FK1` (`promotion_id`) REFERENCES `promotion` (`id`),
+  FOREIGN KEY `promotion_item_FK2` (`product_id`) REFERENCES `product` (`id`)
+);
\ No newline at end of file
@@ -0,0 +1,11 @@
+CREATE TABLE `promotion_tier` (
+  `id` bigint UNSIGNED NOT NULL AUTO_INCREMENT,
+  `promotion_id` bigint UNSIGNED NOT NULL,
+  `min_quantity` int UNSIGNED NOT NULL DEFAULT 1,
+  `percent` int UNSIGNED NOT NULL DEFAULT 0,
+  `unit_price` decimal(10,2) NOT NULL DEFAULT 0,
+
+  PRIMARY KEY (`id`),
+  UNIQUE KEY `promotion_tier_UNQ1` (`promotion_id`, `min_quantity`),
+  FOREIGN KEY `promotion_tier_FK1` (`promotion_id`) REFERENCES `promotion` (`id`)
+);
\ No newline at end of file
@@ -0,0 +1,3 @@
+-- total discount of promotions
+ALTER TABLE `order`
+  ADD COLUMN `total_discount` double(10,2) NOT NULL DEFAULT 0 AFTER `total_price`;
\ No newline at end of file
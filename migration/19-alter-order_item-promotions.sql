@@ -0,0 +1,12 @@
+-- order item stores applied promotions with their discount instead of promotion ids
+ALTER TABLE `order_item`
+  ADD COLUMN `applied_promotions` text COLLATE utf8mb4_unicode_ci NOT NULL AFTER `sub_total_price`;
+
+-- keep promotion id of existing orders, their discount is not recorded
+UPDATE `order_item` SET `applied_promotions` = COALESCE((
+  SELECT JSON_ARRAYAGG(JSON_OBJECT('promotionId', `promo`.`id`))
+  FROM JSON_TABLE(`order_item`.`promotion_ids`, '$[*]' COLUMNS (`id` bigint PATH '$')) AS `promo`
+), '[]');
+
+ALTER TABLE `order_item`
+  DROP COLUMN `promotion_ids`;
\ No newline at end of file
@@ -0,0 +1,3 @@
+-- price is stored as exact decimal instead of double
+ALTER TABLE `product`
+  MODIFY COLUMN `price` decimal(10,2) NOT NULL DEFAULT 0;
\ No newline at end of file
@@ -0,0 +1,4 @@
+-- prices are stored as exact decimal instead of double
+ALTER TABLE `order_item`
+  MODIFY COLUMN `price` decimal(10,2) NOT NULL DEFAULT 0,
+  MODIFY COLUMN `sub_total_price` decimal(10,2) NOT NULL DEFAULT 0;
\ No newline at end of file
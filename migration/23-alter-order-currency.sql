@@ -0,0 +1,3 @@
+-- existing orders are in default currency
+ALTER TABLE `order`
+  ADD COLUMN `currency` char(3) NOT NULL DEFAULT 'USD' AFTER `total_discount`;
\ No newline at end of file
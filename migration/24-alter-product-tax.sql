@@ -0,0 +1,3 @@
+-- tax category of product, taxed by the standard rate when not set
+ALTER TABLE `product`
+  ADD COLUMN `tax_category` varchar(20) COLLATE utf8mb4_unicode_ci NOT NULL DEFAULT 'standard' AFTER `price`;
\ No newline at end of file
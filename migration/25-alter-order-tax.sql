@@ -0,0 +1,6 @@
+-- existing orders have no tax, so grand total is total price
+ALTER TABLE `order`
+  ADD COLUMN `tax_total` decimal(10,2) NOT NULL DEFAULT 0 AFTER `total_discount`,
+  ADD COLUMN `grand_total` decimal(10,2) NOT NULL DEFAULT 0 AFTER `tax_total`;
+
+UPDATE `order` SET `grand_total` = `total_price`;
\ No newline at end of file
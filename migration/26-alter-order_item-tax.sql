@@ -0,0 +1,3 @@
+-- tax of sub total price
+ALTER TABLE `order_item`
+  ADD COLUMN `tax_amount` decimal(10,2) NOT NULL DEFAULT 0 AFTER `sub_total_price`;
\ No newline at end of file
@@ -0,0 +1,4 @@
+-- existing promotions have no validity window
+ALTER TABLE `promotion`
+  ADD COLUMN `starts_at` timestamp NULL DEFAULT NULL AFTER `promo_product_id`,
+  ADD COLUMN `ends_at` timestamp NULL DEFAULT NULL AFTER `starts_at`;
\ No newline at end of file
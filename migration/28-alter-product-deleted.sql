@@ -0,0 +1,3 @@
+-- product is soft deleted by admin catalog API
+ALTER TABLE `product`
+  ADD COLUMN `deleted_at` timestamp NULL DEFAULT NULL AFTER `updated_at`;
\ No newline at end of file
@@ -0,0 +1,3 @@
+-- quantity held by active reservations
+ALTER TABLE `product_quantity`
+  ADD COLUMN `reserved` int UNSIGNED NOT NULL DEFAULT 0 AFTER `quantity`;
\ No newline at end of file
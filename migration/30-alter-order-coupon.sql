@@ -0,0 +1,6 @@
+-- coupon redeemed by order
+ALTER TABLE `order`
+  ADD COLUMN `customer_id` varchar(64) NOT NULL DEFAULT '' AFTER `currency`,
+  ADD COLUMN `coupon_id` bigint UNSIGNED NOT NULL DEFAULT 0 AFTER `customer_id`,
+  ADD COLUMN `coupon_code` varchar(32) NOT NULL DEFAULT '' AFTER `coupon_id`,
+  ADD COLUMN `coupon_discount` decimal(10,2) NOT NULL DEFAULT 0 AFTER `coupon_code`;
\ No newline at end of file
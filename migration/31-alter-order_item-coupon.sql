@@ -0,0 +1,3 @@
+-- share of coupon discount of the order
+ALTER TABLE `order_item`
+  ADD COLUMN `coupon_discount` decimal(10,2) NOT NULL DEFAULT 0 AFTER `applied_promotions`;
\ No newline at end of file
@@ -0,0 +1,3 @@
+-- product category of category bundle promotion
+ALTER TABLE `product`
+  ADD COLUMN `category` varchar(20) COLLATE utf8mb4_unicode_ci NOT NULL DEFAULT '' AFTER `tax_category`;
\ No newline at end of file
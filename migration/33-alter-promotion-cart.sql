@@ -0,0 +1,19 @@
+-- cart level promotion has no product, so product id is no longer a foreign key
+-- name of foreign key depends on mysql version, so it is taken from information schema
+SET @promotion_fk = (
+  SELECT `CONSTRAINT_NAME` FROM `information_schema`.`KEY_COLUMN_USAGE`
+  WHERE `TABLE_SCHEMA` = DATABASE() AND `TABLE_NAME` = 'promotion' AND `COLUMN_NAME` = 'product_id' AND `REFERENCED_TABLE_NAME` = 'product'
+  LIMIT 1
+);
+SET @drop_fk = IF(@promotion_fk IS NULL, 'DO 0', CONCAT('ALTER TABLE `promotion` DROP FOREIGN KEY `', @promotion_fk, '`'));
+PREPARE drop_fk FROM @drop_fk;
+EXECUTE drop_fk;
+DEALLOCATE PREPARE drop_fk;
+
+ALTER TABLE `promotion`
+  MODIFY COLUMN `product_id` bigint UNSIGNED NOT NULL DEFAULT 0,
+  ADD COLUMN `min_total` decimal(10,2) NOT NULL DEFAULT 0 AFTER `promo_product_id`,
+  ADD COLUMN `promo_price` decimal(10,2) NOT NULL DEFAULT 0 AFTER `min_total`,
+  ADD COLUMN `category` varchar(20) COLLATE utf8mb4_unicode_ci NOT NULL DEFAULT '' AFTER `promo_price`,
+  ADD COLUMN `currency` char(3) NOT NULL DEFAULT '' AFTER `category`,
+  ADD KEY `promotion_IDX2` (`product_id`);
\ No newline at end of file
@@ -0,0 +1,5 @@
+-- existing orders have no cart level adjustments
+ALTER TABLE `order`
+  ADD COLUMN `adjustments` text COLLATE utf8mb4_unicode_ci NOT NULL AFTER `currency`;
+
+UPDATE `order` SET `adjustments` = '[]';
\ No newline at end of file
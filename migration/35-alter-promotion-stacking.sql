@@ -0,0 +1,5 @@
+-- existing promotions are not exclusive and can be stacked with any promotion
+ALTER TABLE `promotion`
+  ADD COLUMN `priority` int NOT NULL DEFAULT 0 AFTER `currency`,
+  ADD COLUMN `exclusive` tinyint(1) NOT NULL DEFAULT 0 AFTER `priority`,
+  ADD COLUMN `stackable_with` varchar(255) NOT NULL DEFAULT '[]' AFTER `exclusive`;
\ No newline at end of file
@@ -43,10 +43,11 @@ else
 fi
 
 # create table if not exists
-TABLES=("product" "product_quantity" "promotion")
-COUNT=1
+# migration file name is <number>-<table name>.sql
+MIGRATIONS=("01-product" "02-product_quantity" "03-promotion" "05-order" "06-order_item" "07-product_price" "08-tax_rate" "09-stock_movement" "10-reservation" "11-reservation_item" "12-cart" "13-cart_item" "14-coupon" "15-coupon_redemption" "16-promotion_item" "17-promotion_tier")
 
-for TABLE_NAME in "${TABLES[@]}"; do
+for MIGRATION in "${MIGRATIONS[@]}"; do
+    TABLE_NAME="${MIGRATION#*-}"
     # check table if exists
     TABLE_EXISTS=$(mysql -u"$MYSQL_USERNAME" -p"$MYSQL_PASSWORD" -D "$MYSQL_DB_NAME" -e "SHOW TABLES LIKE '$TABLE_NAME';" 2>/dev/null | grep "^$TABLE_NAME$")
     if [ "$TABLE_EXISTS" == "$TABLE_NAME" ]; then
@@ -55,10 +56,27 @@ for TABLE_NAME in "${TABLES[@]}"; do
         echo "Tabel '$TABLE_NAME' is not exists in database '$MYSQL_DB_NAME'."
         echo "Creating..."
 
-        mysql -u"$MYSQL_USERNAME" -p"$MYSQL_PASSWORD" $MYSQL_DB_NAME <./0$COUNT-$TABLE_NAME.sql
+        mysql -u"$MYSQL_USERNAME" -p"$MYSQL_PASSWORD" $MYSQL_DB_NAME <./$MIGRATION.sql
     fi
+done
+
+# alter tables that are created by older version, created tables above already have these columns
+# format is <migration>:<table name>:<column name>:<data type>, alter migration file name is <number>-alter-<table name>-<description>.sql
+# migration is skipped when the column has the data type, or when the column exists and data type is empty
+ALTERS=("18-alter-order-discount:order:total_discount:" "19-alter-order_item-promotions:order_item:applied_promotions:" "20-alter-product-price:product:price:decimal" "21-alter-order-price:order:total_price:decimal" "22-alter-order_item-price:order_item:price:decimal" "23-alter-order-currency:order:currency:" "24-alter-product-tax:product:tax_category:" "25-alter-order-tax:order:tax_total:" "26-alter-order_item-tax:order_item:tax_amount:" "27-alter-promotion-validity:promotion:starts_at:" "28-alter-product-deleted:product:deleted_at:" "29-alter-product_quantity-reserved:product_quantity:reserved:" "30-alter-order-coupon:order:coupon_id:" "31-alter-order_item-coupon:order_item:coupon_discount:" "32-alter-product-category:product:category:" "33-alter-promotion-cart:promotion:min_total:" "34-alter-order-adjustments:order:adjustments:" "35-alter-promotion-stacking:promotion:priority:")
 
-    COUNT=$((COUNT + 1))
+for ALTER in "${ALTERS[@]}"; do
+    IFS=":" read -r MIGRATION TABLE_NAME COLUMN_NAME DATA_TYPE <<<"$ALTER"
+    # check column and its data type
+    COLUMN_TYPE=$(mysql -u"$MYSQL_USERNAME" -p"$MYSQL_PASSWORD" -N -e "SELECT DATA_TYPE FROM information_schema.COLUMNS WHERE TABLE_SCHEMA = '$MYSQL_DB_NAME' AND TABLE_NAME = '$TABLE_NAME' AND COLUMN_NAME = '$COLUMN_NAME';" 2>/dev/null)
+    if [ -n "$COLUMN_TYPE" ] && { [ -z "$DATA_TYPE" ] || [ "$COLUMN_TYPE" == "$DATA_TYPE" ]; }; then
+        echo "Migration '$MIGRATION' is applied in '$MYSQL_DB_NAME'."
+    else
+        echo "Migration '$MIGRATION' is not applied in database '$MYSQL_DB_NAME'."
+        echo "Altering..."
+
+        mysql -u"$MYSQL_USERNAME" -p"$MYSQL_PASSWORD" $MYSQL_DB_NAME <./$MIGRATION.sql
+    fi
 done
 
 # run seed data
@@ -0,0 +1,60 @@
+package cartrepository
+
+import (
+	"github.com/gendutski/be-candidate-home-test/core/entity"
+	"github.com/gendutski/be-candidate-home-test/core/repository"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type repo struct {
+	db *gorm.DB
+}
+
+func New(db *gorm.DB) repository.CartRepo {
+	return &repo{db}
+}
+
+func (r *repo) CreateCart(cart *entity.Cart) error {
+	return r.db.Omit(clause.Associations).Create(cart).Error
+}
+
+func (r *repo) GetCartByID(id int64) (*entity.Cart, error) {
+	var result []*entity.Cart
+	err := r.db.
+		Preload("Items", func(db *gorm.DB) *gorm.DB {
+			return db.Order("id asc")
+		}).
+		Preload("Items.Product", unscoped).
+		Where("id = ?", id).
+		Limit(1).
+		Find(&result).
+		Error
+	if err != nil {
+		return nil, err
+	}
+	if len(result) == 0 {
+		return nil, nil
+	}
+	return result[0], nil
+}
+
+func (r *repo) SaveCartItem(item *entity.CartItem) error {
+	// cart item is unique by cart and product
+	return r.db.Omit(clause.Associations).
+		Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "cart_id"}, {Name: "product_id"}},
+			DoUpdates: clause.AssignmentColumns([]string{"quantity", "updated_at"}),
+		}).
+		Create(item).
+		Error
+}
+
+func (r *repo) DeleteCartItem(cartID, productID int64) error {
+	return r.db.Where("cart_id = ? AND product_id = ?", cartID, productID).Delete(&entity.CartItem{}).Error
+}
+
+// deleted product is still shown in cart
+func unscoped(db *gorm.DB) *gorm.DB {
+	return db.Unscoped()
+}
@@ -0,0 +1,176 @@
+package cartrepository_test
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"regexp"
+	"testing"
+	"time"
+
+	sqlmock "github.com/DATA-DOG/go-sqlmock"
+	"github.com/gendutski/be-candidate-home-test/core/entity"
+	"github.com/gendutski/be-candidate-home-test/core/repository"
+	cartrepository "github.com/gendutski/be-candidate-home-test/repository/cart-repository"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/driver/mysql"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+	"gorm.io/gorm/schema"
+)
+
+type AnyTime struct{}
+
+// Match satisfies sqlmock.Argument interface
+func (a AnyTime) Match(v driver.Value) bool {
+	_, ok := v.(time.Time)
+	return ok
+}
+
+func initRepo(db *sql.DB, mock sqlmock.Sqlmock) (repository.CartRepo, error) {
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT VERSION()")).
+		WillReturnRows(sqlmock.NewRows([]string{"VERSION()"}).AddRow("5.7.25-log"))
+	gdb, err := gorm.Open(mysql.New(mysql.Config{
+		Conn: db,
+	}), &gorm.Config{
+		Logger: logger.Default.LogMode(logger.LogLevel(logger.Info)),
+		NamingStrategy: schema.NamingStrategy{
+			SingularTable: true,
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+	return cartrepository.New(gdb), nil
+}
+
+func Test_CreateCart(t *testing.T) {
+	// mock db
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("error: %s", err.Error())
+	}
+	defer db.Close()
+
+	// init repo
+	repo, err := initRepo(db, mock)
+	if err != nil {
+		t.Errorf("error initRepo: %s", err.Error())
+		return
+	}
+
+	t.Run("positive", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectExec(regexp.QuoteMeta("INSERT INTO `cart` (`currency`,`order_id`,`created_at`,`updated_at`) VALUES (?,?,?,?)")).
+			WithArgs("USD", 0, AnyTime{}, AnyTime{}).
+			WillReturnResult(sqlmock.NewResult(3, 1))
+		mock.ExpectCommit()
+
+		cart := &entity.Cart{Currency: "USD"}
+		err := repo.CreateCart(cart)
+		assert.Nil(t, err)
+		assert.Nil(t, mock.ExpectationsWereMet())
+		assert.Equal(t, int64(3), cart.ID)
+	})
+}
+
+func Test_GetCartByID(t *testing.T) {
+	// mock db
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("error: %s", err.Error())
+	}
+	defer db.Close()
+
+	// init repo
+	repo, err := initRepo(db, mock)
+	if err != nil {
+		t.Errorf("error initRepo: %s", err.Error())
+		return
+	}
+	dayCreated, _ := time.Parse("2006-01-02", "2023-05-16")
+
+	t.Run("positive, include deleted product", func(t *testing.T) {
+		mock.
+			ExpectQuery(regexp.QuoteMeta("SELECT * FROM `cart` WHERE id = ? LIMIT ?")).
+			WithArgs(3, 1).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "currency", "order_id", "created_at", "updated_at"}).
+				AddRow(3, "USD", 0, dayCreated, dayCreated))
+		mock.
+			ExpectQuery(regexp.QuoteMeta("SELECT * FROM `cart_item` WHERE `cart_item`.`cart_id` = ? ORDER BY id asc")).
+			WithArgs(3).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "cart_id", "product_id", "quantity", "updated_at"}).
+				AddRow(1, 3, 1, 2, dayCreated))
+		mock.
+			ExpectQuery(regexp.QuoteMeta("SELECT * FROM `product` WHERE `product`.`id` = ?")).
+			WithArgs(1).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "serial", "name", "price", "updated_at"}).
+				AddRow(1, "120P90", "Google Home", "49.99", dayCreated))
+
+		resp, err := repo.GetCartByID(3)
+		assert.Nil(t, err)
+		assert.Nil(t, mock.ExpectationsWereMet())
+		assert.Equal(t, &entity.Cart{
+			ID:        3,
+			Currency:  "USD",
+			CreatedAt: dayCreated,
+			UpdatedAt: dayCreated,
+			Items: []*entity.CartItem{
+				{
+					ID: 1, CartID: 3, ProductID: 1, Quantity: 2, UpdatedAt: dayCreated,
+					Product: &entity.Product{ID: 1, Serial: "120P90", Name: "Google Home", Price: 4999, UpdatedAt: dayCreated},
+				},
+			},
+		}, resp)
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		mock.
+			ExpectQuery(regexp.QuoteMeta("SELECT * FROM `cart` WHERE id = ? LIMIT ?")).
+			WithArgs(9, 1).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "currency", "order_id", "created_at", "updated_at"}))
+
+		resp, err := repo.GetCartByID(9)
+		assert.Nil(t, err)
+		assert.Nil(t, resp)
+	})
+}
+
+func Test_CartItem(t *testing.T) {
+	// mock db
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("error: %s", err.Error())
+	}
+	defer db.Close()
+
+	// init repo
+	repo, err := initRepo(db, mock)
+	if err != nil {
+		t.Errorf("error initRepo: %s", err.Error())
+		return
+	}
+
+	t.Run("save, update quantity when product is already in cart", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectExec(regexp.QuoteMeta("INSERT INTO `cart_item` (`cart_id`,`product_id`,`quantity`,`updated_at`) VALUES (?,?,?,?) ON DUPLICATE KEY UPDATE `quantity`=VALUES(`quantity`),`updated_at`=VALUES(`updated_at`)")).
+			WithArgs(3, 1, 5, AnyTime{}).
+			WillReturnResult(sqlmock.NewResult(1, 2))
+		mock.ExpectCommit()
+
+		err := repo.SaveCartItem(&entity.CartItem{CartID: 3, ProductID: 1, Quantity: 5})
+		assert.Nil(t, err)
+		assert.Nil(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("delete", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectExec(regexp.QuoteMeta("DELETE FROM `cart_item` WHERE cart_id = ? AND product_id = ?")).
+			WithArgs(3, 1).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectCommit()
+
+		err := repo.DeleteCartItem(3, 1)
+		assert.Nil(t, err)
+		assert.Nil(t, mock.ExpectationsWereMet())
+	})
+}
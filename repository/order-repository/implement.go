@@ -0,0 +1,84 @@
+package orderrepository
+
+import (
+	"github.com/gendutski/be-candidate-home-test/core/entity"
+	"github.com/gendutski/be-candidate-home-test/core/repository"
+	"gorm.io/gorm"
+)
+
+type repo struct {
+	db *gorm.DB
+}
+
+func New(db *gorm.DB) repository.OrderRepo {
+	return &repo{db}
+}
+
+func (r *repo) GetOrderByID(id int64) (*entity.Order, error) {
+	var result []*entity.Order
+	err := r.db.Preload("Items.Product", unscoped).Where("id = ?", id).Limit(1).Find(&result).Error
+	if err != nil {
+		return nil, err
+	}
+	if len(result) == 0 {
+		return nil, nil
+	}
+	return result[0], nil
+}
+
+func (r *repo) GetOrders(filter *entity.OrderFilter) ([]*entity.Order, int64, error) {
+	// count all matched orders
+	var total int64
+	err := r.filterOrders(filter).Count(&total).Error
+	if err != nil {
+		return nil, 0, err
+	}
+	if total == 0 {
+		return nil, 0, nil
+	}
+
+	// get current page
+	var result []*entity.Order
+	err = r.filterOrders(filter).
+		Preload("Items.Product", unscoped).
+		Order("id desc").
+		Offset(filter.Offset()).
+		Limit(filter.Limit).
+		Find(&result).
+		Error
+	if err != nil {
+		return nil, 0, err
+	}
+	return result, total, nil
+}
+
+func (r *repo) filterOrders(filter *entity.OrderFilter) *gorm.DB {
+	query := r.db.Model(&entity.Order{})
+	if !filter.CreatedFrom.IsZero() {
+		query = query.Where("created_at >= ?", filter.CreatedFrom)
+	}
+	if !filter.CreatedTo.IsZero() {
+		query = query.Where("created_at < ?", filter.CreatedTo)
+	}
+	if filter.MinTotalPrice != nil {
+		query = query.Where("total_price >= ?", *filter.MinTotalPrice)
+	}
+	if filter.MaxTotalPrice != nil {
+		query = query.Where("total_price <= ?", *filter.MaxTotalPrice)
+	}
+	if filter.ProductSerial != "" {
+		// orders that contain the product
+		subQuery := r.db.
+			Table("order_item").
+			Select("order_item.order_id").
+			Joins("JOIN product ON product.id = order_item.product_id").
+			Where("product.serial = ?", filter.ProductSerial)
+		query = query.Where("id in (?)", subQuery)
+	}
+	return query
+}
+
+// deleted product must still be loaded for order items
+func unscoped(db *gorm.DB) *gorm.DB {
+	return db.Unscoped()
+}
@@ -0,0 +1,197 @@
+package orderrepository_test
+
+import (
+	"database/sql"
+	"regexp"
+	"testing"
+	"time"
+
+	sqlmock "github.com/DATA-DOG/go-sqlmock"
+	"github.com/gendutski/be-candidate-home-test/core/entity"
+	"github.com/gendutski/be-candidate-home-test/core/repository"
+	orderrepository "github.com/gendutski/be-candidate-home-test/repository/order-repository"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/driver/mysql"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+	"gorm.io/gorm/schema"
+)
+
+func initRepo(db *sql.DB, mock sqlmock.Sqlmock) (repository.OrderRepo, error) {
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT VERSION()")).
+		WillReturnRows(sqlmock.NewRows([]string{"VERSION()"}).AddRow("5.7.25-log"))
+	gdb, err := gorm.Open(mysql.New(mysql.Config{
+		Conn: db,
+	}), &gorm.Config{
+		Logger: logger.Default.LogMode(logger.LogLevel(logger.Info)),
+		NamingStrategy: schema.NamingStrategy{
+			SingularTable: true,
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+	return orderrepository.New(gdb), nil
+}
+
+func Test_GetOrderByID(t *testing.T) {
+	// mock db
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("error: %s", err.Error())
+	}
+	defer db.Close()
+
+	// init repo
+	repo, err := initRepo(db, mock)
+	if err != nil {
+		t.Errorf("error initRepo: %s", err.Error())
+		return
+	}
+	dayCreated, _ := time.Parse("2006-01-02", "2023-05-16")
+
+	t.Run("positive", func(t *testing.T) {
+		mock.
+			ExpectQuery(regexp.QuoteMeta("SELECT * FROM `order` WHERE id = ? LIMIT ?")).
+			WithArgs(7, 1).
+			WillReturnRows(sqlmock.
+				NewRows([]string{"id", "total_item", "total_price", "total_discount", "created_at"}).
+				AddRow(7, 2, 5399.99, 30, dayCreated))
+		mock.
+			ExpectQuery(regexp.QuoteMeta("SELECT * FROM `order_item` WHERE `order_item`.`order_id` = ?")).
+			WithArgs(7).
+			WillReturnRows(sqlmock.
+				NewRows([]string{"id", "order_id", "product_id", "quantity", "price", "sub_total_price", "applied_promotions"}).
+				AddRow(1, 7, 2, 1, 5399.99, 5399.99, "[]").
+				AddRow(2, 7, 4, 1, 30, 0, `[{"promotionId":1,"type":1,"discountAmount":30,"freeQuantity":1}]`))
+		mock.
+			ExpectQuery(regexp.QuoteMeta("SELECT * FROM `product` WHERE `product`.`id` IN (?,?)")).
+			WithArgs(2, 4).
+			WillReturnRows(sqlmock.
+				NewRows([]string{"id", "serial", "name", "price", "updated_at"}).
+				AddRow(2, "43N23P", "MacBook Pro", 5399.99, dayCreated).
+				AddRow(4, "234234", "Raspberry Pi B", 30, dayCreated))
+
+		resp, err := repo.GetOrderByID(7)
+		assert.Nil(t, err)
+		assert.Nil(t, mock.ExpectationsWereMet())
+		assert.Equal(t, &entity.Order{
+			ID:            7,
+			TotalItem:     2,
+			TotalPrice:    539999,
+			TotalDiscount: 3000,
+			CreatedAt:     dayCreated,
+			Items: []*entity.OrderItem{
+				{
+					ID: 1, OrderID: 7, ProductID: 2, Quantity: 1, Price: 539999, SubTotalPrice: 539999, AppliedPromotions: entity.AppliedPromotionList{},
+					Product: &entity.Product{ID: 2, Serial: "43N23P", Name: "MacBook Pro", Price: 539999, UpdatedAt: dayCreated},
+				},
+				{
+					ID: 2, OrderID: 7, ProductID: 4, Quantity: 1, Price: 3000, SubTotalPrice: 0,
+					AppliedPromotions: entity.AppliedPromotionList{
+						{PromotionID: 1, Type: entity.BonusItem, DiscountAmount: 3000, FreeQuantity: 1},
+					},
+					Product: &entity.Product{ID: 4, Serial: "234234", Name: "Raspberry Pi B", Price: 3000, UpdatedAt: dayCreated},
+				},
+			},
+		}, resp)
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		mock.
+			ExpectQuery(regexp.QuoteMeta("SELECT * FROM `order` WHERE id = ? LIMIT ?")).
+			WithArgs(8, 1).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "total_item", "total_price", "total_discount", "created_at"}))
+
+		resp, err := repo.GetOrderByID(8)
+		assert.Nil(t, err)
+		assert.Nil(t, mock.ExpectationsWereMet())
+		assert.Nil(t, resp)
+	})
+}
+
+func Test_GetOrders(t *testing.T) {
+	// mock db
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("error: %s", err.Error())
+	}
+	defer db.Close()
+
+	// init repo
+	repo, err := initRepo(db, mock)
+	if err != nil {
+		t.Errorf("error initRepo: %s", err.Error())
+		return
+	}
+	dayCreated, _ := time.Parse("2006-01-02", "2023-05-16")
+
+	t.Run("positive, with all filters", func(t *testing.T) {
+		minTotal := entity.Money(10000)
+		maxTotal := entity.Money(600000)
+		filter := &entity.OrderFilter{
+			CreatedFrom:   dayCreated,
+			CreatedTo:     dayCreated.AddDate(0, 0, 1),
+			ProductSerial: "43N23P",
+			MinTotalPrice: &minTotal,
+			MaxTotalPrice: &maxTotal,
+			Page:          2,
+			Limit:         1,
+		}
+		where := "WHERE created_at >= ? AND created_at < ? AND total_price >= ? AND total_price <= ? AND id in (SELECT order_item.order_id FROM `order_item` JOIN product ON product.id = order_item.product_id WHERE product.serial = ?)"
+
+		mock.
+			ExpectQuery(regexp.QuoteMeta("SELECT count(*) FROM `order` "+where)).
+			WithArgs(dayCreated, dayCreated.AddDate(0, 0, 1), "100.00", "6000.00", "43N23P").
+			WillReturnRows(sqlmock.NewRows([]string{"count(*)"}).AddRow(2))
+		mock.
+			ExpectQuery(regexp.QuoteMeta("SELECT * FROM `order` "+where+" ORDER BY id desc LIMIT ? OFFSET ?")).
+			WithArgs(dayCreated, dayCreated.AddDate(0, 0, 1), "100.00", "6000.00", "43N23P", 1, 1).
+			WillReturnRows(sqlmock.
+				NewRows([]string{"id", "total_item", "total_price", "total_discount", "created_at"}).
+				AddRow(7, 1, 5399.99, 0, dayCreated))
+		mock.
+			ExpectQuery(regexp.QuoteMeta("SELECT * FROM `order_item` WHERE `order_item`.`order_id` = ?")).
+			WithArgs(7).
+			WillReturnRows(sqlmock.
+				NewRows([]string{"id", "order_id", "product_id", "quantity", "price", "sub_total_price", "applied_promotions"}).
+				AddRow(1, 7, 2, 1, 5399.99, 5399.99, "[]"))
+		mock.
+			ExpectQuery(regexp.QuoteMeta("SELECT * FROM `product` WHERE `product`.`id` = ?")).
+			WithArgs(2).
+			WillReturnRows(sqlmock.
+				NewRows([]string{"id", "serial", "name", "price", "updated_at"}).
+				AddRow(2, "43N23P", "MacBook Pro", 5399.99, dayCreated))
+
+		resp, total, err := repo.GetOrders(filter)
+		assert.Nil(t, err)
+		assert.Nil(t, mock.ExpectationsWereMet())
+		assert.Equal(t, int64(2), total)
+		assert.Equal(t, []*entity.Order{
+			{
+				ID:         7,
+				TotalItem:  1,
+				TotalPrice: 539999,
+				CreatedAt:  dayCreated,
+				Items: []*entity.OrderItem{
+					{
+						ID: 1, OrderID: 7, ProductID: 2, Quantity: 1, Price: 539999, SubTotalPrice: 539999, AppliedPromotions: entity.AppliedPromotionList{},
+						Product: &entity.Product{ID: 2, Serial: "43N23P", Name: "MacBook Pro", Price: 539999, UpdatedAt: dayCreated},
+					},
+				},
+			},
+		}, resp)
+	})
+
+	t.Run("positive, no order found", func(t *testing.T) {
+		mock.
+			ExpectQuery(regexp.QuoteMeta("SELECT count(*) FROM `order`")).
+			WillReturnRows(sqlmock.NewRows([]string{"count(*)"}).AddRow(0))
+
+		resp, total, err := repo.GetOrders(&entity.OrderFilter{Page: 1, Limit: 10})
+		assert.Nil(t, err)
+		assert.Nil(t, mock.ExpectationsWereMet())
+		assert.Equal(t, int64(0), total)
+		assert.Nil(t, resp)
+	})
+}
@@ -3,6 +3,9 @@ package productrepository
 import (
 	"fmt"
 	"net/http"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/gendutski/be-candidate-home-test/core/entity"
 	"github.com/gendutski/be-candidate-home-test/core/repository"
@@ -36,7 +39,25 @@ func (r *repo) GetProductByIDs(ids []int64) ([]*entity.Product, error) {
 	return result, nil
 }
 
-func (r *repo) SubmitCheckout(payload *entity.Checkout) (err error) {
+func (r *repo) GetProductPrices(productIDs []int64, currency string) ([]*entity.ProductPrice, error) {
+	var result []*entity.ProductPrice
+	err := r.db.Where("product_id in (?) AND currency = ?", productIDs, currency).Find(&result).Error
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func (r *repo) GetProductQuantities(productIDs []int64) ([]*entity.ProductQuantity, error) {
+	var result []*entity.ProductQuantity
+	err := r.db.Where("product_id in (?)", productIDs).Find(&result).Error
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func (r *repo) SubmitCheckout(payload *entity.Checkout) (order *entity.Order, err error) {
 	// begin transaction
 	tx := r.db.Begin()
 	defer func() {
@@ -57,42 +78,198 @@ func (r *repo) SubmitCheckout(payload *entity.Checkout) (err error) {
 		return
 	}
 
-	// lock for update product quantity
-	var mapProdQty map[int64]*entity.ProductQuantity
-	productIDs := r.pluckProductIDFromCheckoutItems(payload.Items)
-	mapProdQty, err = r.lockAndMapProductQuantity(productIDs, tx)
+	// decrease product quantity
+	var movements []*entity.StockMovement
+	if payload.ReservationID != 0 {
+		movements, err = r.consumeReservation(payload, tx)
+	} else {
+		movements, err = r.decreaseQuantity(payload, tx)
+	}
 	if err != nil {
+		tx.Rollback()
+		return
+	}
+
+	// write order
+	order = entity.NewOrder(payload)
+	err = r.createOrder(order, tx)
+	if err != nil {
+		order = nil
 		err = entity.NewError(err.Error(), http.StatusInternalServerError)
 		tx.Rollback()
 		return
 	}
 
-	// validate and update product quantity
-	for _, item := range payload.Items {
-		newQuantity := mapProdQty[item.Product.ID].Quantity - item.Quantity
-		if newQuantity < 0 {
-			err = entity.NewError(
-				fmt.Sprintf("checkout item %s(%s) exceeds existing quantity, only %d items remaining",
-					item.Product.Name, item.Product.Serial, mapProdQty[item.Product.ID].Quantity),
-				http.StatusBadRequest)
+	// close cart, so it cannot be checked out twice
+	if payload.CartID != 0 {
+		err = r.closeCart(payload.CartID, order.ID, tx)
+		if err != nil {
+			order = nil
 			tx.Rollback()
 			return
 		}
+	}
 
-		// update table product_quantity
-		mapProdQty[item.Product.ID].Quantity = newQuantity
-		err = tx.Save(mapProdQty[item.Product.ID]).Error
+	// count coupon redemption, so coupon limits cannot be exceeded
+	if payload.Coupon != nil {
+		err = r.redeemCoupon(payload, order.ID, tx)
 		if err != nil {
-			err = entity.NewError(err.Error(), http.StatusInternalServerError)
+			order = nil
 			tx.Rollback()
 			return
 		}
 	}
 
+	// write stock movement with order id as reference
+	for _, movement := range movements {
+		movement.Reference = strconv.FormatInt(order.ID, 10)
+	}
+	err = tx.Create(&movements).Error
+	if err != nil {
+		order = nil
+		err = entity.NewError(err.Error(), http.StatusInternalServerError)
+		tx.Rollback()
+		return
+	}
+
 	err = tx.Commit().Error
+	if err != nil {
+		order = nil
+	}
 	return
 }
 
+// lock product quantity, then validate and decrease it by checkout items
+// quantity held by reservations cannot be sold
+func (r *repo) decreaseQuantity(payload *entity.Checkout, tx *gorm.DB) ([]*entity.StockMovement, error) {
+	// lock for update product quantity
+	productIDs := r.pluckProductIDFromCheckoutItems(payload.Items)
+	mapProdQty, err := r.lockAndMapProductQuantity(productIDs, tx)
+	if err != nil {
+		return nil, entity.NewError(err.Error(), http.StatusInternalServerError)
+	}
+
+	// validate all items, so every insufficient item is reported at once
+	var messages []string
+	var outOfStock []*entity.OutOfStockItem
+	for _, item := range payload.Items {
+		prodQty := mapProdQty[item.Product.ID]
+		if item.Quantity > prodQty.Available() {
+			messages = append(messages, fmt.Sprintf("checkout item %s(%s) exceeds existing quantity, only %d items remaining",
+				item.Product.Name, item.Product.Serial, prodQty.Available()))
+			outOfStock = append(outOfStock, &entity.OutOfStockItem{
+				Serial:    item.Product.Serial,
+				Requested: item.Quantity,
+				Available: prodQty.Available(),
+			})
+		}
+	}
+	if len(outOfStock) > 0 {
+		return nil, entity.NewOutOfStockError(strings.Join(messages, "; "), outOfStock)
+	}
+
+	// update product quantity
+	var movements []*entity.StockMovement
+	for _, item := range payload.Items {
+		prodQty := mapProdQty[item.Product.ID]
+		prodQty.Quantity -= item.Quantity
+		err = tx.Save(prodQty).Error
+		if err != nil {
+			return nil, entity.NewError(err.Error(), http.StatusInternalServerError)
+		}
+		movements = append(movements, &entity.StockMovement{
+			ProductID: item.Product.ID,
+			Delta:     -item.Quantity,
+			Balance:   prodQty.Quantity,
+			Reason:    entity.StockSale,
+		})
+	}
+	return movements, nil
+}
+
+// convert reserved quantity into sale, reservation must be active at submit time of checkout
+// product quantity is not locked again, because the reservation already holds it
+func (r *repo) consumeReservation(payload *entity.Checkout, tx *gorm.DB) ([]*entity.StockMovement, error) {
+	// lock reservation, so it cannot be consumed twice or released at the same time
+	var reservations []*entity.Reservation
+	err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Preload("Items").
+		Where("id = ?", payload.ReservationID).
+		Limit(1).
+		Find(&reservations).
+		Error
+	if err != nil {
+		return nil, entity.NewError(err.Error(), http.StatusInternalServerError)
+	}
+	if len(reservations) == 0 ||
+		reservations[0].Status != entity.ReservationActive ||
+		!reservations[0].ExpiresAt.After(payload.SubmittedAt) {
+		return nil, entity.NewError(entity.ReservationNotActive, http.StatusBadRequest)
+	}
+	reservation := reservations[0]
+
+	// map reserved quantity, where int64 = product id
+	reserved := map[int64]int{}
+	var reservedIDs []int64
+	for _, item := range reservation.Items {
+		if _, ok := reserved[item.ProductID]; !ok {
+			reservedIDs = append(reservedIDs, item.ProductID)
+		}
+		reserved[item.ProductID] += item.Quantity
+	}
+
+	// checkout items must be covered by reservation
+	sold := map[int64]int{}
+	for _, item := range payload.Items {
+		if item.Quantity > reserved[item.Product.ID] {
+			return nil, entity.NewError(
+				fmt.Sprintf("checkout item %s(%s) exceeds reserved quantity, only %d items reserved",
+					item.Product.Name, item.Product.Serial, reserved[item.Product.ID]),
+				http.StatusBadRequest)
+		}
+		sold[item.Product.ID] = item.Quantity
+	}
+
+	// decrease quantity by sold items, and release all reserved items
+	for _, productID := range reservedIDs {
+		err = tx.Model(&entity.ProductQuantity{}).
+			Where("product_id = ?", productID).
+			Updates(map[string]interface{}{
+				"quantity": gorm.Expr("quantity - ?", sold[productID]),
+				"reserved": gorm.Expr("reserved - ?", reserved[productID]),
+			}).Error
+		if err != nil {
+			return nil, entity.NewError(err.Error(), http.StatusInternalServerError)
+		}
+	}
+	err = tx.Model(reservation).Omit(clause.Associations).Update("status", entity.ReservationConsumed).Error
+	if err != nil {
+		return nil, entity.NewError(err.Error(), http.StatusInternalServerError)
+	}
+
+	// get balance of sold items for stock movement
+	var quantities []*entity.ProductQuantity
+	err = tx.Where("product_id in (?)", r.pluckProductIDFromCheckoutItems(payload.Items)).Find(&quantities).Error
+	if err != nil {
+		return nil, entity.NewError(err.Error(), http.StatusInternalServerError)
+	}
+	balance := map[int64]int{}
+	for _, q := range quantities {
+		balance[q.ProductID] = q.Quantity
+	}
+
+	var movements []*entity.StockMovement
+	for _, item := range payload.Items {
+		movements = append(movements, &entity.StockMovement{
+			ProductID: item.Product.ID,
+			Delta:     -item.Quantity,
+			Balance:   balance[item.Product.ID],
+			Reason:    entity.StockSale,
+		})
+	}
+	return movements, nil
+}
+
 func (r *repo) pluckProductIDFromCheckoutItems(items []*entity.CheckoutItem) []int64 {
 	var result []int64
 	for _, item := range items {
@@ -101,6 +278,87 @@ func (r *repo) pluckProductIDFromCheckoutItems(items []*entity.CheckoutItem) []i
 	return result
 }
 
+// insert order and its items
+func (r *repo) createOrder(order *entity.Order, tx *gorm.DB) error {
+	err := tx.Omit(clause.Associations).Create(order).Error
+	if err != nil {
+		return err
+	}
+
+	for _, item := range order.Items {
+		item.OrderID = order.ID
+	}
+	if len(order.Items) == 0 {
+		return nil
+	}
+	return tx.Omit(clause.Associations).Create(&order.Items).Error
+}
+
+// set order id of open cart
+func (r *repo) closeCart(cartID, orderID int64, tx *gorm.DB) error {
+	result := tx.Model(&entity.Cart{}).
+		Where("id = ? AND order_id = 0", cartID).
+		Update("order_id", orderID)
+	if result.Error != nil {
+		return entity.NewError(result.Error.Error(), http.StatusInternalServerError)
+	}
+	if result.RowsAffected == 0 {
+		return entity.NewError(entity.CartCheckedOut, http.StatusBadRequest)
+	}
+	return nil
+}
+
+// lock coupon, then validate usage limits and record redemption of the order
+// coupon lock serializes concurrent redemptions of the same coupon
+func (r *repo) redeemCoupon(payload *entity.Checkout, orderID int64, tx *gorm.DB) error {
+	var coupons []*entity.Coupon
+	err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("id = ?", payload.Coupon.ID).
+		Limit(1).
+		Find(&coupons).
+		Error
+	if err != nil {
+		return entity.NewError(err.Error(), http.StatusInternalServerError)
+	}
+	// coupon is deleted after checkout is priced
+	if len(coupons) == 0 {
+		return entity.NewError(entity.CouponNotFound, http.StatusBadRequest)
+	}
+	coupon := coupons[0]
+	if coupon.UsageLimitReached() {
+		return entity.NewError(entity.CouponUsageLimitReached, http.StatusBadRequest)
+	}
+
+	// count previous redemptions of the customer
+	if coupon.PerCustomerLimit > 0 {
+		var used int64
+		err = tx.Model(&entity.CouponRedemption{}).
+			Where("coupon_id = ? AND customer_id = ?", coupon.ID, payload.CustomerID).
+			Count(&used).
+			Error
+		if err != nil {
+			return entity.NewError(err.Error(), http.StatusInternalServerError)
+		}
+		if used >= int64(coupon.PerCustomerLimit) {
+			return entity.NewError(entity.CouponCustomerLimitReached, http.StatusBadRequest)
+		}
+	}
+
+	err = tx.Model(coupon).Update("used_count", gorm.Expr("used_count + ?", 1)).Error
+	if err != nil {
+		return entity.NewError(err.Error(), http.StatusInternalServerError)
+	}
+	err = tx.Create(&entity.CouponRedemption{
+		CouponID:   coupon.ID,
+		OrderID:    orderID,
+		CustomerID: payload.CustomerID,
+	}).Error
+	if err != nil {
+		return entity.NewError(err.Error(), http.StatusInternalServerError)
+	}
+	return nil
+}
+
 // lock and get product quantity
 // return map[int64] where int64 = product id
 func (r *repo) lockAndMapProductQuantity(productIDs []int64, tx *gorm.DB) (map[int64]*entity.ProductQuantity, error) {
@@ -120,3 +378,223 @@ func (r *repo) lockAndMapProductQuantity(productIDs []int64, tx *gorm.DB) (map[i
 	}
 	return result, nil
 }
+
+func (r *repo) GetProductByID(id int64) (*entity.Product, error) {
+	var result []*entity.Product
+	err := r.db.Where("id = ?", id).Limit(1).Find(&result).Error
+	if err != nil {
+		return nil, err
+	}
+	if len(result) == 0 {
+		return nil, nil
+	}
+	return result[0], nil
+}
+
+func (r *repo) GetProducts(filter *entity.ProductFilter) ([]*entity.Product, int64, error) {
+	// count all products
+	var total int64
+	err := r.db.Model(&entity.Product{}).Count(&total).Error
+	if err != nil {
+		return nil, 0, err
+	}
+	if total == 0 {
+		return nil, 0, nil
+	}
+
+	// get current page
+	var result []*entity.Product
+	err = r.db.Order("id asc").Offset(filter.Offset()).Limit(filter.Limit).Find(&result).Error
+	if err != nil {
+		return nil, 0, err
+	}
+	return result, total, nil
+}
+
+func (r *repo) CountProductBySerial(serial string, exceptID int64) (int64, error) {
+	// serial is unique in table, including deleted product
+	var total int64
+	err := r.db.Unscoped().Model(&entity.Product{}).
+		Where("serial = ? AND id <> ?", serial, exceptID).
+		Count(&total).Error
+	return total, err
+}
+
+func (r *repo) CreateProduct(product *entity.Product, quantity int) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(product).Error; err != nil {
+			return err
+		}
+		err := tx.Create(&entity.ProductQuantity{
+			ProductID: product.ID,
+			Quantity:  quantity,
+		}).Error
+		if err != nil {
+			return err
+		}
+		// initial quantity is the first stock movement
+		return tx.Create(&entity.StockMovement{
+			ProductID: product.ID,
+			Delta:     quantity,
+			Balance:   quantity,
+			Reason:    entity.StockInitial,
+		}).Error
+	})
+}
+
+func (r *repo) UpdateProduct(product *entity.Product) error {
+	return r.db.Save(product).Error
+}
+
+func (r *repo) DeleteProduct(id int64) error {
+	return r.db.Delete(&entity.Product{}, id).Error
+}
+
+func (r *repo) AdjustQuantity(movement *entity.StockMovement) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		// lock for update product quantity
+		mapProdQty, err := r.lockAndMapProductQuantity([]int64{movement.ProductID}, tx)
+		if err != nil {
+			return entity.NewError(err.Error(), http.StatusInternalServerError)
+		}
+		prodQty, ok := mapProdQty[movement.ProductID]
+		if !ok {
+			return entity.NewError(entity.ProductNotFound, http.StatusNotFound)
+		}
+
+		// validate and update product quantity, quantity held by reservations cannot be removed
+		newQuantity := prodQty.Quantity + movement.Delta
+		if newQuantity < prodQty.Reserved {
+			return entity.NewError(
+				fmt.Sprintf("adjustment exceeds existing quantity, only %d items remaining", prodQty.Available()),
+				http.StatusBadRequest)
+		}
+		prodQty.Quantity = newQuantity
+		if err := tx.Save(prodQty).Error; err != nil {
+			return entity.NewError(err.Error(), http.StatusInternalServerError)
+		}
+
+		// write stock movement
+		movement.Balance = newQuantity
+		if err := tx.Create(movement).Error; err != nil {
+			return entity.NewError(err.Error(), http.StatusInternalServerError)
+		}
+		return nil
+	})
+}
+
+func (r *repo) GetStockMovements(filter *entity.StockMovementFilter) ([]*entity.StockMovement, int64, error) {
+	// count all movements of product
+	var total int64
+	err := r.db.Model(&entity.StockMovement{}).Where("product_id = ?", filter.ProductID).Count(&total).Error
+	if err != nil {
+		return nil, 0, err
+	}
+	if total == 0 {
+		return nil, 0, nil
+	}
+
+	// get current page, latest movement first
+	var result []*entity.StockMovement
+	err = r.db.Where("product_id = ?", filter.ProductID).
+		Order("id desc").
+		Offset(filter.Offset()).
+		Limit(filter.Limit).
+		Find(&result).Error
+	if err != nil {
+		return nil, 0, err
+	}
+	return result, total, nil
+}
+
+func (r *repo) ReserveStock(reservation *entity.Reservation) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		// lock for update product quantity
+		var productIDs []int64
+		for _, item := range reservation.Items {
+			productIDs = append(productIDs, item.ProductID)
+		}
+		mapProdQty, err := r.lockAndMapProductQuantity(productIDs, tx)
+		if err != nil {
+			return entity.NewError(err.Error(), http.StatusInternalServerError)
+		}
+
+		// validate all items, so every insufficient item is reported at once
+		var messages []string
+		var outOfStock []*entity.OutOfStockItem
+		for _, item := range reservation.Items {
+			prodQty, ok := mapProdQty[item.ProductID]
+			if !ok {
+				return entity.NewError(entity.ProductNotFound, http.StatusBadRequest)
+			}
+			if item.Quantity > prodQty.Available() {
+				messages = append(messages, fmt.Sprintf("reserved item %s(%s) exceeds available quantity, only %d items available",
+					item.Product.Name, item.Product.Serial, prodQty.Available()))
+				outOfStock = append(outOfStock, &entity.OutOfStockItem{
+					Serial:    item.Product.Serial,
+					Requested: item.Quantity,
+					Available: prodQty.Available(),
+				})
+			}
+		}
+		if len(outOfStock) > 0 {
+			return entity.NewOutOfStockError(strings.Join(messages, "; "), outOfStock)
+		}
+
+		// hold available quantity
+		for _, item := range reservation.Items {
+			prodQty := mapProdQty[item.ProductID]
+			prodQty.Reserved += item.Quantity
+			if err := tx.Save(prodQty).Error; err != nil {
+				return entity.NewError(err.Error(), http.StatusInternalServerError)
+			}
+		}
+
+		// write reservation and its items
+		reservation.Status = entity.ReservationActive
+		if err := tx.Omit(clause.Associations).Create(reservation).Error; err != nil {
+			return entity.NewError(err.Error(), http.StatusInternalServerError)
+		}
+		for _, item := range reservation.Items {
+			item.ReservationID = reservation.ID
+		}
+		if err := tx.Omit(clause.Associations).Create(&reservation.Items).Error; err != nil {
+			return entity.NewError(err.Error(), http.StatusInternalServerError)
+		}
+		return nil
+	})
+}
+
+func (r *repo) ReleaseExpiredReservations(at time.Time) (int64, error) {
+	var total int64
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		// lock expired reservations, so they cannot be consumed at the same time
+		var reservations []*entity.Reservation
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Preload("Items").
+			Where("status = ? AND expires_at <= ?", entity.ReservationActive, at).
+			Find(&reservations).
+			Error
+		if err != nil {
+			return err
+		}
+
+		for _, reservation := range reservations {
+			for _, item := range reservation.Items {
+				err = tx.Model(&entity.ProductQuantity{}).
+					Where("product_id = ?", item.ProductID).
+					Update("reserved", gorm.Expr("reserved - ?", item.Quantity)).Error
+				if err != nil {
+					return err
+				}
+			}
+			err = tx.Model(reservation).Omit(clause.Associations).Update("status", entity.ReservationReleased).Error
+			if err != nil {
+				return err
+			}
+		}
+		total = int64(len(reservations))
+		return nil
+	})
+	return total, err
+}
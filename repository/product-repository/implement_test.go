@@ -3,6 +3,8 @@ package productrepository_test
 import (
 	"database/sql"
 	"database/sql/driver"
+	"errors"
+	"net/http"
 	"regexp"
 	"testing"
 	"time"
@@ -73,8 +75,8 @@ func Test_GetProductBySerials(t *testing.T) {
 		resp, err := repo.GetProductBySerials([]string{"120P90", "A304SD"})
 		assert.Nil(t, err)
 		assert.Equal(t, []*entity.Product{
-			{ID: 1, Serial: "120P90", Name: "Google Home", Price: 49.99, UpdatedAt: dayCreated},
-			{ID: 3, Serial: "A304SD", Name: "Alexa Speaker", Price: 109.50, UpdatedAt: dayCreated},
+			{ID: 1, Serial: "120P90", Name: "Google Home", Price: 4999, UpdatedAt: dayCreated},
+			{ID: 3, Serial: "A304SD", Name: "Alexa Speaker", Price: 10950, UpdatedAt: dayCreated},
 		}, resp)
 	})
 }
@@ -109,8 +111,79 @@ func Test_GetProductByIDs(t *testing.T) {
 		resp, err := repo.GetProductByIDs([]int64{1, 3})
 		assert.Nil(t, err)
 		assert.Equal(t, []*entity.Product{
-			{ID: 1, Serial: "120P90", Name: "Google Home", Price: 49.99, UpdatedAt: dayCreated},
-			{ID: 3, Serial: "A304SD", Name: "Alexa Speaker", Price: 109.50, UpdatedAt: dayCreated},
+			{ID: 1, Serial: "120P90", Name: "Google Home", Price: 4999, UpdatedAt: dayCreated},
+			{ID: 3, Serial: "A304SD", Name: "Alexa Speaker", Price: 10950, UpdatedAt: dayCreated},
+		}, resp)
+	})
+}
+
+func Test_GetProductQuantities(t *testing.T) {
+	// mock db
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("error: %s", err.Error())
+	}
+	defer db.Close()
+
+	// init repo
+	repo, err := initRepo(db, mock)
+	if err != nil {
+		t.Errorf("error initRepo: %s", err.Error())
+		return
+	}
+	dayCreated, _ := time.Parse("2006-01-02", "2023-05-16")
+
+	t.Run("positive, without locking", func(t *testing.T) {
+		rows := sqlmock.
+			NewRows([]string{"id", "product_id", "quantity", "updated_at"}).
+			AddRow(1, 1, 10, dayCreated).
+			AddRow(3, 3, 2, dayCreated)
+
+		mock.
+			ExpectQuery(regexp.QuoteMeta("SELECT * FROM `product_quantity` WHERE product_id in (?,?)")).
+			WithArgs(1, 3).
+			WillReturnRows(rows)
+
+		resp, err := repo.GetProductQuantities([]int64{1, 3})
+		assert.Nil(t, err)
+		assert.Equal(t, []*entity.ProductQuantity{
+			{ID: 1, ProductID: 1, Quantity: 10, UpdatedAt: dayCreated},
+			{ID: 3, ProductID: 3, Quantity: 2, UpdatedAt: dayCreated},
+		}, resp)
+	})
+}
+
+func Test_GetProductPrices(t *testing.T) {
+	// mock db
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("error: %s", err.Error())
+	}
+	defer db.Close()
+
+	// init repo
+	repo, err := initRepo(db, mock)
+	if err != nil {
+		t.Errorf("error initRepo: %s", err.Error())
+		return
+	}
+	dayCreated, _ := time.Parse("2006-01-02", "2023-05-16")
+
+	t.Run("positive", func(t *testing.T) {
+		rows := sqlmock.
+			NewRows([]string{"id", "product_id", "currency", "price", "updated_at"}).
+			AddRow(1, 1, "EUR", "45.50", dayCreated).
+			AddRow(2, 3, "EUR", "99.00", dayCreated)
+		mock.
+			ExpectQuery(regexp.QuoteMeta("SELECT * FROM `product_price` WHERE product_id in (?,?) AND currency = ?")).
+			WithArgs(1, 3, "EUR").
+			WillReturnRows(rows)
+
+		resp, err := repo.GetProductPrices([]int64{1, 3}, "EUR")
+		assert.Nil(t, err)
+		assert.Equal(t, []*entity.ProductPrice{
+			{ID: 1, ProductID: 1, Currency: "EUR", Price: 4550, UpdatedAt: dayCreated},
+			{ID: 2, ProductID: 3, Currency: "EUR", Price: 9900, UpdatedAt: dayCreated},
 		}, resp)
 	})
 }
@@ -144,22 +217,48 @@ func Test_SubmitCheckout(t *testing.T) {
 			WillReturnRows(rows)
 
 		// validate and update product quantity
-		mock.ExpectExec(regexp.QuoteMeta("UPDATE `product_quantity` SET `product_id`=?,`quantity`=?,`updated_at`=? WHERE `id` = ?")).
-			WithArgs(1, 9, AnyTime{}, 1).
+		mock.ExpectExec(regexp.QuoteMeta("UPDATE `product_quantity` SET `product_id`=?,`quantity`=?,`reserved`=?,`updated_at`=? WHERE `id` = ?")).
+			WithArgs(1, 9, 0, AnyTime{}, 1).
+			WillReturnResult(sqlmock.NewResult(1, 1))
+
+		// write order
+		mock.ExpectExec(regexp.QuoteMeta("INSERT INTO `order` (`total_item`,`total_price`,`total_discount`,`tax_total`,`grand_total`,`currency`,`adjustments`,`customer_id`,`coupon_id`,`coupon_code`,`coupon_discount`,`created_at`) VALUES (?,?,?,?,?,?,?,?,?,?,?,?)")).
+			WithArgs(1, "49.99", "0.00", "5.50", "55.49", "USD", "[]", "", 0, "", "0.00", AnyTime{}).
+			WillReturnResult(sqlmock.NewResult(7, 1))
+		mock.ExpectExec(regexp.QuoteMeta("INSERT INTO `order_item` (`order_id`,`product_id`,`quantity`,`price`,`sub_total_price`,`tax_amount`,`applied_promotions`,`coupon_discount`) VALUES (?,?,?,?,?,?,?,?)")).
+			WithArgs(7, 1, 1, "49.99", "49.99", "5.50", "[]", "0.00").
+			WillReturnResult(sqlmock.NewResult(1, 1))
+
+		// write stock movement with order id as reference
+		mock.ExpectExec(regexp.QuoteMeta("INSERT INTO `stock_movement` (`product_id`,`delta`,`balance`,`reason`,`reference`,`created_at`) VALUES (?,?,?,?,?,?)")).
+			WithArgs(1, -1, 9, entity.StockSale, "7", AnyTime{}).
 			WillReturnResult(sqlmock.NewResult(1, 1))
 
 		mock.ExpectCommit()
 
 		// checkout 1 of 10 existing items
-		err := repo.SubmitCheckout(&entity.Checkout{
+		product := &entity.Product{ID: 1, Serial: "120P90", Name: "Google Home", Price: 4999, UpdatedAt: dayCreated}
+		order, err := repo.SubmitCheckout(&entity.Checkout{
 			Items: []*entity.CheckoutItem{
 				{
-					Product:  &entity.Product{ID: 1, Serial: "120P90", Name: "Google Home", Price: 49.99, UpdatedAt: dayCreated},
-					Quantity: 1,
+					Product:       product,
+					Quantity:      1,
+					SubTotalPrice: 4999,
+					TaxAmount:     550,
 				},
 			},
+			TotalItem:  1,
+			TotalPrice: 4999,
+			TaxTotal:   550,
+			GrandTotal: 5549,
+			Currency:   "USD",
 		})
 		assert.Nil(t, err)
+		assert.Nil(t, mock.ExpectationsWereMet())
+		assert.Equal(t, int64(7), order.ID)
+		assert.Equal(t, []*entity.OrderItem{
+			{ID: 1, OrderID: 7, ProductID: 1, Quantity: 1, Price: 4999, SubTotalPrice: 4999, TaxAmount: 550, Product: product},
+		}, order.Items)
 	})
 
 	t.Run("negative, item quantity is insufficient", func(t *testing.T) {
@@ -167,25 +266,616 @@ func Test_SubmitCheckout(t *testing.T) {
 
 		// lock for update product_quantity
 		rows := sqlmock.
-			NewRows([]string{"id", "product_id", "quantity", "updated_at"}).
-			AddRow(1, 1, 10, dayCreated)
+			NewRows([]string{"id", "product_id", "quantity", "reserved", "updated_at"}).
+			AddRow(1, 1, 10, 0, dayCreated).
+			AddRow(2, 2, 5, 0, dayCreated).
+			AddRow(4, 4, 2, 1, dayCreated)
 		mock.
-			ExpectQuery(regexp.QuoteMeta("SELECT * FROM `product_quantity` WHERE product_id in (?) FOR UPDATE")).
-			WithArgs(1).
+			ExpectQuery(regexp.QuoteMeta("SELECT * FROM `product_quantity` WHERE product_id in (?,?,?) FOR UPDATE")).
+			WithArgs(1, 2, 4).
 			WillReturnRows(rows)
 
-		// item is insufficient, rollback before update
+		// items are insufficient, rollback before update
 		mock.ExpectRollback()
 
-		// checkout 11 of 10 existing items
-		err := repo.SubmitCheckout(&entity.Checkout{
+		// checkout 11 of 10 existing items, 5 of 5 existing items, and 2 of 1 available items
+		order, err := repo.SubmitCheckout(&entity.Checkout{
 			Items: []*entity.CheckoutItem{
 				{
-					Product:  &entity.Product{ID: 1, Serial: "120P90", Name: "Google Home", Price: 49.99, UpdatedAt: dayCreated},
+					Product:  &entity.Product{ID: 1, Serial: "120P90", Name: "Google Home", Price: 4999, UpdatedAt: dayCreated},
 					Quantity: 11,
 				},
+				{
+					Product:  &entity.Product{ID: 2, Serial: "43N23P", Name: "MacBook Pro", Price: 539999, UpdatedAt: dayCreated},
+					Quantity: 5,
+				},
+				{
+					Product:  &entity.Product{ID: 4, Serial: "234234", Name: "Raspberry Pi B", Price: 3000, UpdatedAt: dayCreated},
+					Quantity: 2,
+				},
 			},
 		})
+		assert.Nil(t, order)
+		assert.Equal(t, entity.NewOutOfStockError(
+			"checkout item Google Home(120P90) exceeds existing quantity, only 10 items remaining; "+
+				"checkout item Raspberry Pi B(234234) exceeds existing quantity, only 1 items remaining",
+			[]*entity.OutOfStockItem{
+				{Serial: "120P90", Requested: 11, Available: 10},
+				{Serial: "234234", Requested: 2, Available: 1},
+			}), err)
+		assert.Nil(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("negative, cart is already checked out", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.
+			ExpectQuery(regexp.QuoteMeta("SELECT * FROM `product_quantity` WHERE product_id in (?) FOR UPDATE")).
+			WithArgs(1).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "product_id", "quantity", "updated_at"}).AddRow(1, 1, 10, dayCreated))
+		mock.ExpectExec(regexp.QuoteMeta("UPDATE `product_quantity`")).
+			WillReturnResult(sqlmock.NewResult(1, 1))
+		mock.ExpectExec(regexp.QuoteMeta("INSERT INTO `order`")).
+			WillReturnResult(sqlmock.NewResult(8, 1))
+		mock.ExpectExec(regexp.QuoteMeta("INSERT INTO `order_item`")).
+			WillReturnResult(sqlmock.NewResult(1, 1))
+
+		// cart was closed by another checkout
+		mock.ExpectExec(regexp.QuoteMeta("UPDATE `cart` SET `order_id`=?,`updated_at`=? WHERE id = ? AND order_id = 0")).
+			WithArgs(8, AnyTime{}, 3).
+			WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectRollback()
+
+		order, err := repo.SubmitCheckout(&entity.Checkout{
+			Items: []*entity.CheckoutItem{
+				{
+					Product:       &entity.Product{ID: 1, Serial: "120P90", Name: "Google Home", Price: 4999, UpdatedAt: dayCreated},
+					Quantity:      1,
+					SubTotalPrice: 4999,
+				},
+			},
+			CartID: 3,
+		})
+		assert.Equal(t, entity.NewError(entity.CartCheckedOut, http.StatusBadRequest), err)
+		assert.Nil(t, order)
+		assert.Nil(t, mock.ExpectationsWereMet())
+	})
+}
+
+func Test_CreateProduct(t *testing.T) {
+	// mock db
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("error: %s", err.Error())
+	}
+	defer db.Close()
+
+	// init repo
+	repo, err := initRepo(db, mock)
+	if err != nil {
+		t.Errorf("error initRepo: %s", err.Error())
+		return
+	}
+
+	t.Run("positive, create product quantity in the same transaction", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectExec(regexp.QuoteMeta("INSERT INTO `product` (`serial`,`name`,`price`,`tax_category`,`category`,`updated_at`,`deleted_at`) VALUES (?,?,?,?,?,?,?)")).
+			WithArgs("120P91", "Google Home Mini", "29.99", "standard", "", AnyTime{}, nil).
+			WillReturnResult(sqlmock.NewResult(5, 1))
+		mock.ExpectExec(regexp.QuoteMeta("INSERT INTO `product_quantity` (`product_id`,`quantity`,`reserved`,`updated_at`) VALUES (?,?,?,?)")).
+			WithArgs(5, 10, 0, AnyTime{}).
+			WillReturnResult(sqlmock.NewResult(5, 1))
+		mock.ExpectExec(regexp.QuoteMeta("INSERT INTO `stock_movement` (`product_id`,`delta`,`balance`,`reason`,`reference`,`created_at`) VALUES (?,?,?,?,?,?)")).
+			WithArgs(5, 10, 10, entity.StockInitial, "", AnyTime{}).
+			WillReturnResult(sqlmock.NewResult(1, 1))
+		mock.ExpectCommit()
+
+		product := &entity.Product{Serial: "120P91", Name: "Google Home Mini", Price: 2999, TaxCategory: "standard"}
+		err := repo.CreateProduct(product, 10)
+		assert.Nil(t, err)
+		assert.Nil(t, mock.ExpectationsWereMet())
+		assert.Equal(t, int64(5), product.ID)
+	})
+
+	t.Run("negative, rollback when failed to create product quantity", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectExec(regexp.QuoteMeta("INSERT INTO `product`")).
+			WillReturnResult(sqlmock.NewResult(6, 1))
+		mock.ExpectExec(regexp.QuoteMeta("INSERT INTO `product_quantity`")).
+			WillReturnError(errors.New("connection lost"))
+		mock.ExpectRollback()
+
+		product := &entity.Product{Serial: "120P92", Name: "Google Nest", Price: 9999, TaxCategory: "standard"}
+		err := repo.CreateProduct(product, 10)
 		assert.NotNil(t, err)
+		assert.Nil(t, mock.ExpectationsWereMet())
+	})
+}
+
+func Test_ManageProduct(t *testing.T) {
+	// mock db
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("error: %s", err.Error())
+	}
+	defer db.Close()
+
+	// init repo
+	repo, err := initRepo(db, mock)
+	if err != nil {
+		t.Errorf("error initRepo: %s", err.Error())
+		return
+	}
+	dayCreated, _ := time.Parse("2006-01-02", "2023-05-16")
+
+	t.Run("get products", func(t *testing.T) {
+		mock.
+			ExpectQuery(regexp.QuoteMeta("SELECT count(*) FROM `product` WHERE `product`.`deleted_at` IS NULL")).
+			WillReturnRows(sqlmock.NewRows([]string{"count(*)"}).AddRow(4))
+		rows := sqlmock.
+			NewRows([]string{"id", "serial", "name", "price", "tax_category", "updated_at", "deleted_at"}).
+			AddRow(3, "A304SD", "Alexa Speaker", "109.50", "standard", dayCreated, nil)
+		mock.
+			ExpectQuery(regexp.QuoteMeta("SELECT * FROM `product` WHERE `product`.`deleted_at` IS NULL ORDER BY id asc LIMIT ? OFFSET ?")).
+			WithArgs(2, 2).
+			WillReturnRows(rows)
+
+		resp, total, err := repo.GetProducts(&entity.ProductFilter{Page: 2, Limit: 2})
+		assert.Nil(t, err)
+		assert.Equal(t, int64(4), total)
+		assert.Equal(t, []*entity.Product{
+			{ID: 3, Serial: "A304SD", Name: "Alexa Speaker", Price: 10950, TaxCategory: "standard", UpdatedAt: dayCreated},
+		}, resp)
+	})
+
+	t.Run("count serial including deleted product", func(t *testing.T) {
+		mock.
+			ExpectQuery(regexp.QuoteMeta("SELECT count(*) FROM `product` WHERE serial = ? AND id <> ?")).
+			WithArgs("120P90", 1).
+			WillReturnRows(sqlmock.NewRows([]string{"count(*)"}).AddRow(0))
+
+		total, err := repo.CountProductBySerial("120P90", 1)
+		assert.Nil(t, err)
+		assert.Equal(t, int64(0), total)
+	})
+
+	t.Run("soft delete product", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectExec(regexp.QuoteMeta("UPDATE `product` SET `deleted_at`=? WHERE `product`.`id` = ? AND `product`.`deleted_at` IS NULL")).
+			WithArgs(AnyTime{}, 1).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectCommit()
+
+		err := repo.DeleteProduct(1)
+		assert.Nil(t, err)
+		assert.Nil(t, mock.ExpectationsWereMet())
+	})
+}
+
+func Test_AdjustQuantity(t *testing.T) {
+	// mock db
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("error: %s", err.Error())
+	}
+	defer db.Close()
+
+	// init repo
+	repo, err := initRepo(db, mock)
+	if err != nil {
+		t.Errorf("error initRepo: %s", err.Error())
+		return
+	}
+	dayCreated, _ := time.Parse("2006-01-02", "2023-05-16")
+
+	t.Run("positive, restock", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.
+			ExpectQuery(regexp.QuoteMeta("SELECT * FROM `product_quantity` WHERE product_id in (?) FOR UPDATE")).
+			WithArgs(4).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "product_id", "quantity", "updated_at"}).AddRow(4, 4, 2, dayCreated))
+		mock.ExpectExec(regexp.QuoteMeta("UPDATE `product_quantity` SET `product_id`=?,`quantity`=?,`reserved`=?,`updated_at`=? WHERE `id` = ?")).
+			WithArgs(4, 12, 0, AnyTime{}, 4).
+			WillReturnResult(sqlmock.NewResult(4, 1))
+		mock.ExpectExec(regexp.QuoteMeta("INSERT INTO `stock_movement` (`product_id`,`delta`,`balance`,`reason`,`reference`,`created_at`) VALUES (?,?,?,?,?,?)")).
+			WithArgs(4, 10, 12, entity.StockRestock, "admin", AnyTime{}).
+			WillReturnResult(sqlmock.NewResult(3, 1))
+		mock.ExpectCommit()
+
+		movement := &entity.StockMovement{ProductID: 4, Delta: 10, Reason: entity.StockRestock, Reference: "admin"}
+		err := repo.AdjustQuantity(movement)
+		assert.Nil(t, err)
+		assert.Nil(t, mock.ExpectationsWereMet())
+		assert.Equal(t, int64(3), movement.ID)
+		assert.Equal(t, 12, movement.Balance)
+	})
+
+	t.Run("negative, balance below zero", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.
+			ExpectQuery(regexp.QuoteMeta("SELECT * FROM `product_quantity` WHERE product_id in (?) FOR UPDATE")).
+			WithArgs(4).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "product_id", "quantity", "updated_at"}).AddRow(4, 4, 2, dayCreated))
+		mock.ExpectRollback()
+
+		movement := &entity.StockMovement{ProductID: 4, Delta: -3, Reason: entity.StockCorrection, Reference: "admin"}
+		err := repo.AdjustQuantity(movement)
+		assert.Equal(t, entity.NewError("adjustment exceeds existing quantity, only 2 items remaining", http.StatusBadRequest), err)
+		assert.Nil(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("negative, balance below reserved quantity", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.
+			ExpectQuery(regexp.QuoteMeta("SELECT * FROM `product_quantity` WHERE product_id in (?) FOR UPDATE")).
+			WithArgs(4).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "product_id", "quantity", "reserved", "updated_at"}).AddRow(4, 4, 5, 3, dayCreated))
+		mock.ExpectRollback()
+
+		movement := &entity.StockMovement{ProductID: 4, Delta: -3, Reason: entity.StockCorrection, Reference: "admin"}
+		err := repo.AdjustQuantity(movement)
+		assert.Equal(t, entity.NewError("adjustment exceeds existing quantity, only 2 items remaining", http.StatusBadRequest), err)
+		assert.Nil(t, mock.ExpectationsWereMet())
+	})
+}
+
+func Test_GetStockMovements(t *testing.T) {
+	// mock db
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("error: %s", err.Error())
+	}
+	defer db.Close()
+
+	// init repo
+	repo, err := initRepo(db, mock)
+	if err != nil {
+		t.Errorf("error initRepo: %s", err.Error())
+		return
+	}
+	dayCreated, _ := time.Parse("2006-01-02", "2023-05-16")
+
+	t.Run("positive", func(t *testing.T) {
+		mock.
+			ExpectQuery(regexp.QuoteMeta("SELECT count(*) FROM `stock_movement` WHERE product_id = ?")).
+			WithArgs(4).
+			WillReturnRows(sqlmock.NewRows([]string{"count(*)"}).AddRow(2))
+		rows := sqlmock.
+			NewRows([]string{"id", "product_id", "delta", "balance", "reason", "reference", "created_at"}).
+			AddRow(2, 4, -1, 1, "sale", "7", dayCreated).
+			AddRow(1, 4, 2, 2, "initial", "", dayCreated)
+		mock.
+			ExpectQuery(regexp.QuoteMeta("SELECT * FROM `stock_movement` WHERE product_id = ? ORDER BY id desc LIMIT ?")).
+			WithArgs(4, 20).
+			WillReturnRows(rows)
+
+		resp, total, err := repo.GetStockMovements(&entity.StockMovementFilter{ProductID: 4, Page: 1, Limit: 20})
+		assert.Nil(t, err)
+		assert.Equal(t, int64(2), total)
+		assert.Equal(t, []*entity.StockMovement{
+			{ID: 2, ProductID: 4, Delta: -1, Balance: 1, Reason: entity.StockSale, Reference: "7", CreatedAt: dayCreated},
+			{ID: 1, ProductID: 4, Delta: 2, Balance: 2, Reason: entity.StockInitial, CreatedAt: dayCreated},
+		}, resp)
+	})
+}
+
+func Test_ReserveStock(t *testing.T) {
+	// mock db
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("error: %s", err.Error())
+	}
+	defer db.Close()
+
+	// init repo
+	repo, err := initRepo(db, mock)
+	if err != nil {
+		t.Errorf("error initRepo: %s", err.Error())
+		return
+	}
+	dayCreated, _ := time.Parse("2006-01-02", "2023-05-16")
+	product := &entity.Product{ID: 4, Serial: "234234", Name: "Raspberry Pi B", Price: 3000, UpdatedAt: dayCreated}
+
+	t.Run("positive, hold available quantity", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.
+			ExpectQuery(regexp.QuoteMeta("SELECT * FROM `product_quantity` WHERE product_id in (?) FOR UPDATE")).
+			WithArgs(4).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "product_id", "quantity", "reserved", "updated_at"}).AddRow(4, 4, 5, 1, dayCreated))
+		mock.ExpectExec(regexp.QuoteMeta("UPDATE `product_quantity` SET `product_id`=?,`quantity`=?,`reserved`=?,`updated_at`=? WHERE `id` = ?")).
+			WithArgs(4, 5, 3, AnyTime{}, 4).
+			WillReturnResult(sqlmock.NewResult(1, 1))
+		mock.ExpectExec(regexp.QuoteMeta("INSERT INTO `reservation` (`status`,`expires_at`,`created_at`) VALUES (?,?,?)")).
+			WithArgs(entity.ReservationActive, AnyTime{}, AnyTime{}).
+			WillReturnResult(sqlmock.NewResult(5, 1))
+		mock.ExpectExec(regexp.QuoteMeta("INSERT INTO `reservation_item` (`reservation_id`,`product_id`,`quantity`) VALUES (?,?,?)")).
+			WithArgs(5, 4, 2).
+			WillReturnResult(sqlmock.NewResult(1, 1))
+		mock.ExpectCommit()
+
+		reservation := &entity.Reservation{
+			ExpiresAt: dayCreated.Add(15 * time.Minute),
+			Items:     []*entity.ReservationItem{{ProductID: 4, Quantity: 2, Product: product}},
+		}
+		err := repo.ReserveStock(reservation)
+		assert.Nil(t, err)
+		assert.Nil(t, mock.ExpectationsWereMet())
+		assert.Equal(t, int64(5), reservation.ID)
+		assert.Equal(t, entity.ReservationActive, reservation.Status)
+		assert.Equal(t, int64(5), reservation.Items[0].ReservationID)
+	})
+
+	t.Run("negative, reserved quantity is not available", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.
+			ExpectQuery(regexp.QuoteMeta("SELECT * FROM `product_quantity` WHERE product_id in (?) FOR UPDATE")).
+			WithArgs(4).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "product_id", "quantity", "reserved", "updated_at"}).AddRow(4, 4, 5, 4, dayCreated))
+		mock.ExpectRollback()
+
+		reservation := &entity.Reservation{
+			ExpiresAt: dayCreated.Add(15 * time.Minute),
+			Items:     []*entity.ReservationItem{{ProductID: 4, Quantity: 2, Product: product}},
+		}
+		err := repo.ReserveStock(reservation)
+		assert.Equal(t, entity.NewOutOfStockError("reserved item Raspberry Pi B(234234) exceeds available quantity, only 1 items available", []*entity.OutOfStockItem{
+			{Serial: "234234", Requested: 2, Available: 1},
+		}), err)
+		assert.Equal(t, entity.CodeOutOfStock, err.(entity.Err).GetErrorCode())
+		assert.Nil(t, mock.ExpectationsWereMet())
+	})
+}
+
+func Test_SubmitCheckoutWithReservation(t *testing.T) {
+	// mock db
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("error: %s", err.Error())
+	}
+	defer db.Close()
+
+	// init repo
+	repo, err := initRepo(db, mock)
+	if err != nil {
+		t.Errorf("error initRepo: %s", err.Error())
+		return
+	}
+	dayCreated, _ := time.Parse("2006-01-02", "2023-05-16")
+	product := &entity.Product{ID: 4, Serial: "234234", Name: "Raspberry Pi B", Price: 3000, UpdatedAt: dayCreated}
+	checkout := &entity.Checkout{
+		Items:         []*entity.CheckoutItem{{Product: product, Quantity: 1, SubTotalPrice: 3000}},
+		TotalItem:     1,
+		TotalPrice:    3000,
+		GrandTotal:    3000,
+		Currency:      "USD",
+		ReservationID: 5,
+		SubmittedAt:   dayCreated.Add(10 * time.Minute),
+	}
+
+	t.Run("positive, convert reserved quantity into sale", func(t *testing.T) {
+		mock.ExpectBegin()
+
+		// lock reservation
+		mock.
+			ExpectQuery(regexp.QuoteMeta("SELECT * FROM `reservation` WHERE id = ? LIMIT ? FOR UPDATE")).
+			WithArgs(5, 1).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "status", "expires_at", "created_at"}).
+				AddRow(5, "active", dayCreated.Add(15*time.Minute), dayCreated))
+		mock.
+			ExpectQuery(regexp.QuoteMeta("SELECT * FROM `reservation_item` WHERE `reservation_item`.`reservation_id` = ?")).
+			WithArgs(5).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "reservation_id", "product_id", "quantity"}).AddRow(1, 5, 4, 2))
+
+		// sell 1 item and release all 2 reserved items
+		mock.ExpectExec(regexp.QuoteMeta("UPDATE `product_quantity` SET `quantity`=quantity - ?,`reserved`=reserved - ?,`updated_at`=? WHERE product_id = ?")).
+			WithArgs(1, 2, AnyTime{}, 4).
+			WillReturnResult(sqlmock.NewResult(1, 1))
+		mock.ExpectExec(regexp.QuoteMeta("UPDATE `reservation` SET `status`=? WHERE `id` = ?")).
+			WithArgs(entity.ReservationConsumed, 5).
+			WillReturnResult(sqlmock.NewResult(1, 1))
+		mock.
+			ExpectQuery(regexp.QuoteMeta("SELECT * FROM `product_quantity` WHERE product_id in (?)")).
+			WithArgs(4).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "product_id", "quantity", "reserved", "updated_at"}).AddRow(4, 4, 4, 0, dayCreated))
+
+		// write order and stock movement
+		mock.ExpectExec(regexp.QuoteMeta("INSERT INTO `order`")).
+			WillReturnResult(sqlmock.NewResult(8, 1))
+		mock.ExpectExec(regexp.QuoteMeta("INSERT INTO `order_item`")).
+			WillReturnResult(sqlmock.NewResult(1, 1))
+		mock.ExpectExec(regexp.QuoteMeta("INSERT INTO `stock_movement` (`product_id`,`delta`,`balance`,`reason`,`reference`,`created_at`) VALUES (?,?,?,?,?,?)")).
+			WithArgs(4, -1, 4, entity.StockSale, "8", AnyTime{}).
+			WillReturnResult(sqlmock.NewResult(1, 1))
+		mock.ExpectCommit()
+
+		order, err := repo.SubmitCheckout(checkout)
+		assert.Nil(t, err)
+		assert.Nil(t, mock.ExpectationsWereMet())
+		assert.Equal(t, int64(8), order.ID)
+	})
+
+	t.Run("negative, reservation is expired", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.
+			ExpectQuery(regexp.QuoteMeta("SELECT * FROM `reservation` WHERE id = ? LIMIT ? FOR UPDATE")).
+			WithArgs(5, 1).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "status", "expires_at", "created_at"}).
+				AddRow(5, "active", dayCreated.Add(5*time.Minute), dayCreated))
+		mock.
+			ExpectQuery(regexp.QuoteMeta("SELECT * FROM `reservation_item` WHERE `reservation_item`.`reservation_id` = ?")).
+			WithArgs(5).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "reservation_id", "product_id", "quantity"}).AddRow(1, 5, 4, 2))
+		mock.ExpectRollback()
+
+		order, err := repo.SubmitCheckout(checkout)
+		assert.Nil(t, order)
+		assert.Equal(t, entity.NewError(entity.ReservationNotActive, http.StatusBadRequest), err)
+		assert.Nil(t, mock.ExpectationsWereMet())
+	})
+}
+
+func Test_ReleaseExpiredReservations(t *testing.T) {
+	// mock db
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("error: %s", err.Error())
+	}
+	defer db.Close()
+
+	// init repo
+	repo, err := initRepo(db, mock)
+	if err != nil {
+		t.Errorf("error initRepo: %s", err.Error())
+		return
+	}
+	dayCreated, _ := time.Parse("2006-01-02", "2023-05-16")
+	now := dayCreated.Add(time.Hour)
+
+	t.Run("positive", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.
+			ExpectQuery(regexp.QuoteMeta("SELECT * FROM `reservation` WHERE status = ? AND expires_at <= ? FOR UPDATE")).
+			WithArgs(entity.ReservationActive, now).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "status", "expires_at", "created_at"}).
+				AddRow(5, "active", dayCreated.Add(15*time.Minute), dayCreated))
+		mock.
+			ExpectQuery(regexp.QuoteMeta("SELECT * FROM `reservation_item` WHERE `reservation_item`.`reservation_id` = ?")).
+			WithArgs(5).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "reservation_id", "product_id", "quantity"}).
+				AddRow(1, 5, 2, 1).
+				AddRow(2, 5, 4, 1))
+		mock.ExpectExec(regexp.QuoteMeta("UPDATE `product_quantity` SET `reserved`=reserved - ?,`updated_at`=? WHERE product_id = ?")).
+			WithArgs(1, AnyTime{}, 2).
+			WillReturnResult(sqlmock.NewResult(1, 1))
+		mock.ExpectExec(regexp.QuoteMeta("UPDATE `product_quantity` SET `reserved`=reserved - ?,`updated_at`=? WHERE product_id = ?")).
+			WithArgs(1, AnyTime{}, 4).
+			WillReturnResult(sqlmock.NewResult(1, 1))
+		mock.ExpectExec(regexp.QuoteMeta("UPDATE `reservation` SET `status`=? WHERE `id` = ?")).
+			WithArgs(entity.ReservationReleased, 5).
+			WillReturnResult(sqlmock.NewResult(1, 1))
+		mock.ExpectCommit()
+
+		total, err := repo.ReleaseExpiredReservations(now)
+		assert.Nil(t, err)
+		assert.Nil(t, mock.ExpectationsWereMet())
+		assert.Equal(t, int64(1), total)
+	})
+}
+
+func Test_SubmitCheckoutWithCoupon(t *testing.T) {
+	// mock db
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("error: %s", err.Error())
+	}
+	defer db.Close()
+
+	// init repo
+	repo, err := initRepo(db, mock)
+	if err != nil {
+		t.Errorf("error initRepo: %s", err.Error())
+		return
+	}
+	dayCreated, _ := time.Parse("2006-01-02", "2023-05-16")
+	product := &entity.Product{ID: 3, Serial: "A304SD", Name: "Alexa Speaker", Price: 10950, UpdatedAt: dayCreated}
+	coupon := &entity.Coupon{ID: 2, Code: "WELCOME10", Type: entity.CouponPercent, Percent: 10, Currency: "USD", UsageLimit: 100, PerCustomerLimit: 1}
+	// 10% coupon of 1 Alexa Speaker
+	newCheckout := func() *entity.Checkout {
+		return &entity.Checkout{
+			Items: []*entity.CheckoutItem{
+				{Product: product, Quantity: 1, SubTotalPrice: 9855, CouponDiscount: 1095},
+			},
+			TotalItem:      1,
+			TotalPrice:     9855,
+			TotalDiscount:  1095,
+			GrandTotal:     9855,
+			Currency:       "USD",
+			Coupon:         coupon,
+			CouponDiscount: 1095,
+			CustomerID:     "customer-1",
+		}
+	}
+	expectOrder := func() {
+		mock.
+			ExpectQuery(regexp.QuoteMeta("SELECT * FROM `product_quantity` WHERE product_id in (?) FOR UPDATE")).
+			WithArgs(3).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "product_id", "quantity", "updated_at"}).AddRow(3, 3, 10, dayCreated))
+		mock.ExpectExec(regexp.QuoteMeta("UPDATE `product_quantity`")).
+			WillReturnResult(sqlmock.NewResult(3, 1))
+		mock.ExpectExec(regexp.QuoteMeta("INSERT INTO `order`")).
+			WithArgs(1, "98.55", "10.95", "0.00", "98.55", "USD", "[]", "customer-1", 2, "WELCOME10", "10.95", AnyTime{}).
+			WillReturnResult(sqlmock.NewResult(9, 1))
+		mock.ExpectExec(regexp.QuoteMeta("INSERT INTO `order_item`")).
+			WithArgs(9, 3, 1, "109.50", "98.55", "0.00", "[]", "10.95").
+			WillReturnResult(sqlmock.NewResult(1, 1))
+	}
+
+	t.Run("positive, redemption is counted in checkout transaction", func(t *testing.T) {
+		mock.ExpectBegin()
+		expectOrder()
+
+		// lock coupon, then count redemption of customer
+		mock.
+			ExpectQuery(regexp.QuoteMeta("SELECT * FROM `coupon` WHERE id = ? AND `coupon`.`deleted_at` IS NULL LIMIT ? FOR UPDATE")).
+			WithArgs(2, 1).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "code", "type", "percent", "currency", "usage_limit", "per_customer_limit", "used_count"}).
+				AddRow(2, "WELCOME10", 2, 10, "USD", 100, 1, 99))
+		mock.
+			ExpectQuery(regexp.QuoteMeta("SELECT count(*) FROM `coupon_redemption` WHERE coupon_id = ? AND customer_id = ?")).
+			WithArgs(2, "customer-1").
+			WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(0))
+		mock.ExpectExec(regexp.QuoteMeta("UPDATE `coupon` SET `used_count`=used_count + ?,`updated_at`=? WHERE `coupon`.`deleted_at` IS NULL AND `id` = ?")).
+			WithArgs(1, AnyTime{}, 2).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectExec(regexp.QuoteMeta("INSERT INTO `coupon_redemption` (`coupon_id`,`order_id`,`customer_id`,`created_at`) VALUES (?,?,?,?)")).
+			WithArgs(2, 9, "customer-1", AnyTime{}).
+			WillReturnResult(sqlmock.NewResult(1, 1))
+
+		mock.ExpectExec(regexp.QuoteMeta("INSERT INTO `stock_movement`")).
+			WithArgs(3, -1, 9, entity.StockSale, "9", AnyTime{}).
+			WillReturnResult(sqlmock.NewResult(1, 1))
+		mock.ExpectCommit()
+
+		order, err := repo.SubmitCheckout(newCheckout())
+		assert.Nil(t, err)
+		assert.Nil(t, mock.ExpectationsWereMet())
+		assert.Equal(t, int64(9), order.ID)
+		assert.Equal(t, "WELCOME10", order.CouponCode)
+		assert.Equal(t, entity.Money(1095), order.Items[0].CouponDiscount)
+	})
+
+	t.Run("negative, usage limit is reached by other order", func(t *testing.T) {
+		mock.ExpectBegin()
+		expectOrder()
+		mock.
+			ExpectQuery(regexp.QuoteMeta("SELECT * FROM `coupon` WHERE id = ? AND `coupon`.`deleted_at` IS NULL LIMIT ? FOR UPDATE")).
+			WithArgs(2, 1).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "code", "type", "percent", "currency", "usage_limit", "per_customer_limit", "used_count"}).
+				AddRow(2, "WELCOME10", 2, 10, "USD", 100, 1, 100))
+		mock.ExpectRollback()
+
+		order, err := repo.SubmitCheckout(newCheckout())
+		assert.Nil(t, order)
+		assert.Equal(t, entity.NewError(entity.CouponUsageLimitReached, http.StatusBadRequest), err)
+		assert.Nil(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("negative, customer already redeemed the coupon", func(t *testing.T) {
+		mock.ExpectBegin()
+		expectOrder()
+		mock.
+			ExpectQuery(regexp.QuoteMeta("SELECT * FROM `coupon` WHERE id = ? AND `coupon`.`deleted_at` IS NULL LIMIT ? FOR UPDATE")).
+			WithArgs(2, 1).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "code", "type", "percent", "currency", "usage_limit", "per_customer_limit", "used_count"}).
+				AddRow(2, "WELCOME10", 2, 10, "USD", 100, 1, 50))
+		mock.
+			ExpectQuery(regexp.QuoteMeta("SELECT count(*) FROM `coupon_redemption` WHERE coupon_id = ? AND customer_id = ?")).
+			WithArgs(2, "customer-1").
+			WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+		mock.ExpectRollback()
+
+		order, err := repo.SubmitCheckout(newCheckout())
+		assert.Nil(t, order)
+		assert.Equal(t, entity.NewError(entity.CouponCustomerLimitReached, http.StatusBadRequest), err)
+		assert.Nil(t, mock.ExpectationsWereMet())
 	})
 }
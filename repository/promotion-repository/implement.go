@@ -1,9 +1,13 @@
 package promotionrepository
 
 import (
+	"strings"
+	"time"
+
 	"github.com/gendutski/be-candidate-home-test/core/entity"
 	"github.com/gendutski/be-candidate-home-test/core/repository"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type repo struct {
@@ -14,7 +18,7 @@ func New(db *gorm.DB) repository.PromotionRepo {
 	return &repo{db}
 }
 
-func (r *repo) GetPromotionByProducts(products []*entity.Product) (map[int64][]*entity.Promotion, error) {
+func (r *repo) GetPromotionByProducts(products []*entity.Product, at time.Time) (map[int64][]*entity.Promotion, error) {
 	// pluck product id
 	var ids []int64
 	for _, p := range products {
@@ -23,16 +27,158 @@ func (r *repo) GetPromotionByProducts(products []*entity.Product) (map[int64][]*
 
 	// get promotions by product id
 	var promotions []*entity.Promotion
-	err := r.db.Where("product_id in (?)", ids).Order("product_id asc, type asc").Find(&promotions).Error
+	err := r.db.Where("product_id in (?)", ids).Preload("Tiers", sortTiers).Order("product_id asc, type asc").Find(&promotions).Error
 	if err != nil {
 		return nil, err
 	}
 
-	// maping product
+	// maping product, skip promotion outside its validity window
 	result := map[int64][]*entity.Promotion{}
 	for _, promo := range promotions {
+		if !promo.ActiveAt(at) {
+			continue
+		}
 		result[promo.ProductID] = append(result[promo.ProductID], promo)
 	}
 
 	return result, nil
 }
+
+func (r *repo) GetCartPromotions(at time.Time) ([]*entity.Promotion, error) {
+	var promotions []*entity.Promotion
+	err := r.db.Where("type in (?)", []entity.PromotionType{entity.CategoryBundle, entity.CartBonusItem, entity.CartDiscountInPercent, entity.ProductBundle}).
+		Preload("Items", sortItems).
+		Order("type asc, id asc").
+		Find(&promotions).
+		Error
+	if err != nil {
+		return nil, err
+	}
+
+	// skip promotion outside its validity window
+	var result []*entity.Promotion
+	for _, promo := range promotions {
+		if promo.ActiveAt(at) {
+			result = append(result, promo)
+		}
+	}
+	return result, nil
+}
+
+func (r *repo) GetPromotionByID(id int64) (*entity.Promotion, error) {
+	var result []*entity.Promotion
+	err := r.db.Where("id = ?", id).Preload("Items", sortItems).Preload("Tiers", sortTiers).Limit(1).Find(&result).Error
+	if err != nil {
+		return nil, err
+	}
+	if len(result) == 0 {
+		return nil, nil
+	}
+	return result[0], nil
+}
+
+func (r *repo) GetPromotions() ([]*entity.Promotion, error) {
+	var result []*entity.Promotion
+	err := r.db.Preload("Items", sortItems).Preload("Tiers", sortTiers).Order("id asc").Find(&result).Error
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// count promotions of the product, including product bundles with the product
+func (r *repo) CountProductPromotions(productID int64, exceptID int64) (int64, error) {
+	var total int64
+	bundles := r.db.Model(&entity.PromotionItem{}).Select("promotion_id").Where("product_id = ?", productID)
+	err := r.db.Model(&entity.Promotion{}).
+		Where("(product_id = ? OR id in (?)) AND id <> ?", productID, bundles, exceptID).
+		Count(&total).Error
+	return total, err
+}
+
+func (r *repo) CountBonusPromotions(promoProductID int64, exceptID int64) (int64, error) {
+	var total int64
+	err := r.db.Model(&entity.Promotion{}).
+		Where("type in (?) AND promo_product_id = ? AND id <> ?", []entity.PromotionType{entity.BonusItem, entity.CartBonusItem}, promoProductID, exceptID).
+		Count(&total).Error
+	return total, err
+}
+
+func (r *repo) CreatePromotion(promo *entity.Promotion) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Omit(clause.Associations).Create(promo).Error; err != nil {
+			return err
+		}
+		if err := createItems(promo, tx); err != nil {
+			return err
+		}
+		return createTiers(promo, tx)
+	})
+}
+
+func (r *repo) UpdatePromotion(promo *entity.Promotion) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Omit(clause.Associations).Save(promo).Error; err != nil {
+			return err
+		}
+		// replace items and tiers
+		if err := tx.Where("promotion_id = ?", promo.ID).Delete(&entity.PromotionItem{}).Error; err != nil {
+			return err
+		}
+		if err := createItems(promo, tx); err != nil {
+			return err
+		}
+		if err := tx.Where("promotion_id = ?", promo.ID).Delete(&entity.PromotionTier{}).Error; err != nil {
+			return err
+		}
+		return createTiers(promo, tx)
+	})
+}
+
+// items of product bundle, ordered by id
+func sortItems(db *gorm.DB) *gorm.DB {
+	return db.Order("id asc")
+}
+
+// tiers of tiered price, ordered by min quantity
+func sortTiers(db *gorm.DB) *gorm.DB {
+	return db.Order("min_quantity asc")
+}
+
+func createTiers(promo *entity.Promotion, tx *gorm.DB) error {
+	if len(promo.Tiers) == 0 {
+		return nil
+	}
+	for _, tier := range promo.Tiers {
+		tier.ID = 0
+		tier.PromotionID = promo.ID
+	}
+	return tx.Create(&promo.Tiers).Error
+}
+
+func createItems(promo *entity.Promotion, tx *gorm.DB) error {
+	if len(promo.Items) == 0 {
+		return nil
+	}
+	for _, item := range promo.Items {
+		item.ID = 0
+		item.PromotionID = promo.ID
+	}
+	return tx.Create(&promo.Items).Error
+}
+
+func (r *repo) DeletePromotion(id int64) error {
+	return r.db.Delete(&entity.Promotion{}, id).Error
+}
+
+func (r *repo) GetCouponByCode(code string) (*entity.Coupon, error) {
+	var result []*entity.Coupon
+	err := r.db.Where("code = ?", strings.ToUpper(code)).Limit(1).Find(&result).Error
+	if err != nil {
+		return nil, err
+	}
+	if len(result) == 0 {
+		return nil, nil
+	}
+	return result[0], nil
+}
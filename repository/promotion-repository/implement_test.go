@@ -63,21 +63,360 @@ func Test_GetPromotionByProducts(t *testing.T) {
 		rows := sqlmock.
 			NewRows([]string{"id", "type", "product_id", "match_quantity", "promo_value", "promo_product_id", "updated_at", "deleted_at"}).
 			AddRow(1, 1, 2, 1, 1, 4, dayCreated, nil).
-			AddRow(3, 3, 3, 3, 10, 0, dayCreated, nil)
+			AddRow(3, 3, 3, 3, 10, 0, dayCreated, nil).
+			AddRow(9, 9, 3, 0, 0, 0, dayCreated, nil)
 
 		mock.
 			ExpectQuery(regexp.QuoteMeta("SELECT * FROM `promotion` WHERE product_id in (?,?) AND `promotion`.`deleted_at` IS NULL ORDER BY product_id asc, type asc")).
 			WithArgs(int64(2), int64(3)).
 			WillReturnRows(rows)
+		// tiers of tiered price
+		mock.
+			ExpectQuery(regexp.QuoteMeta("SELECT * FROM `promotion_tier` WHERE `promotion_tier`.`promotion_id` IN (?,?,?) ORDER BY min_quantity asc")).
+			WithArgs(1, 3, 9).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "promotion_id", "min_quantity", "percent", "unit_price"}).
+				AddRow(1, 9, 10, 5, "0.00").
+				AddRow(2, 9, 50, 12, "0.00"))
 
 		resp, err := repo.GetPromotionByProducts([]*entity.Product{
-			{ID: 2, Serial: "43N23P", Name: "MacBook Pro", Price: 5399.99, UpdatedAt: dayCreated},
-			{ID: 3, Serial: "A304SD", Name: "Alexa Speaker", Price: 49.99, UpdatedAt: dayCreated},
-		})
+			{ID: 2, Serial: "43N23P", Name: "MacBook Pro", Price: 539999, UpdatedAt: dayCreated},
+			{ID: 3, Serial: "A304SD", Name: "Alexa Speaker", Price: 4999, UpdatedAt: dayCreated},
+		}, dayCreated)
 		assert.Nil(t, err)
 		assert.Equal(t, map[int64][]*entity.Promotion{
-			2: {{ID: 1, Type: 1, ProductID: 2, MatchQuantity: 1, PromoValue: 1, PromoProductID: 4, UpdatedAt: dayCreated}},
-			3: {{ID: 3, Type: 3, ProductID: 3, MatchQuantity: 3, PromoValue: 10, PromoProductID: 0, UpdatedAt: dayCreated}},
+			2: {{ID: 1, Type: 1, ProductID: 2, MatchQuantity: 1, PromoValue: 1, PromoProductID: 4, UpdatedAt: dayCreated, Tiers: []*entity.PromotionTier{}}},
+			3: {
+				{ID: 3, Type: 3, ProductID: 3, MatchQuantity: 3, PromoValue: 10, PromoProductID: 0, UpdatedAt: dayCreated, Tiers: []*entity.PromotionTier{}},
+				{ID: 9, Type: entity.TieredPrice, ProductID: 3, UpdatedAt: dayCreated, Tiers: []*entity.PromotionTier{
+					{ID: 1, PromotionID: 9, MinQuantity: 10, Percent: 5},
+					{ID: 2, PromotionID: 9, MinQuantity: 50, Percent: 12},
+				}},
+			},
+		}, resp)
+	})
+
+	t.Run("validity window boundary", func(t *testing.T) {
+		// weekend sale from saturday 00:00 until monday 00:00
+		startsAt := time.Date(2023, 5, 20, 0, 0, 0, 0, time.UTC)
+		endsAt := time.Date(2023, 5, 22, 0, 0, 0, 0, time.UTC)
+		weekendPromo := &entity.Promotion{ID: 4, Type: 3, ProductID: 3, MatchQuantity: 1, PromoValue: 20, StartsAt: &startsAt, EndsAt: &endsAt, UpdatedAt: dayCreated, Tiers: []*entity.PromotionTier{}}
+		products := []*entity.Product{
+			{ID: 3, Serial: "A304SD", Name: "Alexa Speaker", Price: 4999, UpdatedAt: dayCreated},
+		}
+
+		tests := []struct {
+			name   string
+			at     time.Time
+			expect map[int64][]*entity.Promotion
+		}{
+			{"before starts_at", startsAt.Add(-time.Nanosecond), map[int64][]*entity.Promotion{}},
+			{"at starts_at", startsAt, map[int64][]*entity.Promotion{3: {weekendPromo}}},
+			{"before ends_at", endsAt.Add(-time.Nanosecond), map[int64][]*entity.Promotion{3: {weekendPromo}}},
+			{"at ends_at", endsAt, map[int64][]*entity.Promotion{}},
+		}
+		for _, tt := range tests {
+			rows := sqlmock.
+				NewRows([]string{"id", "type", "product_id", "match_quantity", "promo_value", "promo_product_id", "starts_at", "ends_at", "updated_at", "deleted_at"}).
+				AddRow(4, 3, 3, 1, 20, 0, startsAt, endsAt, dayCreated, nil)
+			mock.
+				ExpectQuery(regexp.QuoteMeta("SELECT * FROM `promotion` WHERE product_id in (?) AND `promotion`.`deleted_at` IS NULL ORDER BY product_id asc, type asc")).
+				WithArgs(int64(3)).
+				WillReturnRows(rows)
+			mock.
+				ExpectQuery(regexp.QuoteMeta("SELECT * FROM `promotion_tier` WHERE `promotion_tier`.`promotion_id` = ? ORDER BY min_quantity asc")).
+				WithArgs(4).
+				WillReturnRows(sqlmock.NewRows([]string{"id"}))
+
+			resp, err := repo.GetPromotionByProducts(products, tt.at)
+			assert.Nil(t, err, tt.name)
+			assert.Equal(t, tt.expect, resp, tt.name)
+		}
+	})
+}
+
+func Test_GetCartPromotions(t *testing.T) {
+	// mock db
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("error: %s", err.Error())
+	}
+	defer db.Close()
+
+	// init repo
+	repo, err := initRepo(db, mock)
+	if err != nil {
+		t.Errorf("error initRepo: %s", err.Error())
+		return
+	}
+	dayCreated, _ := time.Parse("2006-01-02", "2023-05-16")
+	endsAt := time.Date(2023, 5, 20, 0, 0, 0, 0, time.UTC)
+
+	t.Run("positive, skip promotion outside its validity window", func(t *testing.T) {
+		rows := sqlmock.
+			NewRows([]string{"id", "type", "product_id", "match_quantity", "promo_value", "promo_product_id", "min_total", "promo_price", "category", "currency", "ends_at", "updated_at", "deleted_at"}).
+			AddRow(5, 5, 0, 2, 0, 0, "0.00", "150.00", "smart-home", "USD", nil, dayCreated, nil).
+			AddRow(6, 7, 0, 0, 5, 0, "500.00", "0.00", "", "USD", endsAt, dayCreated, nil).
+			AddRow(7, 7, 0, 0, 10, 0, "1000.00", "0.00", "", "USD", nil, dayCreated, nil).
+			AddRow(8, 8, 0, 0, 0, 0, "0.00", "140.00", "", "USD", nil, dayCreated, nil)
+		mock.
+			ExpectQuery(regexp.QuoteMeta("SELECT * FROM `promotion` WHERE type in (?,?,?,?) AND `promotion`.`deleted_at` IS NULL ORDER BY type asc, id asc")).
+			WithArgs(entity.CategoryBundle, entity.CartBonusItem, entity.CartDiscountInPercent, entity.ProductBundle).
+			WillReturnRows(rows)
+		// items of product bundle
+		mock.
+			ExpectQuery(regexp.QuoteMeta("SELECT * FROM `promotion_item` WHERE `promotion_item`.`promotion_id` IN (?,?,?,?) ORDER BY id asc")).
+			WithArgs(5, 6, 7, 8).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "promotion_id", "product_id", "quantity"}).
+				AddRow(1, 8, 1, 1).
+				AddRow(2, 8, 3, 1))
+
+		resp, err := repo.GetCartPromotions(endsAt)
+		assert.Nil(t, err)
+		assert.Equal(t, []*entity.Promotion{
+			{ID: 5, Type: entity.CategoryBundle, MatchQuantity: 2, PromoPrice: 15000, Category: "smart-home", Currency: "USD", UpdatedAt: dayCreated, Items: []*entity.PromotionItem{}},
+			{ID: 7, Type: entity.CartDiscountInPercent, PromoValue: 10, MinTotal: 100000, Currency: "USD", UpdatedAt: dayCreated, Items: []*entity.PromotionItem{}},
+			{ID: 8, Type: entity.ProductBundle, PromoPrice: 14000, Currency: "USD", UpdatedAt: dayCreated, Items: []*entity.PromotionItem{
+				{ID: 1, PromotionID: 8, ProductID: 1, Quantity: 1},
+				{ID: 2, PromotionID: 8, ProductID: 3, Quantity: 1},
+			}},
+		}, resp)
+	})
+}
+
+func Test_GetPromotionByID(t *testing.T) {
+	// mock db
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("error: %s", err.Error())
+	}
+	defer db.Close()
+
+	// init repo
+	repo, err := initRepo(db, mock)
+	if err != nil {
+		t.Errorf("error initRepo: %s", err.Error())
+		return
+	}
+	dayCreated, _ := time.Parse("2006-01-02", "2023-05-16")
+
+	t.Run("positive", func(t *testing.T) {
+		rows := sqlmock.
+			NewRows([]string{"id", "type", "product_id", "match_quantity", "promo_value", "promo_product_id", "priority", "exclusive", "stackable_with", "updated_at", "deleted_at"}).
+			AddRow(3, 3, 3, 3, 10, 0, 1, false, "[2]", dayCreated, nil)
+		mock.
+			ExpectQuery(regexp.QuoteMeta("SELECT * FROM `promotion` WHERE id = ? AND `promotion`.`deleted_at` IS NULL LIMIT ?")).
+			WithArgs(3, 1).
+			WillReturnRows(rows)
+		mock.
+			ExpectQuery(regexp.QuoteMeta("SELECT * FROM `promotion_item` WHERE `promotion_item`.`promotion_id` = ? ORDER BY id asc")).
+			WithArgs(3).
+			WillReturnRows(sqlmock.NewRows([]string{"id"}))
+		mock.
+			ExpectQuery(regexp.QuoteMeta("SELECT * FROM `promotion_tier` WHERE `promotion_tier`.`promotion_id` = ? ORDER BY min_quantity asc")).
+			WithArgs(3).
+			WillReturnRows(sqlmock.NewRows([]string{"id"}))
+
+		resp, err := repo.GetPromotionByID(3)
+		assert.Nil(t, err)
+		assert.Equal(t, &entity.Promotion{ID: 3, Type: 3, ProductID: 3, MatchQuantity: 3, PromoValue: 10, Priority: 1, StackableWith: entity.PromotionIDList{2}, UpdatedAt: dayCreated, Items: []*entity.PromotionItem{}, Tiers: []*entity.PromotionTier{}}, resp)
+	})
+
+	t.Run("negative, not found", func(t *testing.T) {
+		mock.
+			ExpectQuery(regexp.QuoteMeta("SELECT * FROM `promotion` WHERE id = ? AND `promotion`.`deleted_at` IS NULL LIMIT ?")).
+			WithArgs(8, 1).
+			WillReturnRows(sqlmock.NewRows([]string{"id"}))
+
+		resp, err := repo.GetPromotionByID(8)
+		assert.Nil(t, err)
+		assert.Nil(t, resp)
+	})
+}
+
+func Test_CountPromotions(t *testing.T) {
+	// mock db
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("error: %s", err.Error())
+	}
+	defer db.Close()
+
+	// init repo
+	repo, err := initRepo(db, mock)
+	if err != nil {
+		t.Errorf("error initRepo: %s", err.Error())
+		return
+	}
+
+	t.Run("count product promotions", func(t *testing.T) {
+		mock.
+			ExpectQuery(regexp.QuoteMeta("SELECT count(*) FROM `promotion` WHERE ((product_id = ? OR id in (SELECT `promotion_id` FROM `promotion_item` WHERE product_id = ?)) AND id <> ?) AND `promotion`.`deleted_at` IS NULL")).
+			WithArgs(4, 4, 0).
+			WillReturnRows(sqlmock.NewRows([]string{"count(*)"}).AddRow(1))
+
+		total, err := repo.CountProductPromotions(4, 0)
+		assert.Nil(t, err)
+		assert.Equal(t, int64(1), total)
+	})
+
+	t.Run("count bonus promotions", func(t *testing.T) {
+		mock.
+			ExpectQuery(regexp.QuoteMeta("SELECT count(*) FROM `promotion` WHERE (type in (?,?) AND promo_product_id = ? AND id <> ?) AND `promotion`.`deleted_at` IS NULL")).
+			WithArgs(entity.BonusItem, entity.CartBonusItem, 4, 1).
+			WillReturnRows(sqlmock.NewRows([]string{"count(*)"}).AddRow(0))
+
+		total, err := repo.CountBonusPromotions(4, 1)
+		assert.Nil(t, err)
+		assert.Equal(t, int64(0), total)
+	})
+}
+
+func Test_WritePromotion(t *testing.T) {
+	// mock db
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("error: %s", err.Error())
+	}
+	defer db.Close()
+
+	// init repo
+	repo, err := initRepo(db, mock)
+	if err != nil {
+		t.Errorf("error initRepo: %s", err.Error())
+		return
+	}
+
+	t.Run("create promotion", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectExec(regexp.QuoteMeta("INSERT INTO `promotion` (`type`,`product_id`,`match_quantity`,`promo_value`,`promo_product_id`,`min_total`,`promo_price`,`category`,`currency`,`priority`,`exclusive`,`stackable_with`,`starts_at`,`ends_at`,`updated_at`,`deleted_at`) VALUES (?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?)")).
+			WithArgs(entity.DiscountInPercent, 3, 3, 10, 0, "0.00", "0.00", "", "", 0, false, "[]", nil, nil, AnyTime{}, nil).
+			WillReturnResult(sqlmock.NewResult(5, 1))
+		mock.ExpectCommit()
+
+		promo := &entity.Promotion{Type: entity.DiscountInPercent, ProductID: 3, MatchQuantity: 3, PromoValue: 10}
+		err := repo.CreatePromotion(promo)
+		assert.Nil(t, err)
+		assert.Nil(t, mock.ExpectationsWereMet())
+		assert.Equal(t, int64(5), promo.ID)
+	})
+
+	t.Run("create product bundle with items", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectExec(regexp.QuoteMeta("INSERT INTO `promotion`")).
+			WithArgs(entity.ProductBundle, 0, 0, 0, 0, "0.00", "140.00", "", "USD", 0, false, "[]", nil, nil, AnyTime{}, nil).
+			WillReturnResult(sqlmock.NewResult(6, 1))
+		mock.ExpectExec(regexp.QuoteMeta("INSERT INTO `promotion_item` (`promotion_id`,`product_id`,`quantity`) VALUES (?,?,?),(?,?,?)")).
+			WithArgs(6, 1, 1, 6, 3, 1).
+			WillReturnResult(sqlmock.NewResult(1, 2))
+		mock.ExpectCommit()
+
+		promo := &entity.Promotion{Type: entity.ProductBundle, PromoPrice: 14000, Currency: "USD", Items: []*entity.PromotionItem{
+			{ProductID: 1, Quantity: 1},
+			{ProductID: 3, Quantity: 1},
+		}}
+		err := repo.CreatePromotion(promo)
+		assert.Nil(t, err)
+		assert.Nil(t, mock.ExpectationsWereMet())
+		assert.Equal(t, int64(6), promo.Items[1].PromotionID)
+	})
+
+	t.Run("create tiered price with tiers", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectExec(regexp.QuoteMeta("INSERT INTO `promotion`")).
+			WithArgs(entity.TieredPrice, 1, 0, 0, 0, "0.00", "0.00", "", "USD", 0, false, "[]", nil, nil, AnyTime{}, nil).
+			WillReturnResult(sqlmock.NewResult(7, 1))
+		mock.ExpectExec(regexp.QuoteMeta("INSERT INTO `promotion_tier` (`promotion_id`,`min_quantity`,`percent`,`unit_price`) VALUES (?,?,?,?),(?,?,?,?)")).
+			WithArgs(7, 10, 0, "45.00", 7, 50, 0, "40.00").
+			WillReturnResult(sqlmock.NewResult(1, 2))
+		mock.ExpectCommit()
+
+		promo := &entity.Promotion{Type: entity.TieredPrice, ProductID: 1, Currency: "USD", Tiers: []*entity.PromotionTier{
+			{MinQuantity: 10, UnitPrice: 4500},
+			{MinQuantity: 50, UnitPrice: 4000},
+		}}
+		err := repo.CreatePromotion(promo)
+		assert.Nil(t, err)
+		assert.Nil(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("update product bundle replaces items", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectExec(regexp.QuoteMeta("UPDATE `promotion` SET")).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectExec(regexp.QuoteMeta("DELETE FROM `promotion_item` WHERE promotion_id = ?")).
+			WithArgs(6).
+			WillReturnResult(sqlmock.NewResult(0, 2))
+		mock.ExpectExec(regexp.QuoteMeta("INSERT INTO `promotion_item` (`promotion_id`,`product_id`,`quantity`) VALUES (?,?,?),(?,?,?)")).
+			WithArgs(6, 1, 2, 6, 3, 1).
+			WillReturnResult(sqlmock.NewResult(3, 2))
+		mock.ExpectExec(regexp.QuoteMeta("DELETE FROM `promotion_tier` WHERE promotion_id = ?")).
+			WithArgs(6).
+			WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectCommit()
+
+		promo := &entity.Promotion{ID: 6, Type: entity.ProductBundle, PromoPrice: 18000, Currency: "USD", Items: []*entity.PromotionItem{
+			{ID: 1, ProductID: 1, Quantity: 2},
+			{ID: 2, ProductID: 3, Quantity: 1},
+		}}
+		err := repo.UpdatePromotion(promo)
+		assert.Nil(t, err)
+		assert.Nil(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("soft delete promotion", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectExec(regexp.QuoteMeta("UPDATE `promotion` SET `deleted_at`=? WHERE `promotion`.`id` = ? AND `promotion`.`deleted_at` IS NULL")).
+			WithArgs(AnyTime{}, 5).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectCommit()
+
+		err := repo.DeletePromotion(5)
+		assert.Nil(t, err)
+		assert.Nil(t, mock.ExpectationsWereMet())
+	})
+}
+
+func Test_GetCouponByCode(t *testing.T) {
+	// mock db
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("error: %s", err.Error())
+	}
+	defer db.Close()
+
+	// init repo
+	repo, err := initRepo(db, mock)
+	if err != nil {
+		t.Errorf("error initRepo: %s", err.Error())
+		return
+	}
+	dayCreated, _ := time.Parse("2006-01-02", "2023-05-16")
+
+	t.Run("positive, code is case insensitive", func(t *testing.T) {
+		rows := sqlmock.
+			NewRows([]string{"id", "code", "type", "amount", "percent", "min_total", "currency", "usage_limit", "per_customer_limit", "used_count", "updated_at", "deleted_at"}).
+			AddRow(1, "WELCOME10", 2, "0.00", 10, "100.00", "USD", 100, 1, 7, dayCreated, nil)
+		mock.
+			ExpectQuery(regexp.QuoteMeta("SELECT * FROM `coupon` WHERE code = ? AND `coupon`.`deleted_at` IS NULL LIMIT ?")).
+			WithArgs("WELCOME10", 1).
+			WillReturnRows(rows)
+
+		resp, err := repo.GetCouponByCode("welcome10")
+		assert.Nil(t, err)
+		assert.Equal(t, &entity.Coupon{
+			ID: 1, Code: "WELCOME10", Type: entity.CouponPercent, Percent: 10, MinTotal: 10000, Currency: "USD",
+			UsageLimit: 100, PerCustomerLimit: 1, UsedCount: 7, UpdatedAt: dayCreated,
 		}, resp)
 	})
+
+	t.Run("negative, not found", func(t *testing.T) {
+		mock.
+			ExpectQuery(regexp.QuoteMeta("SELECT * FROM `coupon` WHERE code = ? AND `coupon`.`deleted_at` IS NULL LIMIT ?")).
+			WithArgs("XXXXXX", 1).
+			WillReturnRows(sqlmock.NewRows([]string{"id"}))
+
+		resp, err := repo.GetCouponByCode("XXXXXX")
+		assert.Nil(t, err)
+		assert.Nil(t, resp)
+	})
 }
@@ -0,0 +1,24 @@
+package taxrepository
+
+import (
+	"github.com/gendutski/be-candidate-home-test/core/entity"
+	"github.com/gendutski/be-candidate-home-test/core/repository"
+	"gorm.io/gorm"
+)
+
+type repo struct {
+	db *gorm.DB
+}
+
+func New(db *gorm.DB) repository.TaxRepo {
+	return &repo{db}
+}
+
+func (r *repo) GetTaxRateByCategories(categories []string) ([]*entity.TaxRate, error) {
+	var result []*entity.TaxRate
+	err := r.db.Where("category in (?)", categories).Find(&result).Error
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
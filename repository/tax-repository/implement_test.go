@@ -0,0 +1,70 @@
+package taxrepository_test
+
+import (
+	"database/sql"
+	"regexp"
+	"testing"
+	"time"
+
+	sqlmock "github.com/DATA-DOG/go-sqlmock"
+	"github.com/gendutski/be-candidate-home-test/core/entity"
+	"github.com/gendutski/be-candidate-home-test/core/repository"
+	taxrepository "github.com/gendutski/be-candidate-home-test/repository/tax-repository"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/driver/mysql"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+	"gorm.io/gorm/schema"
+)
+
+func initRepo(db *sql.DB, mock sqlmock.Sqlmock) (repository.TaxRepo, error) {
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT VERSION()")).
+		WillReturnRows(sqlmock.NewRows([]string{"VERSION()"}).AddRow("5.7.25-log"))
+	gdb, err := gorm.Open(mysql.New(mysql.Config{
+		Conn: db,
+	}), &gorm.Config{
+		Logger: logger.Default.LogMode(logger.LogLevel(logger.Info)),
+		NamingStrategy: schema.NamingStrategy{
+			SingularTable: true,
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+	return taxrepository.New(gdb), nil
+}
+
+func Test_GetTaxRateByCategories(t *testing.T) {
+	// mock db
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("error: %s", err.Error())
+	}
+	defer db.Close()
+
+	// init repo
+	repo, err := initRepo(db, mock)
+	if err != nil {
+		t.Errorf("error initRepo: %s", err.Error())
+		return
+	}
+	dayCreated, _ := time.Parse("2006-01-02", "2023-05-16")
+
+	t.Run("positive", func(t *testing.T) {
+		rows := sqlmock.
+			NewRows([]string{"id", "category", "rate", "updated_at"}).
+			AddRow(1, "reduced", 500, dayCreated).
+			AddRow(2, "standard", 1100, dayCreated)
+		mock.
+			ExpectQuery(regexp.QuoteMeta("SELECT * FROM `tax_rate` WHERE category in (?,?)")).
+			WithArgs("reduced", "standard").
+			WillReturnRows(rows)
+
+		resp, err := repo.GetTaxRateByCategories([]string{"reduced", "standard"})
+		assert.Nil(t, err)
+		assert.Equal(t, []*entity.TaxRate{
+			{ID: 1, Category: "reduced", Rate: 500, UpdatedAt: dayCreated},
+			{ID: 2, Category: "standard", Rate: 1100, UpdatedAt: dayCreated},
+		}, resp)
+	})
+}